@@ -0,0 +1,316 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	tcrypto "github.com/tendermint/tendermint/crypto"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+)
+
+const (
+	// FileName is the name of the audit log in the home folder
+	FileName = "audit.log"
+	// DefaultLimit is how many recent entries are returned when the caller doesn't say
+	DefaultLimit = 100
+	// MaxLimit is how many recent entries are kept in memory, the callers asking for more get that many
+	MaxLimit = 1000
+	// the entry carries the keys of all the parties, it is far bigger than the default line limit of the scanner
+	maxEntrySize = 1024 * 1024
+)
+
+const (
+	OpKeygen  = "keygen"
+	OpKeySign = "keysign"
+	OpReshare = "reshare"
+)
+
+// Entry is a record of a keygen, keysign or resharing session, the hash covers all the fields but the hash and the
+// signature themselves, and it chains the entry to the previous one
+type Entry struct {
+	Seq         uint64    `json:"seq"`
+	SessionID   string    `json:"session_id"`
+	Operation   string    `json:"operation"`
+	PoolPubKey  string    `json:"pool_pub_key,omitempty"`
+	Parties     []string  `json:"parties"`
+	MessageHash string    `json:"message_hash,omitempty"` // hex of the sha256 of the message to sign
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Outcome     string    `json:"outcome"`
+	Error       string    `json:"error,omitempty"`
+	Blame       string    `json:"blame,omitempty"`
+	R           string    `json:"r,omitempty"`
+	S           string    `json:"s,omitempty"`
+	Node        string    `json:"node"` // pub key of the node writing the entry
+	PrevHash    string    `json:"prev_hash"`
+	Hash        string    `json:"hash"`
+	Signature   string    `json:"signature"` // base64 of the signature of the hash with the node key
+}
+
+func (e Entry) computeHash() (string, error) {
+	e.Hash = ""
+	e.Signature = ""
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return "", fmt.Errorf("fail to marshal the audit entry: %w", err)
+	}
+	h := sha256.Sum256(buf)
+	return hex.EncodeToString(h[:]), nil
+}
+
+// BlameSummary return the reason and the blamed nodes in one line
+func BlameSummary(b blame.Blame) string {
+	if len(b.FailReason) == 0 && len(b.BlameNodes) == 0 {
+		return ""
+	}
+	nodes := make([]string, len(b.BlameNodes))
+	for i, el := range b.BlameNodes {
+		nodes[i] = el.Pubkey
+	}
+	return fmt.Sprintf("%s: %s", b.FailReason, strings.Join(nodes, ","))
+}
+
+// Log is the append-only audit log, every entry is a json line signed with the node key
+type Log struct {
+	fileName string
+	privKey  tcrypto.PrivKey
+	node     string
+	lock     *sync.Mutex
+	lastSeq  uint64
+	lastHash string
+	recent   []Entry // the last entries of the log, the oldest first, so the callers never read the file
+}
+
+// NewLog open the audit log of the given file, the chain carries on from the last entry of the file. The entry torn
+// by a crash in the middle of the write is cut off the end of the file
+func NewLog(fileName string, privKey tcrypto.PrivKey, bech32Prefix string) (*Log, error) {
+	node, err := conversion.Bech32ifyPubKey(bech32Prefix, privKey.PubKey())
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the node pub key: %w", err)
+	}
+	if err := truncateTornEntry(fileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	l := &Log{
+		fileName: fileName,
+		privKey:  privKey,
+		node:     node,
+		lock:     &sync.Mutex{},
+	}
+	err = l.scan(func(e Entry) {
+		l.lastSeq = e.Seq
+		l.lastHash = e.Hash
+		l.keepRecent(e)
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return l, nil
+}
+
+// truncateTornEntry cut off the bytes after the last line break of the file, every entry is written along with its
+// line break in one go, so they are the part of the entry the node fails to write
+func truncateTornEntry(fileName string) error {
+	f, err := os.OpenFile(fileName, os.O_RDWR, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("fail to stat the audit log: %w", err)
+	}
+	size := fi.Size()
+	if size == 0 {
+		return nil
+	}
+	// the torn entry is shorter than a whole one, so the last line break is within the tail
+	tailSize := size
+	if tailSize > maxEntrySize+1 {
+		tailSize = maxEntrySize + 1
+	}
+	tail := make([]byte, tailSize)
+	if _, err := f.ReadAt(tail, size-tailSize); err != nil {
+		return fmt.Errorf("fail to read the audit log: %w", err)
+	}
+	if tail[len(tail)-1] == '\n' {
+		return nil
+	}
+	idx := bytes.LastIndexByte(tail, '\n')
+	if idx < 0 && tailSize < size {
+		return errors.New("the last entry of the audit log is too long")
+	}
+	validSize := size - tailSize + int64(idx) + 1
+	if err := f.Truncate(validSize); err != nil {
+		return fmt.Errorf("fail to truncate the torn entry of the audit log: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("fail to sync the audit log: %w", err)
+	}
+	log.Warn().Str("module", "audit").Str("file", fileName).Int64("bytes", size-validSize).
+		Msg("the last entry of the audit log is torn, it is truncated")
+	return nil
+}
+
+func (l *Log) scan(fn func(e Entry)) error {
+	f, err := os.Open(l.fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return readEntries(f, func(e Entry) error {
+		fn(e)
+		return nil
+	})
+}
+
+func readEntries(r io.Reader, fn func(e Entry) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxEntrySize)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("fail to unmarshal the audit entry at line %d: %w", line, err)
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("fail to read the audit log: %w", err)
+	}
+	return nil
+}
+
+// Append chain the given entry to the log, sign it and write it to the file
+func (l *Log) Append(e Entry) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	e.Seq = l.lastSeq + 1
+	e.Node = l.node
+	e.PrevHash = l.lastHash
+	e.StartTime = e.StartTime.UTC()
+	e.EndTime = e.EndTime.UTC()
+	hash, err := e.computeHash()
+	if err != nil {
+		return err
+	}
+	e.Hash = hash
+	sig, err := l.privKey.Sign([]byte(hash))
+	if err != nil {
+		return fmt.Errorf("fail to sign the audit entry: %w", err)
+	}
+	e.Signature = base64.StdEncoding.EncodeToString(sig)
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the audit entry: %w", err)
+	}
+	f, err := os.OpenFile(l.fileName, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("fail to open the audit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(buf, '\n')); err != nil {
+		return fmt.Errorf("fail to write the audit entry: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("fail to sync the audit log: %w", err)
+	}
+	l.lastSeq = e.Seq
+	l.lastHash = e.Hash
+	l.keepRecent(e)
+	return nil
+}
+
+// keepRecent add the given entry to the recent ones kept in memory, the lock has to be held
+func (l *Log) keepRecent(e Entry) {
+	l.recent = append(l.recent, e)
+	if len(l.recent) > MaxLimit {
+		l.recent = l.recent[len(l.recent)-MaxLimit:]
+	}
+}
+
+// Recent return the last entries of the log, the newest first, no more than MaxLimit of them. They are kept in
+// memory, so the caller doesn't hold up Append for as long as it takes to read the file
+func (l *Log) Recent(limit int) ([]Entry, error) {
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if limit > len(l.recent) {
+		limit = len(l.recent)
+	}
+	result := make([]Entry, limit)
+	for i := range result {
+		result[i] = l.recent[len(l.recent)-1-i]
+	}
+	return result, nil
+}
+
+// Verify check the hash chain and the signatures of the audit log read from the given reader, the entries have to be
+// written by the given node, it return how many entries are verified. The node pub key is required, the key embedded
+// in the entry proves nothing as anyone rewriting the log signs it with their own key
+func Verify(r io.Reader, nodePubKey string) (int, error) {
+	if len(nodePubKey) == 0 {
+		return 0, errors.New("the pub key of the node writing the log is required")
+	}
+	expectedNode, err := conversion.ParsePubKey(nodePubKey)
+	if err != nil {
+		return 0, fmt.Errorf("invalid node pub key: %w", err)
+	}
+	verified := 0
+	prevHash := ""
+	err = readEntries(r, func(e Entry) error {
+		if e.Seq != uint64(verified+1) {
+			return fmt.Errorf("entry %d: expect seq %d, got %d", verified+1, verified+1, e.Seq)
+		}
+		if e.PrevHash != prevHash {
+			return fmt.Errorf("entry %d: the chain is broken", e.Seq)
+		}
+		hash, err := e.computeHash()
+		if err != nil {
+			return err
+		}
+		if hash != e.Hash {
+			return fmt.Errorf("entry %d: the hash doesn't match the content", e.Seq)
+		}
+		pk, err := conversion.ParsePubKey(e.Node)
+		if err != nil {
+			return fmt.Errorf("entry %d: invalid node pub key: %w", e.Seq, err)
+		}
+		if !pk.Equals(expectedNode) {
+			return fmt.Errorf("entry %d: written by another node(%s)", e.Seq, e.Node)
+		}
+		sig, err := base64.StdEncoding.DecodeString(e.Signature)
+		if err != nil {
+			return fmt.Errorf("entry %d: invalid signature: %w", e.Seq, err)
+		}
+		if !pk.VerifyBytes([]byte(e.Hash), sig) {
+			return fmt.Errorf("entry %d: the signature is invalid", e.Seq)
+		}
+		prevHash = e.Hash
+		verified++
+		return nil
+	})
+	return verified, err
+}
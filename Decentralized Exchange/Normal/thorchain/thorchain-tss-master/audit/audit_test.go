@@ -0,0 +1,214 @@
+package audit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+)
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type AuditTestSuite struct {
+	folder string
+}
+
+var _ = Suite(&AuditTestSuite{})
+
+func (s *AuditTestSuite) SetUpTest(c *C) {
+	folder, err := ioutil.TempDir("", "audit")
+	c.Assert(err, IsNil)
+	s.folder = folder
+}
+
+func (s *AuditTestSuite) TearDownTest(c *C) {
+	c.Assert(os.RemoveAll(s.folder), IsNil)
+}
+
+func newTestEntry(session string) Entry {
+	return Entry{
+		SessionID:   session,
+		Operation:   OpKeySign,
+		PoolPubKey:  "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+		Parties:     []string{"a", "b", "c"},
+		MessageHash: "aabb",
+		StartTime:   time.Now().Add(-time.Second),
+		EndTime:     time.Now(),
+		Outcome:     "success",
+	}
+}
+
+func (s *AuditTestSuite) TestBlameSummary(c *C) {
+	c.Assert(BlameSummary(blame.Blame{}), Equals, "")
+	b := blame.NewBlame(blame.TssTimeout, []blame.Node{{Pubkey: "a"}, {Pubkey: "b"}})
+	c.Assert(BlameSummary(b), Equals, blame.TssTimeout+": a,b")
+}
+
+func (s *AuditTestSuite) TestAppendAndRecent(c *C) {
+	fileName := filepath.Join(s.folder, FileName)
+	priKey := secp256k1.GenPrivKey()
+	l, err := NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	entries, err := l.Recent(0)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 0)
+
+	c.Assert(l.Append(newTestEntry("1")), IsNil)
+	c.Assert(l.Append(newTestEntry("2")), IsNil)
+	// reopening the log carries on the chain
+	l, err = NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	c.Assert(l.Append(newTestEntry("3")), IsNil)
+
+	entries, err = l.Recent(2)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].SessionID, Equals, "3")
+	c.Assert(entries[0].Seq, Equals, uint64(3))
+	c.Assert(entries[0].PrevHash, Equals, entries[1].Hash)
+	c.Assert(entries[1].SessionID, Equals, "2")
+
+	node, err := conversion.Bech32ifyPubKey("thor", priKey.PubKey())
+	c.Assert(err, IsNil)
+	c.Assert(entries[0].Node, Equals, node)
+	f, err := os.Open(fileName)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	verified, err := Verify(f, node)
+	c.Assert(err, IsNil)
+	c.Assert(verified, Equals, 3)
+}
+
+func (s *AuditTestSuite) TestRecentInMemory(c *C) {
+	fileName := filepath.Join(s.folder, FileName)
+	l, err := NewLog(fileName, secp256k1.GenPrivKey(), "thor")
+	c.Assert(err, IsNil)
+	c.Assert(l.Append(newTestEntry("1")), IsNil)
+	// the recent entries don't come from the file
+	c.Assert(os.Remove(fileName), IsNil)
+	entries, err := l.Recent(0)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].SessionID, Equals, "1")
+
+	for i := uint64(2); i <= MaxLimit+10; i++ {
+		l.keepRecent(Entry{Seq: i})
+	}
+	c.Assert(l.recent, HasLen, MaxLimit)
+	entries, err = l.Recent(MaxLimit + 10)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, MaxLimit)
+	c.Assert(entries[0].Seq, Equals, uint64(MaxLimit+10))
+	c.Assert(entries[MaxLimit-1].Seq, Equals, uint64(11))
+}
+
+func (s *AuditTestSuite) TestVerify(c *C) {
+	fileName := filepath.Join(s.folder, FileName)
+	priKey := secp256k1.GenPrivKey()
+	l, err := NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	for _, el := range []string{"1", "2", "3"} {
+		c.Assert(l.Append(newTestEntry(el)), IsNil)
+	}
+	buf, err := ioutil.ReadFile(fileName)
+	c.Assert(err, IsNil)
+	lines := strings.Split(strings.TrimSpace(string(buf)), "\n")
+	c.Assert(lines, HasLen, 3)
+
+	node, err := conversion.Bech32ifyPubKey("thor", priKey.PubKey())
+	c.Assert(err, IsNil)
+	verified, err := Verify(bytes.NewReader(buf), node)
+	c.Assert(err, IsNil)
+	c.Assert(verified, Equals, 3)
+
+	// the node key is required
+	_, err = Verify(bytes.NewReader(buf), "")
+	c.Assert(err, NotNil)
+
+	// written by another node
+	otherNode, err := conversion.Bech32ifyPubKey("thor", secp256k1.GenPrivKey().PubKey())
+	c.Assert(err, IsNil)
+	_, err = Verify(bytes.NewReader(buf), otherNode)
+	c.Assert(err, NotNil)
+
+	// the content is modified
+	tampered := strings.Replace(string(buf), `"outcome":"success"`, `"outcome":"fail"`, 1)
+	verified, err = Verify(strings.NewReader(tampered), node)
+	c.Assert(err, ErrorMatches, "entry 1: the hash doesn't match the content")
+	c.Assert(verified, Equals, 0)
+
+	// an entry is removed
+	removed := lines[0] + "\n" + lines[2] + "\n"
+	verified, err = Verify(strings.NewReader(removed), node)
+	c.Assert(err, NotNil)
+	c.Assert(verified, Equals, 1)
+
+	// the entry is rewritten by another key
+	other, err := NewLog(filepath.Join(s.folder, "other.log"), secp256k1.GenPrivKey(), "thor")
+	c.Assert(err, IsNil)
+	c.Assert(other.Append(newTestEntry("1")), IsNil)
+	otherBuf, err := ioutil.ReadFile(filepath.Join(s.folder, "other.log"))
+	c.Assert(err, IsNil)
+	_, err = Verify(bytes.NewReader(append(otherBuf, []byte(lines[1]+"\n")...)), node)
+	c.Assert(err, ErrorMatches, "entry 1: written by another node.*")
+
+	// the whole log is rewritten and signed by another key, it only passes against that key
+	otherNode, err = conversion.Bech32ifyPubKey("thor", other.privKey.PubKey())
+	c.Assert(err, IsNil)
+	verified, err = Verify(bytes.NewReader(otherBuf), otherNode)
+	c.Assert(err, IsNil)
+	c.Assert(verified, Equals, 1)
+	_, err = Verify(bytes.NewReader(otherBuf), node)
+	c.Assert(err, NotNil)
+}
+
+func (s *AuditTestSuite) TestTornEntry(c *C) {
+	fileName := filepath.Join(s.folder, FileName)
+	priKey := secp256k1.GenPrivKey()
+	l, err := NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	c.Assert(l.Append(newTestEntry("1")), IsNil)
+	c.Assert(l.Append(newTestEntry("2")), IsNil)
+	buf, err := ioutil.ReadFile(fileName)
+	c.Assert(err, IsNil)
+
+	// the node crashes in the middle of writing the third entry
+	f, err := os.OpenFile(fileName, os.O_APPEND|os.O_WRONLY, 0600)
+	c.Assert(err, IsNil)
+	_, err = f.Write([]byte(`{"seq":3,"session_id":"3","oper`))
+	c.Assert(err, IsNil)
+	c.Assert(f.Close(), IsNil)
+
+	l, err = NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	repaired, err := ioutil.ReadFile(fileName)
+	c.Assert(err, IsNil)
+	c.Assert(repaired, DeepEquals, buf)
+	c.Assert(l.Append(newTestEntry("3")), IsNil)
+	node, err := conversion.Bech32ifyPubKey("thor", priKey.PubKey())
+	c.Assert(err, IsNil)
+	f, err = os.Open(fileName)
+	c.Assert(err, IsNil)
+	defer f.Close()
+	verified, err := Verify(f, node)
+	c.Assert(err, IsNil)
+	c.Assert(verified, Equals, 3)
+
+	// the first entry is torn
+	c.Assert(ioutil.WriteFile(fileName, []byte(`{"seq":1`), 0600), IsNil)
+	l, err = NewLog(fileName, priKey, "thor")
+	c.Assert(err, IsNil)
+	c.Assert(l.lastSeq, Equals, uint64(0))
+	fi, err := os.Stat(fileName)
+	c.Assert(err, IsNil)
+	c.Assert(fi.Size(), Equals, int64(0))
+}
@@ -19,9 +19,12 @@ type Manager struct {
 	PartyIDtoP2PID  map[string]peer.ID
 	lastMsgLocker   *sync.RWMutex
 	lastMsg         btss.Message
+	bech32Prefix    string
+	timeline        *Timeline
 }
 
-func NewBlameManager() *Manager {
+// NewBlameManager create a blame manager, which reports the node pub keys in bech32 with the given prefix
+func NewBlameManager(bech32Prefix string) *Manager {
 	return &Manager{
 		logger:          log.With().Str("module", "blame_manager").Logger(),
 		partyInfo:       nil,
@@ -31,6 +34,8 @@ func NewBlameManager() *Manager {
 		roundMgr:        NewTssRoundMgr(),
 		blame:           &Blame{},
 		lastMsgLocker:   &sync.RWMutex{},
+		bech32Prefix:    bech32Prefix,
+		timeline:        NewTimeline(),
 	}
 }
 
@@ -46,6 +51,11 @@ func (m *Manager) GetRoundMgr() *RoundMgr {
 	return m.roundMgr
 }
 
+// GetTimeline return the timeline of the session
+func (m *Manager) GetTimeline() *Timeline {
+	return m.timeline
+}
+
 func (m *Manager) SetLastMsg(lastMsg btss.Message) {
 	m.lastMsgLocker.Lock()
 	defer m.lastMsgLocker.Unlock()
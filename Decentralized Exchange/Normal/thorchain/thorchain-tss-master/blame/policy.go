@@ -35,7 +35,7 @@ func (m *Manager) tssTimeoutBlame(lastMessageType string, partyIDMap map[string]
 		blames = append(blames, el.(string))
 	}
 
-	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(blames, m.partyInfo.PartyIDMap)
+	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(blames, m.partyInfo.PartyIDMap, m.bech32Prefix)
 	if err != nil {
 		m.logger.Error().Err(err).Msg("fail to get the public keys of the blame node")
 		return nil, err
@@ -121,9 +121,34 @@ func (m *Manager) TssWrongShareBlame(wiredMsg *messages.WireMessage) (string, er
 		m.logger.Error().Msg("cannot find the blame node public key")
 		return "", errors.New("fail to find the share Owner")
 	}
-	pk, err := conversion.PartyIDtoPubKey(owner)
+	pk, err := conversion.PartyIDtoPubKey(owner, m.bech32Prefix)
 	if err != nil {
 		return "", err
 	}
 	return pk, nil
 }
+
+// this blame blames the nodes the local party is still waiting for when the timeout happens, it is used by the key
+// resharing, where the messages of a round do not come from every party
+func (m *Manager) GetWaitingForBlame(waitingFor []*btss.PartyID) ([]Node, error) {
+	var partyIDs []string
+	for _, el := range waitingFor {
+		partyIDs = append(partyIDs, el.Id)
+	}
+	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(partyIDs, m.partyInfo.PartyIDMap, m.bech32Prefix)
+	if err != nil {
+		m.logger.Error().Err(err).Msg("fail to get the blamed peers")
+		return nil, fmt.Errorf("fail to get the blamed peers %w", ErrTssTimeOut)
+	}
+	var blameNodes []Node
+	added := make(map[string]bool)
+	for _, el := range blamePubKeys {
+		// a node can be in both committees of the resharing
+		if added[el] {
+			continue
+		}
+		added[el] = true
+		blameNodes = append(blameNodes, NewNode(el, nil, nil))
+	}
+	return blameNodes, nil
+}
@@ -16,7 +16,7 @@ func (m *Manager) getBlamePubKeysInList(peers []string) ([]string, error) {
 
 	localPartyInfo := m.partyInfo
 	partyIDMap := localPartyInfo.PartyIDMap
-	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(partiesInList, partyIDMap)
+	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(partiesInList, partyIDMap, m.bech32Prefix)
 	if err != nil {
 		return nil, err
 	}
@@ -44,7 +44,7 @@ func (m *Manager) getBlamePubKeysNotInList(peers []string) ([]string, error) {
 	}
 
 	partyIDMap := m.partyInfo.PartyIDMap
-	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(partiesNotInList, partyIDMap)
+	blamePubKeys, err := conversion.AccPubKeysFromPartyIDs(partiesNotInList, partyIDMap, m.bech32Prefix)
 	if err != nil {
 		return nil, err
 	}
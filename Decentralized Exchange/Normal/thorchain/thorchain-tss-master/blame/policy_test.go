@@ -1,6 +1,7 @@
 package blame
 
 import (
+	"math/big"
 	"sort"
 	"testing"
 
@@ -33,7 +34,7 @@ type policyTestSuite struct {
 var _ = Suite(&policyTestSuite{})
 
 func (p *policyTestSuite) SetUpTest(c *C) {
-	p.blameMgr = NewBlameManager()
+	p.blameMgr = NewBlameManager(conversion.DefaultBech32Prefix)
 	conversion.SetupBech32Prefix()
 	p1, err := peer.Decode(testPeers[0])
 	c.Assert(err, IsNil)
@@ -114,3 +115,14 @@ func (p *policyTestSuite) TestTssWrongShareBlame(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(target, Equals, "thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j")
 }
+
+func (p *policyTestSuite) TestGetWaitingForBlame(c *C) {
+	pi := p.blameMgr.partyInfo
+	blames, err := p.blameMgr.GetWaitingForBlame([]*btss.PartyID{pi.PartyIDMap["1"], pi.PartyIDMap["1"]})
+	c.Assert(err, IsNil)
+	c.Assert(blames, HasLen, 1)
+	c.Assert(blames[0].Pubkey, Equals, "thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j")
+
+	_, err = p.blameMgr.GetWaitingForBlame([]*btss.PartyID{btss.NewPartyID("unknown", "", big.NewInt(1))})
+	c.Assert(err, NotNil)
+}
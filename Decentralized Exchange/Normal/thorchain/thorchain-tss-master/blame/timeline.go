@@ -0,0 +1,146 @@
+package blame
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	EventPartyFormed    = "party_formed"
+	EventLocalMessage   = "local_message"   // the local party produces the message of the round
+	EventPeerMessage    = "peer_message"    // the message of the peer arrives
+	EventPeerConfirmed  = "peer_confirmed"  // the hash of the broadcast message of the peer is confirmed by the others
+	EventShareRequested = "share_requested" // the message of the peer is requested as it doesn't match the majority
+)
+
+// TimelineEvent is what happens in the tss session, the peer is the p2p ID of the peer the event is about
+type TimelineEvent struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	Round string    `json:"round,omitempty"`
+	Peer  string    `json:"peer,omitempty"`
+}
+
+// RoundSummary tell how a round of the session goes, the times are the milliseconds since the start of the session
+type RoundSummary struct {
+	Round      string `json:"round"`
+	LocalMs    int64  `json:"local_ms,omitempty"`
+	Received   int    `json:"received"`
+	Confirmed  int    `json:"confirmed"`
+	LastPeerMs int64  `json:"last_peer_ms,omitempty"`
+	LastPeer   string `json:"last_peer,omitempty"` // the peer whose message of the round arrives last
+}
+
+// TimelineSummary tell where the time of the session goes, the times are the milliseconds since the start of the
+// session
+type TimelineSummary struct {
+	SessionID     string         `json:"session_id"`
+	PartyFormedMs int64          `json:"party_formed_ms,omitempty"`
+	DurationMs    int64          `json:"duration_ms"`
+	ShareRequests int            `json:"share_requests,omitempty"`
+	Rounds        []RoundSummary `json:"rounds,omitempty"`
+}
+
+// Timeline keeps the events of a tss session in the order they happen
+type Timeline struct {
+	lock     *sync.Mutex
+	start    time.Time
+	events   []TimelineEvent
+	listener func(TimelineEvent)
+	now      func() time.Time
+}
+
+// NewTimeline create a new timeline, the session starts now
+func NewTimeline() *Timeline {
+	return newTimeline(time.Now)
+}
+
+func newTimeline(now func() time.Time) *Timeline {
+	return &Timeline{
+		lock:  &sync.Mutex{},
+		start: now(),
+		now:   now,
+	}
+}
+
+// Add record the given event of the given round and peer, both of them can be empty
+func (t *Timeline) Add(event, round, peer string) {
+	t.lock.Lock()
+	el := TimelineEvent{
+		Time:  t.now(),
+		Event: event,
+		Round: round,
+		Peer:  peer,
+	}
+	t.events = append(t.events, el)
+	listener := t.listener
+	t.lock.Unlock()
+	if listener != nil {
+		listener(el)
+	}
+}
+
+// SetListener set the function that is called with every event added from now on, it should not block as it is
+// called by the goroutines processing the messages
+func (t *Timeline) SetListener(listener func(TimelineEvent)) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.listener = listener
+}
+
+// GetStart return when the session starts
+func (t *Timeline) GetStart() time.Time {
+	return t.start
+}
+
+// GetEvents return a copy of the events recorded so far
+func (t *Timeline) GetEvents() []TimelineEvent {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return append([]TimelineEvent{}, t.events...)
+}
+
+// Summary summarize the events recorded so far round by round, the rounds are in the order they are first seen
+func (t *Timeline) Summary(sessionID string) TimelineSummary {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	summary := TimelineSummary{
+		SessionID:  sessionID,
+		DurationMs: t.sinceStart(t.now()),
+	}
+	rounds := make(map[string]int)
+	getRound := func(round string) *RoundSummary {
+		idx, ok := rounds[round]
+		if !ok {
+			idx = len(summary.Rounds)
+			rounds[round] = idx
+			summary.Rounds = append(summary.Rounds, RoundSummary{Round: round})
+		}
+		return &summary.Rounds[idx]
+	}
+	for _, el := range t.events {
+		switch el.Event {
+		case EventPartyFormed:
+			summary.PartyFormedMs = t.sinceStart(el.Time)
+		case EventShareRequested:
+			summary.ShareRequests++
+		case EventLocalMessage:
+			r := getRound(el.Round)
+			if r.LocalMs == 0 {
+				r.LocalMs = t.sinceStart(el.Time)
+			}
+		case EventPeerMessage:
+			r := getRound(el.Round)
+			r.Received++
+			r.LastPeerMs = t.sinceStart(el.Time)
+			r.LastPeer = el.Peer
+		case EventPeerConfirmed:
+			getRound(el.Round).Confirmed++
+		}
+	}
+	return summary
+}
+
+func (t *Timeline) sinceStart(at time.Time) int64 {
+	return int64(at.Sub(t.start) / time.Millisecond)
+}
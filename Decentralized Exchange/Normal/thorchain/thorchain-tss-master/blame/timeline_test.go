@@ -0,0 +1,53 @@
+package blame
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+type TimelineTestSuite struct{}
+
+var _ = Suite(&TimelineTestSuite{})
+
+func (TimelineTestSuite) TestTimelineSummary(c *C) {
+	now := time.Now()
+	timeline := newTimeline(func() time.Time { return now })
+	now = now.Add(time.Millisecond * 100)
+	timeline.Add(EventPartyFormed, "", "")
+	now = now.Add(time.Millisecond * 100)
+	timeline.Add(EventLocalMessage, "round1", "")
+	timeline.Add(EventPeerMessage, "round1", "peer1")
+	now = now.Add(time.Millisecond * 300)
+	timeline.Add(EventPeerMessage, "round1", "peer2")
+	timeline.Add(EventPeerConfirmed, "round1", "peer1")
+	timeline.Add(EventShareRequested, "round1", "peer2")
+	now = now.Add(time.Millisecond * 100)
+	timeline.Add(EventPeerMessage, "round2", "peer1")
+	timeline.Add(EventLocalMessage, "round2", "")
+	now = now.Add(time.Millisecond * 400)
+
+	summary := timeline.Summary("session")
+	c.Assert(summary.SessionID, Equals, "session")
+	c.Assert(summary.PartyFormedMs, Equals, int64(100))
+	c.Assert(summary.DurationMs, Equals, int64(1000))
+	c.Assert(summary.ShareRequests, Equals, 1)
+	c.Assert(summary.Rounds, DeepEquals, []RoundSummary{
+		{
+			Round:      "round1",
+			LocalMs:    200,
+			Received:   2,
+			Confirmed:  1,
+			LastPeerMs: 500,
+			LastPeer:   "peer2",
+		},
+		{
+			Round:      "round2",
+			LocalMs:    600,
+			Received:   1,
+			LastPeerMs: 600,
+			LastPeer:   "peer1",
+		},
+	})
+	c.Assert(timeline.GetEvents(), HasLen, 8)
+}
@@ -0,0 +1,402 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/yaml.v2"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+)
+
+// envPrefix is the prefix of the environment variables that override the config, the variable of a field is the prefix
+// followed by the upper case path of the field, e.g. TSS_P2P_PORT
+const envPrefix = "TSS"
+
+// redacted replaces the secrets when the config is printed
+const redacted = "<redacted>"
+
+const configTemplateHeader = `# config of the tss service
+#
+# every field can be overridden by the environment variable named TSS_ followed by the upper case path of the field,
+# e.g. TSS_P2P_PORT or TSS_SECURITY_AUTH_TOKEN, the lists are separated by commas. The flags given on the command line
+# override both the file and the environment variables. The durations are written like 30s, 5m or 1h.
+`
+
+// Duration is a time.Duration written like 30s in the config file
+type Duration time.Duration
+
+// MarshalYAML implement yaml.Marshaler
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+// UnmarshalYAML implement yaml.Unmarshaler
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var value string
+	if err := unmarshal(&value); err != nil {
+		return err
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// Config is everything the tss service is configured with, the defaults are overridden by the config file, then by
+// the environment variables and at last by the flags given on the command line
+type Config struct {
+	Log      LogConfig      `yaml:"log"`
+	HTTP     HTTPConfig     `yaml:"http"`
+	Security SecurityConfig `yaml:"security"`
+	TSS      TssConfig      `yaml:"tss"`
+	P2P      P2PConfig      `yaml:"p2p"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Key      KeyConfig      `yaml:"key"`
+}
+
+// LogConfig is how the service logs
+type LogConfig struct {
+	Level  string `yaml:"level"`
+	Pretty bool   `yaml:"pretty"` // unstructured prettified logging, useful for local debugging
+}
+
+// HTTPConfig is where the apis are served
+type HTTPConfig struct {
+	Addr     string `yaml:"addr"`      // the http api is only served on the unix socket if it is empty
+	GrpcAddr string `yaml:"grpc_addr"` // the grpc server is disabled if it is empty
+}
+
+// TssConfig is common.TssConfig in the config file
+type TssConfig struct {
+	PartyTimeout        Duration `yaml:"party_timeout"`
+	KeyGenTimeout       Duration `yaml:"keygen_timeout"`
+	KeySignTimeout      Duration `yaml:"keysign_timeout"`
+	PreParamTimeout     Duration `yaml:"pre_param_timeout"`
+	PreParamPoolSize    int      `yaml:"pre_param_pool_size"`
+	PreParamConcurrency int      `yaml:"pre_param_concurrency"`
+	JobRetention        Duration `yaml:"job_retention"`
+	DrainTimeout        Duration `yaml:"drain_timeout"`
+	Bech32Prefix        string   `yaml:"bech32_prefix"`
+	KeySignPolicyFile   string   `yaml:"keysign_policy_file"`
+}
+
+// P2PConfig is p2p.Config in the config file
+type P2PConfig struct {
+	Rendezvous     string   `yaml:"rendezvous"`
+	Port           int      `yaml:"port"`
+	ExternalIP     string   `yaml:"external_ip"`
+	BootstrapPeers []string `yaml:"bootstrap_peers"` // the multiaddresses of the peers
+}
+
+// StorageConfig is where the service keeps its files
+type StorageConfig struct {
+	Home string `yaml:"home"` // the key shares, the address book and the audit log are kept in this folder
+}
+
+// KeyConfig is where the node key comes from, it is read from stdin when no keystore is given
+type KeyConfig struct {
+	Keystore       string `yaml:"keystore"`        // the encrypted keystore of the node key
+	PassphraseFile string `yaml:"passphrase_file"` // the passphrase is prompted if neither it nor its file is given
+	Passphrase     string `yaml:"passphrase"`      // the passphrase of the keystore, it can't be set along with the file
+}
+
+// DefaultConfig return the config used when nothing is given
+func DefaultConfig() Config {
+	return Config{
+		Log: LogConfig{
+			Level: "info",
+		},
+		HTTP: HTTPConfig{
+			Addr:     "127.0.0.1:8080",
+			GrpcAddr: "127.0.0.1:8081",
+		},
+		Security: SecurityConfig{
+			UnixSocketMode: defaultUnixSocketMode,
+		},
+		TSS: TssConfig{
+			PartyTimeout:        Duration(10 * time.Second),
+			KeyGenTimeout:       Duration(30 * time.Second),
+			KeySignTimeout:      Duration(30 * time.Second),
+			PreParamTimeout:     Duration(5 * time.Minute),
+			PreParamPoolSize:    2,
+			PreParamConcurrency: 1,
+			JobRetention:        Duration(time.Hour),
+			DrainTimeout:        Duration(time.Minute),
+			Bech32Prefix:        conversion.DefaultBech32Prefix,
+		},
+		P2P: P2PConfig{
+			Rendezvous: "Asgard",
+			Port:       6668,
+		},
+	}
+}
+
+// stringList is a flag that can be given multiple times, every value is appended to the list
+type stringList []string
+
+// String implement flag.Value
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+// Set implement flag.Value
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// options are the flags that are not part of the config
+type options struct {
+	help               bool
+	configFile         string
+	printDefaultConfig bool
+}
+
+// newFlagSet return the flags that set the given config, the current values of the config are the defaults
+func newFlagSet(conf *Config, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet("tss", flag.ContinueOnError)
+	fs.BoolVar(&opts.help, "h", false, "Display Help")
+	fs.StringVar(&opts.configFile, "config", "", "yaml config file, the environment variables and the flags override it")
+	fs.BoolVar(&opts.printDefaultConfig, "print-default-config", false, "print the template of the config file and exit")
+
+	fs.StringVar(&conf.Log.Level, "loglevel", conf.Log.Level, "Log Level")
+	fs.BoolVar(&conf.Log.Pretty, "pretty-log", conf.Log.Pretty, "Enables unstructured prettified logging. This is useful for local debugging")
+	fs.StringVar(&conf.Storage.Home, "home", conf.Storage.Home, "home folder to store the keygen state file")
+
+	fs.StringVar(&conf.HTTP.Addr, "tss-port", conf.HTTP.Addr, "tss port")
+	fs.StringVar(&conf.HTTP.GrpcAddr, "grpc-port", conf.HTTP.GrpcAddr, "tss grpc port, the grpc server is disabled if it is empty")
+	fs.StringVar(&conf.Security.TLSCertFile, "tls-cert", conf.Security.TLSCertFile, "TLS certificate of the http api, it is served over plain http if it is empty")
+	fs.StringVar(&conf.Security.TLSKeyFile, "tls-key", conf.Security.TLSKeyFile, "TLS private key of the http api")
+	fs.StringVar(&conf.Security.ClientCAFile, "tls-client-ca", conf.Security.ClientCAFile, "CA of the client certificates, the http api requires the client certificate if it is set")
+	fs.StringVar(&conf.Security.AuthTokenFile, "auth-token-file", conf.Security.AuthTokenFile, "file of the bearer token the http api requires")
+	fs.StringVar(&conf.Security.HMACKeyFile, "hmac-key-file", conf.Security.HMACKeyFile, "file of the key the requests to the http api are signed with")
+	fs.StringVar(&conf.Security.UnixSocket, "unix-socket", conf.Security.UnixSocket, "unix socket the http api is served on as well, set -tss-port to empty to only serve on it")
+	fs.StringVar(&conf.Security.UnixSocketMode, "unix-socket-mode", conf.Security.UnixSocketMode, "octal permissions of the unix socket")
+
+	fs.DurationVar((*time.Duration)(&conf.TSS.PartyTimeout), "partytimeout", time.Duration(conf.TSS.PartyTimeout), "how long to wait for the party to form")
+	fs.DurationVar((*time.Duration)(&conf.TSS.KeyGenTimeout), "gentimeout", time.Duration(conf.TSS.KeyGenTimeout), "keygen timeout")
+	fs.DurationVar((*time.Duration)(&conf.TSS.KeySignTimeout), "signtimeout", time.Duration(conf.TSS.KeySignTimeout), "keysign timeout")
+	fs.DurationVar((*time.Duration)(&conf.TSS.PreParamTimeout), "preparamtimeout", time.Duration(conf.TSS.PreParamTimeout), "pre-parameter generation timeout")
+	fs.IntVar(&conf.TSS.PreParamPoolSize, "preparampoolsize", conf.TSS.PreParamPoolSize, "how many pre-parameters are kept ready for keygen")
+	fs.IntVar(&conf.TSS.PreParamConcurrency, "preparamcpu", conf.TSS.PreParamConcurrency, "how many CPU cores can be used to generate pre-parameters")
+	fs.DurationVar((*time.Duration)(&conf.TSS.JobRetention), "jobretention", time.Duration(conf.TSS.JobRetention), "how long the finished asynchronous jobs are kept")
+	fs.DurationVar((*time.Duration)(&conf.TSS.DrainTimeout), "draintimeout", time.Duration(conf.TSS.DrainTimeout), "how long the sessions in flight are given to finish when the server stops")
+	fs.StringVar(&conf.TSS.KeySignPolicyFile, "keysign-policy", conf.TSS.KeySignPolicyFile, "json file of the rules the keysign has to follow, the node joins any keysign if it is empty")
+	fs.StringVar(&conf.TSS.Bech32Prefix, "bech32-prefix", conf.TSS.Bech32Prefix, "account prefix of the bech32 pub keys, the pub key prefix is the account prefix plus pub")
+
+	fs.StringVar(&conf.P2P.Rendezvous, "rendezvous", conf.P2P.Rendezvous,
+		"Unique string to identify group of nodes. Share this with your friends to let them connect with you")
+	fs.IntVar(&conf.P2P.Port, "p2p-port", conf.P2P.Port, "listening port local")
+	fs.StringVar(&conf.P2P.ExternalIP, "external-ip", conf.P2P.ExternalIP, "external IP of this node")
+	fs.Var((*stringList)(&conf.P2P.BootstrapPeers), "peer", "Adds a peer multiaddress to the bootstrap list")
+
+	fs.StringVar(&conf.Key.Keystore, "keystore", conf.Key.Keystore, "encrypted keystore of the node key, the key is read from stdin if it is empty")
+	fs.StringVar(&conf.Key.PassphraseFile, "keystore-passphrase-file", conf.Key.PassphraseFile, "file of the keystore passphrase, it is prompted if neither the file nor TSS_KEY_PASSPHRASE is given")
+	return fs
+}
+
+// loadConfig return the config built from the defaults, the config file, the given environment variables and the
+// given flags. The flags are parsed twice, first to find the config file, and then on top of the config file and the
+// environment variables, so only the flags given on the command line override them
+func loadConfig(args []string, lookupEnv func(string) (string, bool)) (Config, options, error) {
+	var opts options
+	probe := DefaultConfig()
+	probeFlags := newFlagSet(&probe, &opts)
+	if err := probeFlags.Parse(args); err != nil {
+		return Config{}, opts, err
+	}
+	conf := DefaultConfig()
+	if len(opts.configFile) != 0 {
+		buf, err := ioutil.ReadFile(opts.configFile)
+		if err != nil {
+			return Config{}, opts, fmt.Errorf("fail to read the config file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(buf, &conf); err != nil {
+			return Config{}, opts, fmt.Errorf("fail to parse the config file: %w", err)
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(&conf).Elem(), envPrefix, lookupEnv); err != nil {
+		return Config{}, opts, err
+	}
+	fs := newFlagSet(&conf, &opts)
+	fs.SetOutput(ioutil.Discard)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, opts, err
+	}
+	return conf, opts, nil
+}
+
+// applyEnv override the fields of the given struct with the environment variables named after the yaml tags
+func applyEnv(v reflect.Value, prefix string, lookupEnv func(string) (string, bool)) error {
+	for i := 0; i < v.NumField(); i++ {
+		tag := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+		if len(tag) == 0 || tag == "-" {
+			continue
+		}
+		name := prefix + "_" + strings.ToUpper(tag)
+		field := v.Field(i)
+		if field.Kind() == reflect.Struct {
+			if err := applyEnv(field, name, lookupEnv); err != nil {
+				return err
+			}
+			continue
+		}
+		value, ok := lookupEnv(name)
+		if !ok {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Slice:
+			var items []string
+			for _, el := range strings.Split(value, ",") {
+				if el = strings.TrimSpace(el); len(el) != 0 {
+					items = append(items, el)
+				}
+			}
+			field.Set(reflect.ValueOf(items))
+		default:
+			if err := yaml.UnmarshalStrict([]byte(value), field.Addr().Interface()); err != nil {
+				return fmt.Errorf("invalid %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate return an error when the config can't be used
+func (c Config) Validate() error {
+	if _, err := zerolog.ParseLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("invalid log level(%s): %w", c.Log.Level, err)
+	}
+	if len(c.HTTP.Addr) == 0 && len(c.Security.UnixSocket) == 0 {
+		return errors.New("neither the http address nor the unix socket is given")
+	}
+	if (len(c.Security.TLSCertFile) == 0) != (len(c.Security.TLSKeyFile) == 0) {
+		return errors.New("the TLS certificate and its key have to be given together")
+	}
+	if len(c.Security.ClientCAFile) != 0 && len(c.Security.TLSCertFile) == 0 {
+		return errors.New("client CA is set without the TLS certificate")
+	}
+	if len(c.Security.AuthToken) != 0 && len(c.Security.AuthTokenFile) != 0 {
+		return errors.New("the auth token and its file are both set")
+	}
+	if len(c.Security.HMACKey) != 0 && len(c.Security.HMACKeyFile) != 0 {
+		return errors.New("the HMAC key and its file are both set")
+	}
+	if len(c.Security.UnixSocket) != 0 {
+		if _, err := strconv.ParseUint(c.Security.UnixSocketMode, 8, 32); err != nil {
+			return fmt.Errorf("invalid unix socket mode(%s): %w", c.Security.UnixSocketMode, err)
+		}
+	}
+	for name, value := range map[string]Duration{
+		"party timeout":         c.TSS.PartyTimeout,
+		"keygen timeout":        c.TSS.KeyGenTimeout,
+		"keysign timeout":       c.TSS.KeySignTimeout,
+		"pre-parameter timeout": c.TSS.PreParamTimeout,
+		"job retention":         c.TSS.JobRetention,
+		"drain timeout":         c.TSS.DrainTimeout,
+	} {
+		if value <= 0 {
+			return fmt.Errorf("%s should be positive", name)
+		}
+	}
+	if len(c.Key.Passphrase) != 0 && len(c.Key.PassphraseFile) != 0 {
+		return errors.New("the keystore passphrase and its file are both set")
+	}
+	if len(c.Key.Keystore) == 0 && (len(c.Key.Passphrase) != 0 || len(c.Key.PassphraseFile) != 0) {
+		return errors.New("the keystore passphrase is set without the keystore")
+	}
+	if c.TSS.PreParamConcurrency <= 0 {
+		return errors.New("pre-parameter concurrency should be positive")
+	}
+	// the keygen has no pre-parameters to use without the pool
+	if c.TSS.PreParamPoolSize <= 0 {
+		return errors.New("pre-parameter pool size should be positive")
+	}
+	if len(c.TSS.Bech32Prefix) == 0 {
+		return errors.New("bech32 prefix is empty")
+	}
+	if len(c.P2P.Rendezvous) == 0 {
+		return errors.New("rendezvous is empty")
+	}
+	if c.P2P.Port <= 0 || c.P2P.Port > 65535 {
+		return fmt.Errorf("invalid p2p port(%d)", c.P2P.Port)
+	}
+	if _, err := c.P2P.toP2PConfig(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// toTssConfig return the common.TssConfig of the config
+func (c TssConfig) toTssConfig() common.TssConfig {
+	return common.TssConfig{
+		PartyTimeout:        time.Duration(c.PartyTimeout),
+		KeyGenTimeout:       time.Duration(c.KeyGenTimeout),
+		KeySignTimeout:      time.Duration(c.KeySignTimeout),
+		PreParamTimeout:     time.Duration(c.PreParamTimeout),
+		PreParamPoolSize:    c.PreParamPoolSize,
+		PreParamConcurrency: c.PreParamConcurrency,
+		JobRetention:        time.Duration(c.JobRetention),
+		DrainTimeout:        time.Duration(c.DrainTimeout),
+		Bech32Prefix:        c.Bech32Prefix,
+		KeySignPolicyFile:   c.KeySignPolicyFile,
+	}
+}
+
+// toP2PConfig return the p2p.Config of the config
+func (c P2PConfig) toP2PConfig() (p2p.Config, error) {
+	p2pConf := p2p.Config{
+		RendezvousString: c.Rendezvous,
+		Port:             c.Port,
+		ExternalIP:       c.ExternalIP,
+	}
+	for _, el := range c.BootstrapPeers {
+		if err := p2pConf.BootstrapPeers.Set(el); err != nil {
+			return p2p.Config{}, fmt.Errorf("invalid bootstrap peer(%s): %w", el, err)
+		}
+	}
+	return p2pConf, nil
+}
+
+// Redacted return a copy of the config with the secrets replaced, so it can be printed
+func (c Config) Redacted() Config {
+	if len(c.Security.AuthToken) != 0 {
+		c.Security.AuthToken = redacted
+	}
+	if len(c.Security.HMACKey) != 0 {
+		c.Security.HMACKey = redacted
+	}
+	if len(c.Key.Passphrase) != 0 {
+		c.Key.Passphrase = redacted
+	}
+	return c
+}
+
+// String return the config in yaml with the secrets redacted
+func (c Config) String() string {
+	buf, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return fmt.Sprintf("fail to marshal the config: %s", err)
+	}
+	return string(buf)
+}
+
+// defaultConfigTemplate return the template of the config file, which is the default config
+func defaultConfigTemplate() string {
+	return configTemplateHeader + DefaultConfig().String()
+}
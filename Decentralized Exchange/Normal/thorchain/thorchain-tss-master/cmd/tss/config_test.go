@@ -0,0 +1,147 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/yaml.v2"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+type ConfigTestSuite struct{}
+
+var _ = Suite(&ConfigTestSuite{})
+
+func noEnv(string) (string, bool) {
+	return "", false
+}
+
+func (s *ConfigTestSuite) TestDefaultConfig(c *C) {
+	conf, opts, err := loadConfig(nil, noEnv)
+	c.Assert(err, IsNil)
+	c.Assert(opts.configFile, Equals, "")
+	c.Assert(conf, DeepEquals, DefaultConfig())
+	c.Assert(conf.Validate(), IsNil)
+
+	// the template can be loaded back
+	var template Config
+	c.Assert(yaml.UnmarshalStrict([]byte(defaultConfigTemplate()), &template), IsNil)
+	c.Assert(template.String(), Equals, DefaultConfig().String())
+	c.Assert(strings.Contains(defaultConfigTemplate(), "keygen_timeout: 30s"), Equals, true)
+}
+
+func (s *ConfigTestSuite) TestLoadConfig(c *C) {
+	file := filepath.Join(c.MkDir(), "tss.yaml")
+	c.Assert(ioutil.WriteFile(file, []byte(`
+log:
+  level: debug
+tss:
+  party_timeout: 20s
+  keygen_timeout: 1m
+p2p:
+  port: 7000
+  bootstrap_peers:
+    - /ip4/127.0.0.1/tcp/6668/ipfs/16Uiu2HAm4TmEzUqy3q3Dv7HvdoSboHk5sFj2FH3npiN5vDbJC6gh
+`), 0o600), IsNil)
+	env := map[string]string{
+		"TSS_TSS_KEYGEN_TIMEOUT":  "2m",
+		"TSS_P2P_PORT":            "7001",
+		"TSS_SECURITY_AUTH_TOKEN": "secret",
+		"TSS_LOG_PRETTY":          "true",
+	}
+	lookupEnv := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+	conf, opts, err := loadConfig([]string{"-config", file, "-p2p-port", "7002", "-peer", "/ip4/127.0.0.1/tcp/6669"}, lookupEnv)
+	c.Assert(err, IsNil)
+	c.Assert(opts.configFile, Equals, file)
+	c.Assert(conf.Validate(), IsNil)
+	// from the file
+	c.Assert(conf.Log.Level, Equals, "debug")
+	c.Assert(conf.TSS.PartyTimeout, Equals, Duration(20*time.Second))
+	// the environment variables override the file
+	c.Assert(conf.TSS.KeyGenTimeout, Equals, Duration(2*time.Minute))
+	c.Assert(conf.Security.AuthToken, Equals, "secret")
+	c.Assert(conf.Log.Pretty, Equals, true)
+	// the flags override the environment variables
+	c.Assert(conf.P2P.Port, Equals, 7002)
+	c.Assert(conf.P2P.BootstrapPeers, HasLen, 2)
+	// the defaults are kept
+	c.Assert(conf.TSS.KeySignTimeout, Equals, Duration(30*time.Second))
+	c.Assert(conf.HTTP.Addr, Equals, "127.0.0.1:8080")
+
+	// the secrets are not printed
+	c.Assert(strings.Contains(conf.String(), "secret"), Equals, false)
+	c.Assert(conf.Redacted().Security.AuthToken, Equals, redacted)
+	c.Assert(conf.Security.AuthToken, Equals, "secret")
+	conf.Key.Passphrase = "secret"
+	c.Assert(strings.Contains(conf.String(), "secret"), Equals, false)
+
+	// the typo in the config file is an error
+	c.Assert(ioutil.WriteFile(file, []byte("tss:\n  keygen_timeuot: 1m\n"), 0o600), IsNil)
+	_, _, err = loadConfig([]string{"-config", file}, noEnv)
+	c.Assert(err, NotNil)
+	env["TSS_P2P_PORT"] = "whatever"
+	_, _, err = loadConfig(nil, lookupEnv)
+	c.Assert(err, NotNil)
+}
+
+func (s *ConfigTestSuite) TestEveryTssConfigField(c *C) {
+	// every field of common.TssConfig can be set from the config file
+	conf := TssConfig{}
+	v := reflect.ValueOf(&conf).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		switch v.Field(i).Kind() {
+		case reflect.String:
+			v.Field(i).SetString("whatever")
+		default:
+			v.Field(i).SetInt(1)
+		}
+	}
+	tssConf := reflect.ValueOf(conf.toTssConfig())
+	c.Assert(tssConf.NumField(), Equals, v.NumField())
+	for i := 0; i < tssConf.NumField(); i++ {
+		c.Assert(tssConf.Field(i).IsZero(), Equals, false, Commentf("field: %s", reflect.TypeOf(common.TssConfig{}).Field(i).Name))
+	}
+}
+
+func (s *ConfigTestSuite) TestValidate(c *C) {
+	for _, update := range []func(conf *Config){
+		func(conf *Config) { conf.Log.Level = "whatever" },
+		func(conf *Config) { conf.HTTP.Addr = "" },
+		func(conf *Config) { conf.Security.TLSCertFile = "server.crt" },
+		func(conf *Config) { conf.Security.ClientCAFile = "ca.crt" },
+		func(conf *Config) {
+			conf.Security.AuthToken = "secret"
+			conf.Security.AuthTokenFile = "token"
+		},
+		func(conf *Config) {
+			conf.Security.UnixSocket = "tss.sock"
+			conf.Security.UnixSocketMode = "whatever"
+		},
+		func(conf *Config) { conf.TSS.KeyGenTimeout = 0 },
+		func(conf *Config) { conf.TSS.PreParamConcurrency = 0 },
+		func(conf *Config) { conf.TSS.PreParamPoolSize = -1 },
+		func(conf *Config) { conf.TSS.PreParamPoolSize = 0 },
+		func(conf *Config) { conf.TSS.Bech32Prefix = "" },
+		func(conf *Config) { conf.P2P.Rendezvous = "" },
+		func(conf *Config) { conf.P2P.Port = 70000 },
+		func(conf *Config) { conf.P2P.BootstrapPeers = []string{"whatever"} },
+		func(conf *Config) { conf.Key.PassphraseFile = "passphrase" },
+		func(conf *Config) {
+			conf.Key.Keystore = "keystore.json"
+			conf.Key.Passphrase = "secret"
+			conf.Key.PassphraseFile = "passphrase"
+		},
+	} {
+		conf := DefaultConfig()
+		update(&conf)
+		c.Assert(conf.Validate(), NotNil)
+	}
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+)
+
+const (
+	// HeaderTimestamp is the unix time in seconds the request is signed at
+	HeaderTimestamp = "X-Tss-Timestamp"
+	// HeaderSignature is the hex of the HMAC-SHA256 of the request, see SignRequest
+	HeaderSignature = "X-Tss-Signature"
+	// HeaderNonce is the random value that makes every signed request unique, the same signature is accepted once
+	HeaderNonce = "X-Tss-Nonce"
+)
+
+const (
+	// maxSignatureAge is how far the timestamp of the signed request can be from now, the request is not replayed
+	// later than that
+	maxSignatureAge = 5 * time.Minute
+	// maxSignedBodySize is the largest body of the signed request, the body has to be read to check the signature
+	maxSignedBodySize = 10 << 20
+	// maxNonceSize is the longest nonce of the signed request
+	maxNonceSize = 64
+	// defaultUnixSocketMode only let the owner of the tss process call the api through the unix socket
+	defaultUnixSocketMode = "0600"
+)
+
+// SecurityConfig is how the http api is protected, all of it is optional. When the token and the HMAC key are both
+// set, the request only needs either of them. The token and the HMAC key are either read from the files or given
+// straight away, the latter is meant for the environment variables. The requests through the unix socket are
+// protected by the permissions of the socket file rather than the client certificate
+type SecurityConfig struct {
+	TLSCertFile    string `yaml:"tls_cert_file"`
+	TLSKeyFile     string `yaml:"tls_key_file"`
+	ClientCAFile   string `yaml:"client_ca_file"`   // the client has to present a certificate signed by this CA
+	AuthTokenFile  string `yaml:"auth_token_file"`  // the file of the bearer token
+	AuthToken      string `yaml:"auth_token"`       // the bearer token, it can't be set along with the file
+	HMACKeyFile    string `yaml:"hmac_key_file"`    // the file of the key the requests are signed with
+	HMACKey        string `yaml:"hmac_key"`         // the key the requests are signed with, it can't be set along with the file
+	UnixSocket     string `yaml:"unix_socket"`      // the api is served on this unix socket as well
+	UnixSocketMode string `yaml:"unix_socket_mode"` // octal permissions of the socket file, 0600 by default
+}
+
+// authenticator checks the credentials of the requests, the requests without valid credentials get 401
+type authenticator struct {
+	token         []byte
+	hmacKey       []byte
+	requireClient bool
+	now           func() time.Time
+	lock          *sync.Mutex
+	seen          map[string]time.Time // the signatures accepted, till they expire
+}
+
+// readSecret return the given secret, or read it from the given file
+func readSecret(name, secret, file string) ([]byte, error) {
+	if len(secret) != 0 {
+		if len(file) != 0 {
+			return nil, fmt.Errorf("%s and its file are both set", name)
+		}
+		return []byte(secret), nil
+	}
+	if len(file) == 0 {
+		return nil, nil
+	}
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read %s: %w", file, err)
+	}
+	value := bytes.TrimSpace(buf)
+	if len(value) == 0 {
+		return nil, fmt.Errorf("%s is empty", file)
+	}
+	return value, nil
+}
+
+// newAuthenticator return nil when no authentication is set
+func newAuthenticator(conf SecurityConfig) (*authenticator, error) {
+	a := &authenticator{
+		requireClient: len(conf.ClientCAFile) != 0,
+		now:           time.Now,
+		lock:          &sync.Mutex{},
+		seen:          make(map[string]time.Time),
+	}
+	var err error
+	if a.token, err = readSecret("auth token", conf.AuthToken, conf.AuthTokenFile); err != nil {
+		return nil, err
+	}
+	if a.hmacKey, err = readSecret("HMAC key", conf.HMACKey, conf.HMACKeyFile); err != nil {
+		return nil, err
+	}
+	if a.token == nil && a.hmacKey == nil && !a.requireClient {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// check return the reason the request is refused, empty if the request is authenticated
+func (a *authenticator) check(r *http.Request) string {
+	// the requests through the unix socket don't come with TLS
+	if a.requireClient && r.TLS != nil && len(r.TLS.VerifiedChains) == 0 {
+		return "missing_client_cert"
+	}
+	if a.token == nil && a.hmacKey == nil {
+		return ""
+	}
+	if auth := r.Header.Get("Authorization"); len(auth) != 0 && a.token != nil {
+		if !strings.HasPrefix(auth, "Bearer ") {
+			return "invalid_token"
+		}
+		if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(auth, "Bearer ")), a.token) != 1 {
+			return "invalid_token"
+		}
+		return ""
+	}
+	if len(r.Header.Get(HeaderSignature)) != 0 && a.hmacKey != nil {
+		return a.checkSignature(r)
+	}
+	return "missing_credentials"
+}
+
+func (a *authenticator) checkSignature(r *http.Request) string {
+	timestamp, err := strconv.ParseInt(r.Header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		return "invalid_signature"
+	}
+	signedAt := time.Unix(timestamp, 0)
+	now := a.now()
+	if age := now.Sub(signedAt); age > maxSignatureAge || age < -maxSignatureAge {
+		return "expired_signature"
+	}
+	nonce := r.Header.Get(HeaderNonce)
+	if len(nonce) == 0 || len(nonce) > maxNonceSize {
+		return "invalid_signature"
+	}
+	var body []byte
+	if r.Body != nil {
+		body, err = ioutil.ReadAll(http.MaxBytesReader(nil, r.Body, maxSignedBodySize))
+		if err != nil {
+			return "invalid_signature"
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	expected, err := hex.DecodeString(r.Header.Get(HeaderSignature))
+	if err != nil || !hmac.Equal(expected, signRequest(a.hmacKey, r.Method, r.URL.RequestURI(), timestamp, nonce, body)) {
+		return "invalid_signature"
+	}
+	// the signature can't be replayed after it expires, so it is only kept till then
+	if !a.markSeen(hex.EncodeToString(expected), signedAt.Add(maxSignatureAge), now) {
+		return "replayed_signature"
+	}
+	return ""
+}
+
+// markSeen record the given signature, it return false if the signature is seen before
+func (a *authenticator) markSeen(signature string, expireAt, now time.Time) bool {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	for key, el := range a.seen {
+		if now.After(el) {
+			delete(a.seen, key)
+		}
+	}
+	if _, ok := a.seen[signature]; ok {
+		return false
+	}
+	a.seen[signature] = expireAt
+	return true
+}
+
+// checkToken check the bearer token in the metadata of the grpc request
+func (a *authenticator) checkToken(ctx context.Context) error {
+	reason := "missing_credentials"
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) != 0 {
+		reason = "invalid_token"
+		token := strings.TrimPrefix(values[0], "Bearer ")
+		if strings.HasPrefix(values[0], "Bearer ") && subtle.ConstantTimeCompare([]byte(token), a.token) == 1 {
+			return nil
+		}
+	}
+	monitor.ObserveUnauthorized(reason)
+	return status.Error(codes.Unauthenticated, reason)
+}
+
+// middleware refuse the requests without valid credentials, the ping is always allowed so the liveness probe
+// doesn't need the credentials
+func (a *authenticator) middleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ping" {
+			if reason := a.check(r); len(reason) != 0 {
+				monitor.ObserveUnauthorized(reason)
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func signRequest(key []byte, method, uri string, timestamp int64, nonce string, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%d\n%s\n", method, uri, timestamp, nonce)
+	_, _ = mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// SignRequest sign the given request with the HMAC key and a random nonce, the signature is the hex of the
+// HMAC-SHA256 of the method, the request uri, the timestamp and the nonce, each followed by a new line, and then the
+// body. The server accepts each signature only once
+func SignRequest(key []byte, r *http.Request, body []byte, now time.Time) error {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Errorf("fail to generate the nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(buf)
+	timestamp := now.Unix()
+	r.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	r.Header.Set(HeaderNonce, nonce)
+	r.Header.Set(HeaderSignature, hex.EncodeToString(signRequest(key, r.Method, r.URL.RequestURI(), timestamp, nonce, body)))
+	return nil
+}
+
+// newTLSConfig return nil when TLS is not set
+func newTLSConfig(conf SecurityConfig) (*tls.Config, error) {
+	if len(conf.TLSCertFile) == 0 && len(conf.TLSKeyFile) == 0 {
+		if len(conf.ClientCAFile) != 0 {
+			return nil, errors.New("client CA is set without the TLS certificate")
+		}
+		return nil, nil
+	}
+	cert, err := tls.LoadX509KeyPair(conf.TLSCertFile, conf.TLSKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load the TLS certificate: %w", err)
+	}
+	tlsConf := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	if len(conf.ClientCAFile) != 0 {
+		buf, err := ioutil.ReadFile(conf.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("fail to read the client CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(buf) {
+			return nil, errors.New("no certificate is found in the client CA")
+		}
+		tlsConf.ClientCAs = pool
+		// the request without the client certificate gets 401 from the authenticator rather than a failed handshake,
+		// so it is counted
+		tlsConf.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return tlsConf, nil
+}
+
+// listenUnix listen to the given unix socket with the given permissions, the socket is created in a private
+// directory and only moved to the given path once its permissions are set, so it is never reachable with the default
+// ones
+func listenUnix(path, mode string) (net.Listener, error) {
+	if len(mode) == 0 {
+		mode = defaultUnixSocketMode
+	}
+	perm, err := strconv.ParseUint(mode, 8, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unix socket mode(%s): %w", mode, err)
+	}
+	// the socket file left by the last run is removed, otherwise listen fails
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("fail to remove the old unix socket: %w", err)
+	}
+	tmpDir, err := ioutil.TempDir(filepath.Dir(path), ".tss-socket")
+	if err != nil {
+		return nil, fmt.Errorf("fail to create the directory of the unix socket: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Error().Err(err).Msg("fail to remove the directory of the unix socket")
+		}
+	}()
+	tmpPath := filepath.Join(tmpDir, "tss.sock")
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: tmpPath, Net: "unix"})
+	if err != nil {
+		return nil, fmt.Errorf("fail to listen to the unix socket: %w", err)
+	}
+	// the socket file is removed when the server stops, it is not at the path it is created at anymore
+	listener.SetUnlinkOnClose(false)
+	if err := os.Chmod(tmpPath, os.FileMode(perm)); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("fail to set the permissions of the unix socket: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("fail to move the unix socket: %w", err)
+	}
+	return listener, nil
+}
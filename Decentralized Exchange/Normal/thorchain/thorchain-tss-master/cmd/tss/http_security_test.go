@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/rpc"
+)
+
+type HttpSecurityTestSuite struct {
+	folder string
+}
+
+var _ = Suite(&HttpSecurityTestSuite{})
+
+func (s *HttpSecurityTestSuite) SetUpTest(c *C) {
+	s.folder = c.MkDir()
+}
+
+func (s *HttpSecurityTestSuite) writeFile(c *C, name, content string) string {
+	path := filepath.Join(s.folder, name)
+	c.Assert(ioutil.WriteFile(path, []byte(content), 0o600), IsNil)
+	return path
+}
+
+// writeCert write a certificate signed by the given parent and its private key, the certificate is self signed when
+// the parent is nil
+func (s *HttpSecurityTestSuite) writeCert(c *C, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	c.Assert(err, IsNil)
+	cert, err := x509.ParseCertificate(der)
+	c.Assert(err, IsNil)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+	s.writeFile(c, name+".crt", string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+	s.writeFile(c, name+".key", string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer})))
+	return cert, key
+}
+
+func (s *HttpSecurityTestSuite) TestToken(c *C) {
+	hs, err := NewSecureTssHttpServer("127.0.0.1:8080", &MockTssServer{}, SecurityConfig{
+		AuthTokenFile: s.writeFile(c, "token", "secret\n"),
+	})
+	c.Assert(err, IsNil)
+	for token, code := range map[string]int{
+		"":              http.StatusUnauthorized,
+		"Bearer whoami": http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer secret": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/p2pid", nil)
+		if len(token) != 0 {
+			req.Header.Set("Authorization", token)
+		}
+		res := httptest.NewRecorder()
+		hs.s.Handler.ServeHTTP(res, req)
+		c.Assert(res.Code, Equals, code, Commentf("token: %s", token))
+	}
+	// the liveness probe doesn't need the token
+	res := httptest.NewRecorder()
+	hs.s.Handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+
+	// the refused requests are counted
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	res = httptest.NewRecorder()
+	hs.s.Handler.ServeHTTP(res, req)
+	c.Assert(res.Code, Equals, http.StatusOK)
+	c.Assert(strings.Contains(res.Body.String(), `tss_http_unauthorized_total{reason="invalid_token"} `), Equals, true)
+
+	_, err = NewSecureTssHttpServer("127.0.0.1:8080", &MockTssServer{}, SecurityConfig{
+		AuthTokenFile: s.writeFile(c, "empty", "\n"),
+	})
+	c.Assert(err, NotNil)
+
+	// the token can be given straight away, but not along with its file
+	hs, err = NewSecureTssHttpServer("127.0.0.1:8080", &MockTssServer{}, SecurityConfig{AuthToken: "inline"})
+	c.Assert(err, IsNil)
+	req = httptest.NewRequest(http.MethodGet, "/p2pid", nil)
+	req.Header.Set("Authorization", "Bearer inline")
+	res = httptest.NewRecorder()
+	hs.s.Handler.ServeHTTP(res, req)
+	c.Assert(res.Code, Equals, http.StatusOK)
+	_, err = NewSecureTssHttpServer("127.0.0.1:8080", &MockTssServer{}, SecurityConfig{
+		AuthToken:     "inline",
+		AuthTokenFile: s.folder + "/token",
+	})
+	c.Assert(err, NotNil)
+}
+
+func (s *HttpSecurityTestSuite) TestHMAC(c *C) {
+	key := []byte("hmac-key")
+	hs, err := NewSecureTssHttpServer("127.0.0.1:8080", &MockTssServer{}, SecurityConfig{
+		HMACKeyFile: s.writeFile(c, "hmac", string(key)),
+	})
+	c.Assert(err, IsNil)
+	body := []byte(`{"keys":["whatever"]}`)
+	send := func(sign func(req *http.Request)) int {
+		req := httptest.NewRequest(http.MethodPost, "/keygen", bytes.NewReader(body))
+		sign(req)
+		res := httptest.NewRecorder()
+		hs.s.Handler.ServeHTTP(res, req)
+		return res.Code
+	}
+	c.Assert(send(func(req *http.Request) {}), Equals, http.StatusUnauthorized)
+	c.Assert(send(func(req *http.Request) {
+		c.Assert(SignRequest(key, req, body, time.Now()), IsNil)
+	}), Equals, http.StatusOK)
+	c.Assert(send(func(req *http.Request) {
+		c.Assert(SignRequest([]byte("whatever"), req, body, time.Now()), IsNil)
+	}), Equals, http.StatusUnauthorized)
+	c.Assert(send(func(req *http.Request) {
+		c.Assert(SignRequest(key, req, []byte(`{"keys":["other"]}`), time.Now()), IsNil)
+	}), Equals, http.StatusUnauthorized)
+	c.Assert(send(func(req *http.Request) {
+		c.Assert(SignRequest(key, req, body, time.Now().Add(-time.Hour)), IsNil)
+	}), Equals, http.StatusUnauthorized)
+
+	// the signed request is accepted only once
+	var signed http.Header
+	c.Assert(send(func(req *http.Request) {
+		c.Assert(SignRequest(key, req, body, time.Now()), IsNil)
+		signed = req.Header.Clone()
+	}), Equals, http.StatusOK)
+	c.Assert(send(func(req *http.Request) {
+		req.Header = signed.Clone()
+	}), Equals, http.StatusUnauthorized)
+	// the nonce is covered by the signature
+	c.Assert(send(func(req *http.Request) {
+		req.Header = signed.Clone()
+		req.Header.Set(HeaderNonce, "other")
+	}), Equals, http.StatusUnauthorized)
+	c.Assert(send(func(req *http.Request) {
+		req.Header = signed.Clone()
+		req.Header.Del(HeaderNonce)
+	}), Equals, http.StatusUnauthorized)
+}
+
+func (s *HttpSecurityTestSuite) TestSeenSignatureExpires(c *C) {
+	a, err := newAuthenticator(SecurityConfig{HMACKey: "hmac-key"})
+	c.Assert(err, IsNil)
+	now := time.Now()
+	c.Assert(a.markSeen("aa", now.Add(maxSignatureAge), now), Equals, true)
+	c.Assert(a.markSeen("aa", now.Add(maxSignatureAge), now.Add(time.Minute)), Equals, false)
+	c.Assert(a.markSeen("bb", now.Add(maxSignatureAge+time.Minute), now.Add(time.Minute)), Equals, true)
+	// the expired signature is dropped from the cache
+	c.Assert(a.markSeen("cc", now.Add(maxSignatureAge*2), now.Add(maxSignatureAge+time.Second)), Equals, true)
+	c.Assert(a.seen, HasLen, 2)
+}
+
+func (s *HttpSecurityTestSuite) TestMutualTLS(c *C) {
+	ca, caKey := s.writeCert(c, "ca", nil, nil)
+	s.writeCert(c, "server", ca, caKey)
+	s.writeCert(c, "client", ca, caKey)
+	conf := SecurityConfig{
+		TLSCertFile:  filepath.Join(s.folder, "server.crt"),
+		TLSKeyFile:   filepath.Join(s.folder, "server.key"),
+		ClientCAFile: filepath.Join(s.folder, "ca.crt"),
+	}
+	hs, err := NewSecureTssHttpServer("127.0.0.1:0", &MockTssServer{}, conf)
+	c.Assert(err, IsNil)
+	listeners, err := hs.listen()
+	c.Assert(err, IsNil)
+	c.Assert(listeners, HasLen, 1)
+	go func() {
+		c.Check(hs.serve(listeners[0]), IsNil)
+	}()
+	defer func() {
+		c.Assert(hs.Stop(), IsNil)
+	}()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	url := "https://" + listeners[0].Addr().String() + "/p2pid"
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	resp, err := client.Get(url)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusUnauthorized)
+
+	clientCert, err := tls.LoadX509KeyPair(filepath.Join(s.folder, "client.crt"), filepath.Join(s.folder, "client.key"))
+	c.Assert(err, IsNil)
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{clientCert},
+	}}}
+	resp, err = client.Get(url)
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	// the client CA is useless without the certificate of the server
+	_, err = NewSecureTssHttpServer("127.0.0.1:0", &MockTssServer{}, SecurityConfig{ClientCAFile: conf.ClientCAFile})
+	c.Assert(err, NotNil)
+}
+
+func (s *HttpSecurityTestSuite) TestUnixSocket(c *C) {
+	socket := filepath.Join(s.folder, "tss.sock")
+	// the socket left by the last run is replaced
+	s.writeFile(c, "tss.sock", "whatever")
+	hs, err := NewSecureTssHttpServer("", &MockTssServer{}, SecurityConfig{
+		UnixSocket:     socket,
+		UnixSocketMode: "0600",
+	})
+	c.Assert(err, IsNil)
+	listeners, err := hs.listen()
+	c.Assert(err, IsNil)
+	c.Assert(listeners, HasLen, 1)
+	go func() {
+		c.Check(hs.serve(listeners[0]), IsNil)
+	}()
+	info, err := os.Stat(socket)
+	c.Assert(err, IsNil)
+	c.Assert(info.Mode()&os.ModeSocket, Not(Equals), os.FileMode(0))
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0o600))
+	// nothing is left from the creation of the socket
+	files, err := ioutil.ReadDir(s.folder)
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+		},
+	}}
+	resp, err := client.Get("http://unix/p2pid")
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	c.Assert(hs.Stop(), IsNil)
+	_, err = os.Stat(socket)
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	_, err = NewSecureTssHttpServer("", &MockTssServer{}, SecurityConfig{})
+	c.Assert(err, NotNil)
+}
+
+func (s *HttpSecurityTestSuite) TestGrpcToken(c *C) {
+	_, err := NewSecureTssGrpcServer("", &MockTssServer{}, SecurityConfig{
+		HMACKeyFile: s.writeFile(c, "hmac", "hmac-key"),
+	})
+	c.Assert(err, NotNil)
+
+	gs, err := NewSecureTssGrpcServer("", &MockTssServer{}, SecurityConfig{
+		AuthTokenFile: s.writeFile(c, "token", "secret"),
+	})
+	c.Assert(err, IsNil)
+	listener := bufconn.Listen(1024 * 1024)
+	go func() {
+		c.Check(gs.Serve(listener), IsNil)
+	}()
+	defer gs.Stop()
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	c.Assert(err, IsNil)
+	defer conn.Close()
+	client := rpc.NewTssClient(conn)
+
+	_, err = client.GetLocalPeerID(context.Background(), &rpc.GetLocalPeerIDRequest{})
+	c.Assert(status.Code(err), Equals, codes.Unauthenticated)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer secret")
+	_, err = client.GetLocalPeerID(ctx, &rpc.GetLocalPeerIDRequest{})
+	c.Assert(err, IsNil)
+	stream, err := client.KeygenStream(context.Background(), &rpc.KeygenRequest{})
+	c.Assert(err, IsNil)
+	_, err = stream.Recv()
+	c.Assert(status.Code(err), Equals, codes.Unauthenticated)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk/client/input"
+	tcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keystore"
+)
+
+// keystoreCommand is the subcommand that creates the keystore, e.g. tss keystore -out keystore.json
+const keystoreCommand = "keystore"
+
+// readPassphrase return the passphrase of the keystore given in the config or its file, otherwise it is prompted, and
+// asked twice when the confirm is true
+func readPassphrase(conf KeyConfig, in *bufio.Reader, confirm bool) (string, error) {
+	if len(conf.Passphrase) != 0 && len(conf.PassphraseFile) != 0 {
+		return "", errors.New("the keystore passphrase and its file are both set")
+	}
+	if len(conf.Passphrase) != 0 {
+		return conf.Passphrase, nil
+	}
+	if len(conf.PassphraseFile) != 0 {
+		buf, err := ioutil.ReadFile(conf.PassphraseFile)
+		if err != nil {
+			return "", fmt.Errorf("fail to read the passphrase file: %w", err)
+		}
+		// only the line break added by the editor is removed, the passphrase can have spaces
+		passphrase := strings.TrimRight(string(buf), "\r\n")
+		if len(passphrase) == 0 {
+			return "", fmt.Errorf("%s is empty", conf.PassphraseFile)
+		}
+		return passphrase, nil
+	}
+	if confirm {
+		return input.GetCheckPassword("keystore passphrase:", "repeat the keystore passphrase:", in)
+	}
+	return input.GetPassword("keystore passphrase:", in)
+}
+
+// loadNodeKey return the private key of the node, it is decrypted from the keystore when one is given, otherwise it
+// is read from stdin as the base64 of the hex of the key
+func loadNodeKey(conf KeyConfig, in *bufio.Reader) (tcrypto.PrivKey, error) {
+	if len(conf.Keystore) == 0 {
+		priKeyBytes, err := input.GetPassword("input node secret key:", in)
+		if err != nil {
+			return nil, fmt.Errorf("fail to get the secret key: %w", err)
+		}
+		return conversion.GetPriKey(priKeyBytes)
+	}
+	passphrase, err := readPassphrase(conf, in, false)
+	if err != nil {
+		return nil, err
+	}
+	rawBytes, err := keystore.Load(conf.Keystore, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("fail to load the keystore(%s): %w", conf.Keystore, err)
+	}
+	return conversion.GetPriKeyFromRawBytes(rawBytes)
+}
+
+// runKeystoreCommand create the keystore of a new node key, or of the existing one read from stdin, the pub key and
+// the p2p ID of the node are printed afterwards
+func runKeystoreCommand(args []string, in *bufio.Reader, out io.Writer, lookupEnv func(string) (string, bool)) error {
+	fs := flag.NewFlagSet(keystoreCommand, flag.ContinueOnError)
+	file := fs.String("out", "", "file the keystore is written to, the existing file is never overwritten")
+	existing := fs.Bool("import", false, "encrypt the existing node key read from stdin rather than a new one")
+	passphraseFile := fs.String("passphrase-file", "", "file of the passphrase, it is prompted if neither the file nor "+envPrefix+"_KEY_PASSPHRASE is given")
+	prefix := fs.String("bech32-prefix", conversion.DefaultBech32Prefix, "account prefix of the printed pub key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(*file) == 0 {
+		return errors.New("the file of the keystore is not given")
+	}
+	conf := KeyConfig{Keystore: *file, PassphraseFile: *passphraseFile}
+	conf.Passphrase, _ = lookupEnv(envPrefix + "_KEY_PASSPHRASE")
+
+	var priKey tcrypto.PrivKey = secp256k1.GenPrivKey()
+	if *existing {
+		secret, err := input.GetPassword("input node secret key:", in)
+		if err != nil {
+			return fmt.Errorf("fail to get the secret key: %w", err)
+		}
+		if priKey, err = conversion.GetPriKey(secret); err != nil {
+			return err
+		}
+	}
+	passphrase, err := readPassphrase(conf, in, true)
+	if err != nil {
+		return err
+	}
+	if len(passphrase) < input.MinPassLength {
+		return fmt.Errorf("passphrase must be at least %d characters", input.MinPassLength)
+	}
+	rawBytes, err := conversion.GetPriKeyRawBytes(priKey)
+	if err != nil {
+		return err
+	}
+	if err := keystore.Save(*file, rawBytes, passphrase); err != nil {
+		return err
+	}
+	pubKey, err := conversion.Bech32ifyPubKey(*prefix, priKey.PubKey())
+	if err != nil {
+		return fmt.Errorf("fail to get the pub key: %w", err)
+	}
+	peerID, err := conversion.GetPeerIDFromSecp256PubKey(priKey.PubKey().(secp256k1.PubKeySecp256k1))
+	if err != nil {
+		return fmt.Errorf("fail to get the p2p ID: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "keystore is written to %s\npub key: %s\np2p id: %s\n", *file, pubKey, peerID)
+	return err
+}
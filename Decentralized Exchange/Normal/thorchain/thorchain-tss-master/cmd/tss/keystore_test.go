@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keystore"
+)
+
+type KeystoreTestSuite struct{}
+
+var _ = Suite(&KeystoreTestSuite{})
+
+func newInput(lines ...string) *bufio.Reader {
+	return bufio.NewReader(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+}
+
+func (s *KeystoreTestSuite) TestKeystoreCommand(c *C) {
+	dir := c.MkDir()
+	file := filepath.Join(dir, "keystore.json")
+	var out bytes.Buffer
+	c.Assert(runKeystoreCommand([]string{"-out", file}, newInput("passphrase", "passphrase"), &out, noEnv), IsNil)
+	c.Assert(strings.Contains(out.String(), "pub key: thorpub"), Equals, true)
+
+	priKey, err := loadNodeKey(KeyConfig{Keystore: file}, newInput("passphrase"))
+	c.Assert(err, IsNil)
+	pubKey, err := conversion.Bech32ifyPubKey(conversion.DefaultBech32Prefix, priKey.PubKey())
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(out.String(), pubKey), Equals, true)
+	_, err = loadNodeKey(KeyConfig{Keystore: file}, newInput("wrong passphrase"))
+	c.Assert(errors.Is(err, keystore.ErrInvalidMAC), Equals, true)
+	// the existing keystore is never overwritten
+	c.Assert(runKeystoreCommand([]string{"-out", file}, newInput("passphrase", "passphrase"), &out, noEnv), NotNil)
+	// the passphrase is too short
+	c.Assert(runKeystoreCommand([]string{"-out", file + ".short"}, newInput("short", "short"), &out, func(string) (string, bool) {
+		return "short", true
+	}), NotNil)
+	c.Assert(runKeystoreCommand(nil, newInput(), &out, noEnv), NotNil)
+}
+
+func (s *KeystoreTestSuite) TestImportKey(c *C) {
+	dir := c.MkDir()
+	file := filepath.Join(dir, "keystore.json")
+	passphraseFile := filepath.Join(dir, "passphrase")
+	c.Assert(ioutil.WriteFile(passphraseFile, []byte("passphrase\n"), 0o600), IsNil)
+
+	// the existing key is the base64 of the hex of the key, the same as what is given on stdin to the node
+	existing := secp256k1.GenPrivKey()
+	secret := base64.StdEncoding.EncodeToString([]byte(hex.EncodeToString(existing[:])))
+	var out bytes.Buffer
+	c.Assert(runKeystoreCommand([]string{"-out", file, "-import", "-passphrase-file", passphraseFile}, newInput(secret), &out, noEnv), IsNil)
+
+	priKey, err := loadNodeKey(KeyConfig{Keystore: file, PassphraseFile: passphraseFile}, newInput())
+	c.Assert(err, IsNil)
+	c.Assert(priKey.Equals(existing), Equals, true)
+	priKey, err = loadNodeKey(KeyConfig{Keystore: file, Passphrase: "passphrase"}, newInput())
+	c.Assert(err, IsNil)
+	c.Assert(priKey.Equals(existing), Equals, true)
+
+	// without the keystore the key is read from stdin as before
+	priKey, err = loadNodeKey(KeyConfig{}, newInput(secret))
+	c.Assert(err, IsNil)
+	c.Assert(priKey.Equals(existing), Equals, true)
+
+	c.Assert(ioutil.WriteFile(passphraseFile, []byte("\n"), 0o600), IsNil)
+	_, err = loadNodeKey(KeyConfig{Keystore: file, PassphraseFile: passphraseFile}, newInput())
+	c.Assert(err, NotNil)
+}
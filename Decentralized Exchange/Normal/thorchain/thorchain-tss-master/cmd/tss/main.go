@@ -2,59 +2,69 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/binance-chain/go-sdk/common/types"
-	"github.com/cosmos/cosmos-sdk/client/input"
 	golog "github.com/ipfs/go-log"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 
 	"gitlab.com/thorchain/tss/go-tss/common"
-	"gitlab.com/thorchain/tss/go-tss/conversion"
-	"gitlab.com/thorchain/tss/go-tss/p2p"
 	"gitlab.com/thorchain/tss/go-tss/tss"
 )
 
-var (
-	help       bool
-	logLevel   string
-	pretty     bool
-	baseFolder string
-	tssAddr    string
-)
-
 func main() {
-	// Parse the cli into configuration structs
-	tssConf, p2pConf := parseFlags()
-	if help {
-		flag.PrintDefaults()
+	if len(os.Args) > 1 && os.Args[1] == keystoreCommand {
+		if err := runKeystoreCommand(os.Args[2:], bufio.NewReader(os.Stdin), os.Stdout, os.LookupEnv); err != nil {
+			if !errors.Is(err, flag.ErrHelp) {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+		return
+	}
+	conf, opts, err := loadConfig(os.Args[1:], os.LookupEnv)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if opts.help {
+		defaults := DefaultConfig()
+		newFlagSet(&defaults, &options{}).PrintDefaults()
+		return
+	}
+	if opts.printDefaultConfig {
+		fmt.Print(defaultConfigTemplate())
 		return
 	}
+	if err := conf.Validate(); err != nil {
+		fmt.Printf("invalid config: %s\n", err)
+		os.Exit(1)
+	}
+	p2pConf, err := conf.P2P.toP2PConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
 	// Setup logging
 	golog.SetAllLoggers(golog.LevelInfo)
 	_ = golog.SetLogLevel("tss-lib", "INFO")
-	common.InitLog(logLevel, pretty, "tss_service")
+	common.InitLog(conf.Log.Level, conf.Log.Pretty, "tss_service")
+	fmt.Printf("effective config:\n%s", conf)
 
-	// Setup Bech32 Prefixes
-	conversion.SetupBech32Prefix()
 	// this is only need for the binance library
 	if os.Getenv("NET") == "testnet" || os.Getenv("NET") == "mocknet" {
 		types.Network = types.TestNetwork
 	}
-	// Read stdin for the private key
-	inBuf := bufio.NewReader(os.Stdin)
-	priKeyBytes, err := input.GetPassword("input node secret key:", inBuf)
-	if err != nil {
-		fmt.Printf("error in get the secret key: %s\n", err.Error())
-		return
-	}
-	priKey, err := conversion.GetPriKey(priKeyBytes)
+	// Decrypt the private key from the keystore, or read it from stdin
+	priKey, err := loadNodeKey(conf.Key, bufio.NewReader(os.Stdin))
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -64,47 +74,43 @@ func main() {
 		p2pConf.Port,
 		priKey,
 		p2pConf.RendezvousString,
-		baseFolder,
-		tssConf,
+		conf.Storage.Home,
+		conf.TSS.toTssConfig(),
 		nil,
 		p2pConf.ExternalIP,
 	)
 	if nil != err {
 		log.Fatal(err)
 	}
-	s := NewTssHttpServer(tssAddr, tss)
+	s, err := NewSecureTssHttpServer(conf.HTTP.Addr, tss, conf.Security)
+	if err != nil {
+		log.Fatal(err)
+	}
 	go func() {
 		if err := s.Start(); err != nil {
 			fmt.Println(err)
 		}
 	}()
+	var gs *TssGrpcServer
+	if len(conf.HTTP.GrpcAddr) != 0 {
+		gs, err = NewSecureTssGrpcServer(conf.HTTP.GrpcAddr, tss, conf.Security)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			if err := gs.Start(); err != nil {
+				fmt.Println(err)
+			}
+		}()
+	}
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
 	<-ch
 	fmt.Println("stop ")
+	// the http and grpc servers keep serving till the sessions in flight are finished
+	tss.Drain()
+	if gs != nil {
+		gs.Stop()
+	}
 	fmt.Println(s.Stop())
 }
-
-// parseFlags - Parses the cli flags
-func parseFlags() (tssConf common.TssConfig, p2pConf p2p.Config) {
-	// we setup the configure for the general configuration
-	flag.StringVar(&tssAddr, "tss-port", "127.0.0.1:8080", "tss port")
-	flag.BoolVar(&help, "h", false, "Display Help")
-	flag.StringVar(&logLevel, "loglevel", "info", "Log Level")
-	flag.BoolVar(&pretty, "pretty-log", false, "Enables unstructured prettified logging. This is useful for local debugging")
-	flag.StringVar(&baseFolder, "home", "", "home folder to store the keygen state file")
-
-	// we setup the Tss parameter configuration
-	flag.DurationVar(&tssConf.KeyGenTimeout, "gentimeout", 30*time.Second, "keygen timeout")
-	flag.DurationVar(&tssConf.KeySignTimeout, "signtimeout", 30*time.Second, "keysign timeout")
-	flag.DurationVar(&tssConf.PreParamTimeout, "preparamtimeout", 5*time.Minute, "pre-parameter generation timeout")
-
-	// we setup the p2p network configuration
-	flag.StringVar(&p2pConf.RendezvousString, "rendezvous", "Asgard",
-		"Unique string to identify group of nodes. Share this with your friends to let them connect with you")
-	flag.IntVar(&p2pConf.Port, "p2p-port", 6668, "listening port local")
-	flag.StringVar(&p2pConf.ExternalIP, "external-ip", "", "external IP of this node")
-	flag.Var(&p2pConf.BootstrapPeers, "peer", "Adds a peer multiaddress to the bootstrap list")
-	flag.Parse()
-	return
-}
@@ -1,20 +1,39 @@
 package main
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"time"
 
+	"gitlab.com/thorchain/tss/go-tss/audit"
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+	"gitlab.com/thorchain/tss/go-tss/tss"
 )
 
 type MockTssServer struct {
-	failToStart   bool
-	failToKeyGen  bool
-	failToKeySign bool
+	failToStart    bool
+	failToKeyGen   bool
+	failToKeySign  bool
+	failToReshare  bool
+	failToDerive   bool
+	failToCheck    bool
+	failToListKeys bool
+	failToSetState bool
+	failToAudit    bool
+	draining       bool
+	stuckInKeyGen  bool
+	keyState       storage.KeyState // the state of the key asked to sign, active if it is empty
+	eventBus       *events.Bus
+	jobManager     *tss.JobManager
 }
 
 func (mts *MockTssServer) Start() error {
@@ -32,17 +51,212 @@ func (mts *MockTssServer) GetLocalPeerID() string {
 }
 
 func (mts *MockTssServer) Keygen(req keygen.Request) (keygen.Response, error) {
+	if mts.draining {
+		return keygen.Response{}, tss.ErrDraining
+	}
 	if mts.failToKeyGen {
 		return keygen.Response{}, errors.New("you ask for it")
 	}
 	return keygen.NewResponse(conversion.GetRandomPubKey(), "whatever", common.Success, blame.Blame{}), nil
 }
 
+func (mts *MockTssServer) KeygenWithContext(ctx context.Context, req keygen.Request) (keygen.Response, error) {
+	if mts.stuckInKeyGen {
+		<-ctx.Done()
+	}
+	if ctx.Err() != nil {
+		return keygen.Response{Status: common.Cancelled}, nil
+	}
+	return mts.Keygen(req)
+}
+
+func (mts *MockTssServer) KeygenWithProgress(ctx context.Context, req keygen.Request, listener tss.ProgressListener) (keygen.Response, error) {
+	if ctx.Err() != nil {
+		return keygen.Response{Status: common.Cancelled}, nil
+	}
+	listener(tss.ProgressEvent{Type: tss.ProgressPartyJoined})
+	listener(tss.ProgressEvent{Type: tss.ProgressRoundStarted, Round: 1, RoundName: "KGRound1Message"})
+	return mts.Keygen(req)
+}
+
 func (mts *MockTssServer) KeySign(req keysign.Request) (keysign.Response, error) {
+	if mts.draining {
+		return keysign.Response{}, tss.ErrDraining
+	}
 	if mts.failToKeySign {
 		return keysign.Response{}, errors.New("you ask for it")
 	}
-	return keysign.NewResponse("", "", common.Success, blame.Blame{}), nil
+	switch mts.keyState {
+	case storage.KeyRetiring:
+		return keysign.Response{}, tss.ErrKeyRetiring
+	case storage.KeyRetired, storage.KeyArchived:
+		return keysign.Response{}, fmt.Errorf("%w, its state is %s", tss.ErrKeyRetired, mts.keyState)
+	}
+	return keysign.NewResponse("", "", nil, common.Success, blame.Blame{}), nil
+}
+
+func (mts *MockTssServer) KeySignWithContext(ctx context.Context, req keysign.Request) (keysign.Response, error) {
+	if ctx.Err() != nil {
+		return keysign.Response{Status: common.Cancelled}, nil
+	}
+	return mts.KeySign(req)
+}
+
+func (mts *MockTssServer) KeySignWithProgress(ctx context.Context, req keysign.Request, listener tss.ProgressListener) (keysign.Response, error) {
+	if ctx.Err() != nil {
+		return keysign.Response{Status: common.Cancelled}, nil
+	}
+	listener(tss.ProgressEvent{Type: tss.ProgressPartyJoined})
+	listener(tss.ProgressEvent{Type: tss.ProgressRoundStarted, Round: 1, RoundName: "SignRound1Message"})
+	return mts.KeySign(req)
+}
+
+func (mts *MockTssServer) KeySignBatch(req keysign.BatchRequest) ([]keysign.Response, error) {
+	if mts.draining {
+		return nil, tss.ErrDraining
+	}
+	if mts.failToKeySign {
+		return nil, errors.New("you ask for it")
+	}
+	responses := make([]keysign.Response, len(req.Messages))
+	for i := range req.Messages {
+		responses[i] = keysign.NewResponse("", "", nil, common.Success, blame.Blame{})
+	}
+	return responses, nil
+}
+
+func (mts *MockTssServer) KeySignBatchWithContext(ctx context.Context, req keysign.BatchRequest) ([]keysign.Response, error) {
+	if ctx.Err() != nil {
+		responses := make([]keysign.Response, len(req.Messages))
+		for i := range responses {
+			responses[i] = keysign.Response{Status: common.Cancelled}
+		}
+		return responses, nil
+	}
+	return mts.KeySignBatch(req)
+}
+
+func (mts *MockTssServer) Reshare(req reshare.Request) (reshare.Response, error) {
+	if mts.draining {
+		return reshare.Response{}, tss.ErrDraining
+	}
+	if mts.failToReshare {
+		return reshare.Response{}, errors.New("you ask for it")
+	}
+	return reshare.NewResponse(req.PoolPubKey, "whatever", common.Success, blame.Blame{}), nil
+}
+
+func (mts *MockTssServer) ReshareWithContext(ctx context.Context, req reshare.Request) (reshare.Response, error) {
+	if ctx.Err() != nil {
+		return reshare.Response{Status: common.Cancelled}, nil
+	}
+	return mts.Reshare(req)
+}
+
+func (mts *MockTssServer) DerivePubKey(req keysign.DeriveRequest) (keysign.DeriveResponse, error) {
+	if mts.failToDerive {
+		return keysign.DeriveResponse{}, errors.New("you ask for it")
+	}
+	return keysign.DeriveResponse{PubKey: conversion.GetRandomPubKey(), Address: "whatever"}, nil
+}
+
+func (mts *MockTssServer) GetPubKeyInfo(poolPubKey, cosmosPrefix string) (conversion.PubKeyInfo, error) {
+	return conversion.GetPubKeyInfoFromBech32(poolPubKey, cosmosPrefix)
+}
+
+func (mts *MockTssServer) CheckParty(ctx context.Context, keys []string) (tss.PartyCheckResponse, error) {
+	if mts.failToCheck {
+		return tss.PartyCheckResponse{}, errors.New("you ask for it")
+	}
+	resp := tss.PartyCheckResponse{AllReachable: true}
+	for _, el := range keys {
+		resp.Nodes = append(resp.Nodes, tss.NodeReachability{
+			PubKey: el,
+			PeerReachability: p2p.PeerReachability{
+				Reachable: true,
+				RTT:       time.Millisecond.String(),
+			},
+		})
+	}
+	return resp, nil
+}
+
+func (mts *MockTssServer) ListLocalKeys() ([]storage.LocalStateInfo, error) {
+	if mts.failToListKeys {
+		return nil, errors.New("you ask for it")
+	}
+	return []storage.LocalStateInfo{
+		{
+			PubKey:          "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+			Algo:            common.ECDSA,
+			State:           storage.KeyActive,
+			ParticipantKeys: []string{"whatever"},
+			Threshold:       1,
+			ShareID:         "1",
+			PublicShares:    []string{"whatever"},
+		},
+	}, nil
+}
+
+func (mts *MockTssServer) GetLocalKey(pubKey string) (storage.LocalStateInfo, error) {
+	keys, err := mts.ListLocalKeys()
+	if err != nil {
+		return storage.LocalStateInfo{}, err
+	}
+	for _, el := range keys {
+		if el.PubKey == pubKey {
+			return el, nil
+		}
+	}
+	return storage.LocalStateInfo{}, tss.ErrKeyNotFound
+}
+
+func (mts *MockTssServer) SetKeyState(pubKey string, state storage.KeyState) (storage.LocalStateInfo, error) {
+	if mts.failToSetState {
+		return storage.LocalStateInfo{}, errors.New("you ask for it")
+	}
+	key, err := mts.GetLocalKey(pubKey)
+	if err != nil {
+		return storage.LocalStateInfo{}, err
+	}
+	if !key.State.CanTransitionTo(state) {
+		return storage.LocalStateInfo{}, tss.ErrInvalidKeyState
+	}
+	key.State = state
+	return key, nil
+}
+
+func (mts *MockTssServer) getJobManager() *tss.JobManager {
+	if mts.jobManager == nil {
+		mts.jobManager = tss.NewJobManager(time.Minute)
+	}
+	return mts.jobManager
+}
+
+func (mts *MockTssServer) KeygenAsync(req keygen.Request) (string, error) {
+	if mts.draining {
+		return "", tss.ErrDraining
+	}
+	return mts.getJobManager().Submit(tss.JobKeygen, func(ctx context.Context, _ *tss.JobProgress) (interface{}, error) {
+		return mts.KeygenWithContext(ctx, req)
+	})
+}
+
+func (mts *MockTssServer) KeySignAsync(req keysign.Request) (string, error) {
+	if mts.draining {
+		return "", tss.ErrDraining
+	}
+	return mts.getJobManager().Submit(tss.JobKeySign, func(ctx context.Context, _ *tss.JobProgress) (interface{}, error) {
+		return mts.KeySignWithContext(ctx, req)
+	})
+}
+
+func (mts *MockTssServer) GetJob(id string) (tss.Job, error) {
+	return mts.getJobManager().Get(id)
+}
+
+func (mts *MockTssServer) CancelJob(id string) (tss.Job, error) {
+	return mts.getJobManager().Cancel(id)
 }
 
 func (mts *MockTssServer) GetStatus() common.TssStatus {
@@ -54,3 +268,50 @@ func (mts *MockTssServer) GetStatus() common.TssStatus {
 		FailedKeySign: 0,
 	}
 }
+
+func (mts *MockTssServer) GetAuditEntries(limit int) ([]audit.Entry, error) {
+	if mts.failToAudit {
+		return nil, errors.New("you ask for it")
+	}
+	entries := []audit.Entry{
+		{
+			Seq:       2,
+			SessionID: "whatever-2",
+			Operation: audit.OpKeySign,
+			Outcome:   common.Success.String(),
+		},
+		{
+			Seq:       1,
+			SessionID: "whatever-1",
+			Operation: audit.OpKeygen,
+			Outcome:   common.Success.String(),
+		},
+	}
+	if limit > 0 && limit < len(entries) {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+func (mts *MockTssServer) GetTimeline(sessionID string) (tss.SessionTimeline, error) {
+	if sessionID != "whatever" {
+		return tss.SessionTimeline{}, tss.ErrTimelineNotFound
+	}
+	return tss.SessionTimeline{
+		Summary: blame.TimelineSummary{
+			SessionID:  sessionID,
+			DurationMs: 1000,
+		},
+		Start: time.Now(),
+		Events: []blame.TimelineEvent{
+			{
+				Time:  time.Now(),
+				Event: blame.EventPartyFormed,
+			},
+		},
+	}, nil
+}
+
+func (mts *MockTssServer) SubscribeEvents(filter events.Filter) *events.Subscription {
+	return mts.eventBus.Subscribe(filter)
+}
@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/wrappers"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keygen"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/rpc"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+	"gitlab.com/thorchain/tss/go-tss/tss"
+)
+
+// progressBufferSize is how many progress events are kept for the slow stream, the newer ones are dropped once it
+// is full, so the tss session is never held up by the client
+const progressBufferSize = 64
+
+// grpcStopTimeout is how long the running requests are given to finish when the grpc server stops, the streams
+// still open by then are cut off
+const grpcStopTimeout = 10 * time.Second
+
+// TssGrpcServer provide the grpc service of the tss server, it runs next to the http server and shares the same
+// tss server, which is started and stopped by the http server
+type TssGrpcServer struct {
+	logger      zerolog.Logger
+	tssServer   tss.Server
+	addr        string
+	s           *grpc.Server
+	stopTimeout time.Duration
+}
+
+// NewTssGrpcServer should only listen to the loopback
+func NewTssGrpcServer(addr string, t tss.Server) *TssGrpcServer {
+	gs := &TssGrpcServer{
+		logger:      log.With().Str("module", "grpc").Logger(),
+		tssServer:   t,
+		addr:        addr,
+		s:           grpc.NewServer(),
+		stopTimeout: grpcStopTimeout,
+	}
+	rpc.RegisterTssServer(gs.s, gs)
+	return gs
+}
+
+// NewSecureTssGrpcServer create the grpc server protected by the TLS and the bearer token of the given config, the
+// HMAC request signing is http only, so the grpc server refuses to start when it is the only credential
+func NewSecureTssGrpcServer(addr string, t tss.Server, conf SecurityConfig) (*TssGrpcServer, error) {
+	auth, err := newAuthenticator(conf)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil && auth.hmacKey != nil && auth.token == nil {
+		return nil, errors.New("the grpc api needs the auth token when the HMAC key is set")
+	}
+	tlsConf, err := newTLSConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	var opts []grpc.ServerOption
+	if tlsConf != nil {
+		if tlsConf.ClientCAs != nil {
+			tlsConf.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConf)))
+	}
+	if auth != nil && auth.token != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				if err := auth.checkToken(ctx); err != nil {
+					return nil, err
+				}
+				return handler(ctx, req)
+			}),
+			grpc.StreamInterceptor(func(srv interface{}, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				if err := auth.checkToken(stream.Context()); err != nil {
+					return err
+				}
+				return handler(srv, stream)
+			}),
+		)
+	}
+	gs := &TssGrpcServer{
+		logger:      log.With().Str("module", "grpc").Logger(),
+		tssServer:   t,
+		addr:        addr,
+		s:           grpc.NewServer(opts...),
+		stopTimeout: grpcStopTimeout,
+	}
+	rpc.RegisterTssServer(gs.s, gs)
+	return gs, nil
+}
+
+// Start listen to the given address and serve the grpc requests till it is stopped
+func (t *TssGrpcServer) Start() error {
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("fail to listen to %s: %w", t.addr, err)
+	}
+	return t.Serve(listener)
+}
+
+// Serve the grpc requests from the given listener till it is stopped
+func (t *TssGrpcServer) Serve(listener net.Listener) error {
+	if err := t.s.Serve(listener); err != nil && err != grpc.ErrServerStopped {
+		return fmt.Errorf("fail to start grpc server: %w", err)
+	}
+	return nil
+}
+
+// Stop the grpc server, the running requests are left to finish within the stop timeout, after which the server
+// is stopped hard, so a client holding a stream open doesn't keep the node from shutting down
+func (t *TssGrpcServer) Stop() {
+	stopped := make(chan struct{})
+	go func() {
+		t.s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(t.stopTimeout):
+		t.logger.Warn().Msg("the grpc requests are not finished in time, stop the server anyway")
+		t.s.Stop()
+		<-stopped
+	}
+}
+
+func (t *TssGrpcServer) Keygen(ctx context.Context, req *rpc.KeygenRequest) (*rpc.KeygenResponse, error) {
+	t.logger.Info().Msg("receive key gen request")
+	resp, err := t.tssServer.KeygenWithContext(ctx, toKeygenRequest(req))
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to key gen")
+		return nil, toStatusError(err)
+	}
+	return toKeygenResponse(resp), nil
+}
+
+func (t *TssGrpcServer) KeygenStream(req *rpc.KeygenRequest, stream rpc.Tss_KeygenStreamServer) error {
+	t.logger.Info().Msg("receive key gen stream request")
+	var resp keygen.Response
+	err := t.streamProgress(func(listener tss.ProgressListener) error {
+		var err error
+		resp, err = t.tssServer.KeygenWithProgress(stream.Context(), toKeygenRequest(req), listener)
+		return err
+	}, func(event *rpc.ProgressEvent) error {
+		return stream.Send(&rpc.KeygenProgress{Event: event})
+	})
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to key gen")
+		return err
+	}
+	return stream.Send(&rpc.KeygenProgress{
+		Event:    &rpc.ProgressEvent{Type: rpc.ProgressEvent_Finished},
+		Response: toKeygenResponse(resp),
+	})
+}
+
+func (t *TssGrpcServer) KeySign(ctx context.Context, req *rpc.KeySignRequest) (*rpc.KeySignResponse, error) {
+	t.logger.Info().Msg("receive key sign request")
+	resp, err := t.tssServer.KeySignWithContext(ctx, toKeySignRequest(req))
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to key sign")
+		return nil, toStatusError(err)
+	}
+	return toKeySignResponse(resp), nil
+}
+
+func (t *TssGrpcServer) KeySignStream(req *rpc.KeySignRequest, stream rpc.Tss_KeySignStreamServer) error {
+	t.logger.Info().Msg("receive key sign stream request")
+	var resp keysign.Response
+	err := t.streamProgress(func(listener tss.ProgressListener) error {
+		var err error
+		resp, err = t.tssServer.KeySignWithProgress(stream.Context(), toKeySignRequest(req), listener)
+		return err
+	}, func(event *rpc.ProgressEvent) error {
+		return stream.Send(&rpc.KeySignProgress{Event: event})
+	})
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to key sign")
+		return err
+	}
+	return stream.Send(&rpc.KeySignProgress{
+		Event:    &rpc.ProgressEvent{Type: rpc.ProgressEvent_Finished},
+		Response: toKeySignResponse(resp),
+	})
+}
+
+// streamProgress run the session and send its progress till it is finished, the error of the session is returned
+// as the grpc status error
+func (t *TssGrpcServer) streamProgress(run func(listener tss.ProgressListener) error, send func(*rpc.ProgressEvent) error) error {
+	events := make(chan tss.ProgressEvent, progressBufferSize)
+	listener := func(event tss.ProgressEvent) {
+		select {
+		case events <- event:
+		default:
+			t.logger.Warn().Str("event", string(event.Type)).Msg("progress stream is full, drop the event")
+		}
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- run(listener)
+	}()
+	var sendErr error
+	sendEvent := func(event tss.ProgressEvent) {
+		// once the client is gone, the session is cancelled with the context of the stream
+		if sendErr != nil {
+			return
+		}
+		if err := send(toProgressEvent(event)); err != nil {
+			t.logger.Error().Err(err).Msg("fail to send the progress")
+			sendErr = err
+		}
+	}
+	for {
+		select {
+		case event := <-events:
+			sendEvent(event)
+		case err := <-done:
+			for len(events) > 0 {
+				sendEvent(<-events)
+			}
+			if err != nil {
+				return toStatusError(err)
+			}
+			return sendErr
+		}
+	}
+}
+
+func (t *TssGrpcServer) GetStatus(_ context.Context, _ *rpc.GetStatusRequest) (*rpc.GetStatusResponse, error) {
+	s := t.tssServer.GetStatus()
+	return &rpc.GetStatusResponse{
+		StartTime:         s.Starttime.Unix(),
+		SuccessfulKeygen:  s.SucKeyGen,
+		FailedKeygen:      s.FailedKeyGen,
+		SuccessfulKeysign: s.SucKeySign,
+		FailedKeysign:     s.FailedKeySign,
+		PreParamPoolDepth: int32(s.PreParamPoolDepth),
+	}, nil
+}
+
+func (t *TssGrpcServer) GetLocalPeerID(_ context.Context, _ *rpc.GetLocalPeerIDRequest) (*rpc.GetLocalPeerIDResponse, error) {
+	return &rpc.GetLocalPeerIDResponse{PeerId: t.tssServer.GetLocalPeerID()}, nil
+}
+
+func (t *TssGrpcServer) ListKeys(_ context.Context, _ *rpc.ListKeysRequest) (*rpc.ListKeysResponse, error) {
+	keys, err := t.tssServer.ListLocalKeys()
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to list the local keys")
+		return nil, toStatusError(err)
+	}
+	resp := &rpc.ListKeysResponse{}
+	for _, el := range keys {
+		resp.Keys = append(resp.Keys, toLocalKey(el))
+	}
+	return resp, nil
+}
+
+func (t *TssGrpcServer) GetKey(_ context.Context, req *rpc.GetKeyRequest) (*rpc.LocalKey, error) {
+	key, err := t.tssServer.GetLocalKey(req.GetPubKey())
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the local key")
+		return nil, toStatusError(err)
+	}
+	return toLocalKey(key), nil
+}
+
+// toStatusError map the error of the tss server to the grpc status error
+func toStatusError(err error) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, tss.ErrKeyNotFound):
+		code = codes.NotFound
+	case errors.Is(err, tss.ErrKeyRetired), errors.Is(err, tss.ErrKeyRetiring):
+		code = codes.FailedPrecondition
+	case errors.Is(err, tss.ErrDraining), errors.Is(err, common.ErrCurveBusy):
+		code = codes.Unavailable
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	}
+	return status.Error(code, err.Error())
+}
+
+func toKeygenRequest(req *rpc.KeygenRequest) keygen.Request {
+	return keygen.Request{
+		Keys:         req.GetKeys(),
+		Threshold:    int(req.GetThreshold()),
+		Algo:         common.Algo(req.GetAlgo()),
+		BlockHeight:  req.GetBlockHeight(),
+		CosmosPrefix: req.GetCosmosPrefix(),
+	}
+}
+
+func toKeygenResponse(resp keygen.Response) *rpc.KeygenResponse {
+	return &rpc.KeygenResponse{
+		PubKey:      resp.PubKey,
+		PoolAddress: resp.PoolAddress,
+		Status:      rpc.Status(resp.Status),
+		Blame:       toBlame(resp.Blame),
+		PubKeyInfo:  toPubKeyInfo(resp.PubKeyInfo),
+		Timeline:    toTimeline(resp.Timeline),
+	}
+}
+
+func toPubKeyInfo(info *conversion.PubKeyInfo) *rpc.PubKeyInfo {
+	if info == nil {
+		return nil
+	}
+	return &rpc.PubKeyInfo{
+		PubKeyCompressed:   info.PubKeyCompressed,
+		PubKeyUncompressed: info.PubKeyUncompressed,
+		BtcP2Wpkh:          info.BTCP2WPKH,
+		BtcP2Pkh:           info.BTCP2PKH,
+		Eth:                info.ETH,
+		Cosmos:             info.Cosmos,
+	}
+}
+
+func toTimeline(timeline *blame.TimelineSummary) *rpc.TimelineSummary {
+	if timeline == nil {
+		return nil
+	}
+	result := &rpc.TimelineSummary{
+		SessionId:     timeline.SessionID,
+		PartyFormedMs: timeline.PartyFormedMs,
+		DurationMs:    timeline.DurationMs,
+		ShareRequests: int32(timeline.ShareRequests),
+	}
+	for _, el := range timeline.Rounds {
+		result.Rounds = append(result.Rounds, &rpc.RoundSummary{
+			Round:      el.Round,
+			LocalMs:    el.LocalMs,
+			Received:   int32(el.Received),
+			Confirmed:  int32(el.Confirmed),
+			LastPeerMs: el.LastPeerMs,
+			LastPeer:   el.LastPeer,
+		})
+	}
+	return result
+}
+
+func toKeySignRequest(req *rpc.KeySignRequest) keysign.Request {
+	return keysign.Request{
+		PoolPubKey:     req.GetPoolPubKey(),
+		Message:        req.GetMessage(),
+		SignerPubKeys:  req.GetSignerPubKeys(),
+		Algo:           common.Algo(req.GetAlgo()),
+		DerivationPath: req.GetDerivationPath(),
+		BlockHeight:    req.GetBlockHeight(),
+		Migration:      req.GetMigration(),
+		Metadata:       req.GetMetadata(),
+	}
+}
+
+func toKeySignResponse(resp keysign.Response) *rpc.KeySignResponse {
+	return &rpc.KeySignResponse{
+		R:          resp.R,
+		S:          resp.S,
+		RecoveryId: toRecoveryID(resp.RecoveryID),
+		Status:     rpc.Status(resp.Status),
+		Blame:      toBlame(resp.Blame),
+		Timeline:   toTimeline(resp.Timeline),
+	}
+}
+
+// toRecoveryID return the recovery id, it is not set for the EdDSA signature
+func toRecoveryID(recoveryID *int) *wrappers.Int32Value {
+	if recoveryID == nil {
+		return nil
+	}
+	return &wrappers.Int32Value{Value: int32(*recoveryID)}
+}
+
+func toBlame(b blame.Blame) *rpc.Blame {
+	result := &rpc.Blame{
+		FailReason: b.FailReason,
+		IsUnicast:  b.IsUnicast,
+	}
+	for _, el := range b.BlameNodes {
+		result.BlameNodes = append(result.BlameNodes, &rpc.BlameNode{
+			Pubkey:    el.Pubkey,
+			Data:      el.BlameData,
+			Signature: el.BlameSignature,
+		})
+	}
+	return result
+}
+
+func toProgressEvent(event tss.ProgressEvent) *rpc.ProgressEvent {
+	result := &rpc.ProgressEvent{
+		Round:     int32(event.Round),
+		RoundName: event.RoundName,
+		Peer:      event.Peer,
+	}
+	switch event.Type {
+	case tss.ProgressPartyJoined:
+		result.Type = rpc.ProgressEvent_PartyJoined
+	case tss.ProgressRoundStarted:
+		result.Type = rpc.ProgressEvent_RoundStarted
+	case tss.ProgressShareRequested:
+		result.Type = rpc.ProgressEvent_ShareRequested
+	}
+	return result
+}
+
+func toLocalKey(info storage.LocalStateInfo) *rpc.LocalKey {
+	key := &rpc.LocalKey{
+		PubKey:          info.PubKey,
+		Algo:            string(info.Algo),
+		State:           string(info.State),
+		ParticipantKeys: info.ParticipantKeys,
+		LocalPartyKey:   info.LocalPartyKey,
+		Threshold:       int32(info.Threshold),
+		ShareId:         info.ShareID,
+		PublicShares:    info.PublicShares,
+		Error:           info.Error,
+	}
+	if !info.ModifiedAt.IsZero() {
+		key.ModifiedAt = info.ModifiedAt.Unix()
+	}
+	return key
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keygen"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/rpc"
+)
+
+type TssGrpcServerTestSuite struct {
+	mock   *MockTssServer
+	server *TssGrpcServer
+	conn   *grpc.ClientConn
+	client rpc.TssClient
+}
+
+var _ = Suite(&TssGrpcServerTestSuite{})
+
+func (s *TssGrpcServerTestSuite) SetUpTest(c *C) {
+	listener := bufconn.Listen(1024 * 1024)
+	s.mock = &MockTssServer{}
+	s.server = NewTssGrpcServer("", s.mock)
+	go func() {
+		c.Check(s.server.Serve(listener), IsNil)
+	}()
+	var err error
+	s.conn, err = grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.Dial()
+	}))
+	c.Assert(err, IsNil)
+	s.client = rpc.NewTssClient(s.conn)
+}
+
+func (s *TssGrpcServerTestSuite) TearDownTest(c *C) {
+	c.Assert(s.conn.Close(), IsNil)
+	s.server.Stop()
+}
+
+func (s *TssGrpcServerTestSuite) TestKeygen(c *C) {
+	resp, err := s.client.Keygen(context.Background(), &rpc.KeygenRequest{Keys: []string{"whatever"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.GetStatus(), Equals, rpc.Status_Success)
+	c.Assert(resp.GetPubKey(), Not(Equals), "")
+
+	s.mock.failToKeyGen = true
+	_, err = s.client.Keygen(context.Background(), &rpc.KeygenRequest{Keys: []string{"whatever"}})
+	c.Assert(status.Code(err), Equals, codes.Internal)
+}
+
+func (s *TssGrpcServerTestSuite) TestStopWithRequestRunning(c *C) {
+	s.mock.stuckInKeyGen = true
+	s.server.stopTimeout = time.Millisecond * 100
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := s.client.Keygen(context.Background(), &rpc.KeygenRequest{Keys: []string{"whatever"}})
+		errCh <- err
+	}()
+	// give the request the time to reach the server
+	time.Sleep(time.Millisecond * 100)
+	stopped := make(chan struct{})
+	go func() {
+		s.server.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second * 5):
+		c.Fatal("the grpc server should be stopped after the stop timeout")
+	}
+	c.Assert(<-errCh, NotNil)
+}
+
+func (s *TssGrpcServerTestSuite) TestKeygenStream(c *C) {
+	stream, err := s.client.KeygenStream(context.Background(), &rpc.KeygenRequest{Keys: []string{"whatever"}})
+	c.Assert(err, IsNil)
+	var events []*rpc.KeygenProgress
+	for {
+		progress, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, IsNil)
+		events = append(events, progress)
+	}
+	c.Assert(events, HasLen, 3)
+	c.Assert(events[0].GetEvent().GetType(), Equals, rpc.ProgressEvent_PartyJoined)
+	c.Assert(events[1].GetEvent().GetType(), Equals, rpc.ProgressEvent_RoundStarted)
+	c.Assert(events[1].GetEvent().GetRound(), Equals, int32(1))
+	c.Assert(events[2].GetEvent().GetType(), Equals, rpc.ProgressEvent_Finished)
+	c.Assert(events[2].GetResponse().GetStatus(), Equals, rpc.Status_Success)
+}
+
+func (s *TssGrpcServerTestSuite) TestKeySignStream(c *C) {
+	s.mock.failToKeySign = true
+	stream, err := s.client.KeySignStream(context.Background(), &rpc.KeySignRequest{
+		PoolPubKey: "whatever",
+		Message:    "whatever",
+	})
+	c.Assert(err, IsNil)
+	for {
+		_, err = stream.Recv()
+		if err != nil {
+			break
+		}
+	}
+	c.Assert(status.Code(err), Equals, codes.Internal)
+}
+
+func (s *TssGrpcServerTestSuite) TestToKeygenResponse(c *C) {
+	resp := keygen.NewResponse("pubkey", "addr", common.Success, blame.Blame{})
+	result := toKeygenResponse(resp)
+	c.Assert(result.GetPubKeyInfo(), IsNil)
+	c.Assert(result.GetTimeline(), IsNil)
+
+	resp.PubKeyInfo = &conversion.PubKeyInfo{
+		PubKeyCompressed: "02ab",
+		BTCP2WPKH:        "bc1q",
+		BTCP2PKH:         "1abc",
+		ETH:              "0xabc",
+		Cosmos:           "cosmos1abc",
+	}
+	resp.Timeline = &blame.TimelineSummary{
+		SessionID:     "session",
+		PartyFormedMs: 10,
+		DurationMs:    100,
+		ShareRequests: 1,
+		Rounds:        []blame.RoundSummary{{Round: "round1", LocalMs: 20, Received: 3, Confirmed: 2, LastPeer: "peer"}},
+	}
+	result = toKeygenResponse(resp)
+	c.Assert(result.GetPubKeyInfo().GetPubKeyCompressed(), Equals, "02ab")
+	c.Assert(result.GetPubKeyInfo().GetBtcP2Wpkh(), Equals, "bc1q")
+	c.Assert(result.GetPubKeyInfo().GetBtcP2Pkh(), Equals, "1abc")
+	c.Assert(result.GetPubKeyInfo().GetEth(), Equals, "0xabc")
+	c.Assert(result.GetPubKeyInfo().GetCosmos(), Equals, "cosmos1abc")
+	c.Assert(result.GetTimeline().GetSessionId(), Equals, "session")
+	c.Assert(result.GetTimeline().GetDurationMs(), Equals, int64(100))
+	c.Assert(result.GetTimeline().GetRounds(), HasLen, 1)
+	c.Assert(result.GetTimeline().GetRounds()[0].GetReceived(), Equals, int32(3))
+	c.Assert(result.GetTimeline().GetRounds()[0].GetLastPeer(), Equals, "peer")
+}
+
+func (s *TssGrpcServerTestSuite) TestToKeySignResponse(c *C) {
+	resp := toKeySignResponse(keysign.NewResponse("r", "s", keysign.GetRecoveryID([]byte{1}), common.Success, blame.Blame{}))
+	c.Assert(resp.GetRecoveryId(), NotNil)
+	c.Assert(resp.GetRecoveryId().GetValue(), Equals, int32(1))
+	// the recovery id 0 is set as well
+	resp = toKeySignResponse(keysign.NewResponse("r", "s", keysign.GetRecoveryID([]byte{0}), common.Success, blame.Blame{}))
+	c.Assert(resp.GetRecoveryId(), NotNil)
+	c.Assert(resp.GetRecoveryId().GetValue(), Equals, int32(0))
+	// EdDSA
+	resp = toKeySignResponse(keysign.NewResponse("r", "s", nil, common.Success, blame.Blame{}))
+	c.Assert(resp.GetRecoveryId(), IsNil)
+	c.Assert(resp.GetTimeline(), IsNil)
+}
+
+func (s *TssGrpcServerTestSuite) TestKeys(c *C) {
+	keys, err := s.client.ListKeys(context.Background(), &rpc.ListKeysRequest{})
+	c.Assert(err, IsNil)
+	c.Assert(keys.GetKeys(), HasLen, 1)
+	key, err := s.client.GetKey(context.Background(), &rpc.GetKeyRequest{PubKey: keys.GetKeys()[0].GetPubKey()})
+	c.Assert(err, IsNil)
+	c.Assert(key.GetState(), Equals, "active")
+	_, err = s.client.GetKey(context.Background(), &rpc.GetKeyRequest{PubKey: "whatever"})
+	c.Assert(status.Code(err), Equals, codes.NotFound)
+
+	peerID, err := s.client.GetLocalPeerID(context.Background(), &rpc.GetLocalPeerIDRequest{})
+	c.Assert(err, IsNil)
+	c.Assert(peerID.GetPeerId(), Not(Equals), "")
+}
@@ -2,26 +2,45 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
 	"gitlab.com/thorchain/tss/go-tss/tss"
 )
 
+// eventsKeepAliveInterval is how often the comment is sent on the idle event stream, so the proxies keep it open
+const eventsKeepAliveInterval = 15 * time.Second
+
+// JobSubmitResponse is the response of the asynchronous keygen/keysign requests
+type JobSubmitResponse struct {
+	JobID string `json:"job_id"`
+}
+
 // TssHttpServer provide http endpoint for tss server
 type TssHttpServer struct {
-	logger    zerolog.Logger
-	tssServer tss.Server
-	s         *http.Server
+	logger         zerolog.Logger
+	tssServer      tss.Server
+	s              *http.Server
+	unixSocket     string
+	unixSocketMode string
 }
 
 // NewTssHttpServer should only listen to the loopback
@@ -38,11 +57,49 @@ func NewTssHttpServer(tssAddr string, t tss.Server) *TssHttpServer {
 	return hs
 }
 
+// NewSecureTssHttpServer create the http server protected by the given config, the server doesn't listen to the tcp
+// address if it is empty, so it can be only reached through the unix socket
+func NewSecureTssHttpServer(tssAddr string, t tss.Server, conf SecurityConfig) (*TssHttpServer, error) {
+	hs := NewTssHttpServer(tssAddr, t)
+	auth, err := newAuthenticator(conf)
+	if err != nil {
+		return nil, err
+	}
+	if auth != nil {
+		hs.s.Handler = auth.middleware(hs.s.Handler)
+	}
+	if hs.s.TLSConfig, err = newTLSConfig(conf); err != nil {
+		return nil, err
+	}
+	if len(tssAddr) == 0 && len(conf.UnixSocket) == 0 {
+		return nil, errors.New("neither the tcp address nor the unix socket is given")
+	}
+	hs.unixSocket = conf.UnixSocket
+	hs.unixSocketMode = conf.UnixSocketMode
+	return hs, nil
+}
+
 // NewHandler registers the API routes and returns a new HTTP handler
 func (t *TssHttpServer) tssNewHandler() http.Handler {
 	router := mux.NewRouter()
 	router.Handle("/keygen", http.HandlerFunc(t.keygenHandler)).Methods(http.MethodPost)
 	router.Handle("/keysign", http.HandlerFunc(t.keySignHandler)).Methods(http.MethodPost)
+	router.Handle("/keysign/batch", http.HandlerFunc(t.keySignBatchHandler)).Methods(http.MethodPost)
+	router.Handle("/reshare", http.HandlerFunc(t.reshareHandler)).Methods(http.MethodPost)
+	router.Handle("/derive", http.HandlerFunc(t.deriveHandler)).Methods(http.MethodPost)
+	router.Handle("/pubkey/{pool_pub_key}", http.HandlerFunc(t.getPubKeyInfoHandler)).Methods(http.MethodGet)
+	router.Handle("/party/check", http.HandlerFunc(t.checkPartyHandler)).Methods(http.MethodPost)
+	router.Handle("/keys", http.HandlerFunc(t.listKeysHandler)).Methods(http.MethodGet)
+	router.Handle("/keys/{pub_key}", http.HandlerFunc(t.getKeyHandler)).Methods(http.MethodGet)
+	router.Handle("/keys/{pub_key}/state", http.HandlerFunc(t.setKeyStateHandler)).Methods(http.MethodPost)
+	router.Handle("/jobs/keygen", http.HandlerFunc(t.keygenJobHandler)).Methods(http.MethodPost)
+	router.Handle("/jobs/keysign", http.HandlerFunc(t.keySignJobHandler)).Methods(http.MethodPost)
+	router.Handle("/jobs/{id}", http.HandlerFunc(t.getJobHandler)).Methods(http.MethodGet)
+	router.Handle("/jobs/{id}", http.HandlerFunc(t.cancelJobHandler)).Methods(http.MethodDelete)
+	router.Handle("/audit", http.HandlerFunc(t.getAuditHandler)).Methods(http.MethodGet)
+	router.Handle("/debug/timeline/{session_id}", http.HandlerFunc(t.getTimelineHandler)).Methods(http.MethodGet)
+	router.Handle("/events", http.HandlerFunc(t.eventsHandler)).Methods(http.MethodGet)
+	router.Handle("/metrics", monitor.Handler()).Methods(http.MethodGet)
 	router.Handle("/status", http.HandlerFunc(t.getNodeStatusHandler)).Methods(http.MethodGet)
 	router.Handle("/ping", http.HandlerFunc(t.pingHandler)).Methods(http.MethodGet)
 	router.Handle("/p2pid", http.HandlerFunc(t.getP2pIDHandler)).Methods(http.MethodGet)
@@ -72,7 +129,7 @@ func (t *TssHttpServer) keygenHandler(w http.ResponseWriter, r *http.Request) {
 	resp, err := t.tssServer.Keygen(keygenReq)
 	if err != nil {
 		t.logger.Error().Err(err).Msg("fail to key gen")
-		w.WriteHeader(http.StatusInternalServerError)
+		writeSessionError(w, err)
 		return
 	}
 	t.logger.Info().Msgf("resp:%+v", resp)
@@ -111,7 +168,7 @@ func (t *TssHttpServer) keySignHandler(w http.ResponseWriter, r *http.Request) {
 	signResp, err := t.tssServer.KeySign(keySignReq)
 	if err != nil {
 		t.logger.Error().Err(err).Msg("fail to key sign")
-		w.WriteHeader(http.StatusInternalServerError)
+		writeSessionError(w, err)
 		return
 	}
 
@@ -127,6 +184,467 @@ func (t *TssHttpServer) keySignHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (t *TssHttpServer) keySignBatchHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	t.logger.Info().Msg("receive batch key sign request")
+
+	var keySignReq keysign.BatchRequest
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&keySignReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode batch key sign request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	t.logger.Info().Msgf("request:%+v", keySignReq)
+	signResps, err := t.tssServer.KeySignBatch(keySignReq)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to batch key sign")
+		writeSessionError(w, err)
+		return
+	}
+
+	jsonResult, err := json.MarshalIndent(signResps, "", "	")
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json message")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(jsonResult)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write response")
+	}
+}
+
+func (t *TssHttpServer) reshareHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	t.logger.Info().Msg("receive reshare request")
+	decoder := json.NewDecoder(r.Body)
+	var reshareReq reshare.Request
+	if err := decoder.Decode(&reshareReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode reshare request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := t.tssServer.Reshare(reshareReq)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to reshare")
+		writeSessionError(w, err)
+		return
+	}
+	t.logger.Info().Msgf("resp:%+v", resp)
+	buf, err := json.Marshal(resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) deriveHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	decoder := json.NewDecoder(r.Body)
+	var deriveReq keysign.DeriveRequest
+	if err := decoder.Decode(&deriveReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode derive request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := t.tssServer.DerivePubKey(deriveReq)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to derive the child key")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	buf, err := json.Marshal(resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) getPubKeyInfoHandler(w http.ResponseWriter, r *http.Request) {
+	pubKeyInfo, err := t.tssServer.GetPubKeyInfo(mux.Vars(r)["pool_pub_key"], r.URL.Query().Get("cosmos_prefix"))
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the encodings of the pub key")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	buf, err := json.Marshal(pubKeyInfo)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) checkPartyHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	decoder := json.NewDecoder(r.Body)
+	var checkReq tss.PartyCheckRequest
+	if err := decoder.Decode(&checkReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode party check request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	resp, err := t.tssServer.CheckParty(r.Context(), checkReq.Keys)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to check the party")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	buf, err := json.Marshal(resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) listKeysHandler(w http.ResponseWriter, _ *http.Request) {
+	keys, err := t.tssServer.ListLocalKeys()
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to list the local keys")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	t.writeJSON(w, keys)
+}
+
+// getAuditHandler return the latest entries of the audit log, the number of entries is set by the limit query parameter,
+// up to audit.MaxLimit
+func (t *TssHttpServer) getAuditHandler(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if s := r.URL.Query().Get("limit"); len(s) > 0 {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit <= 0 {
+			t.logger.Error().Str("limit", s).Msg("invalid limit")
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	entries, err := t.tssServer.GetAuditEntries(limit)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the audit entries")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	t.writeJSON(w, entries)
+}
+
+// eventsHandler stream the events of the tss sessions as the server-sent events till the client is gone, the events
+// can be filtered by the session id and the types, like /events?session_id=abc&type=party_formed,session_finished
+func (t *TssHttpServer) eventsHandler(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		t.logger.Error().Msg("the response writer doesn't support streaming")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	filter := events.Filter{SessionID: r.URL.Query().Get("session_id")}
+	for _, el := range r.URL.Query()["type"] {
+		for _, eventType := range strings.Split(el, ",") {
+			if !events.IsKnownType(eventType) {
+				t.logger.Error().Str("type", eventType).Msg("unknown event type")
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			filter.Types = append(filter.Types, eventType)
+		}
+	}
+	sub := t.tssServer.SubscribeEvents(filter)
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	keepAlive := time.NewTicker(eventsKeepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			buf, err := json.Marshal(e)
+			if err != nil {
+				t.logger.Error().Err(err).Msg("fail to marshal the event to json")
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, buf); err != nil {
+				t.logger.Error().Err(err).Msg("fail to write the event")
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// getTimelineHandler return the timeline of the given session, only the timelines of the latest sessions are kept
+func (t *TssHttpServer) getTimelineHandler(w http.ResponseWriter, r *http.Request) {
+	timeline, err := t.tssServer.GetTimeline(mux.Vars(r)["session_id"])
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the session timeline")
+		if errors.Is(err, tss.ErrTimelineNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	t.writeJSON(w, timeline)
+}
+
+func (t *TssHttpServer) getKeyHandler(w http.ResponseWriter, r *http.Request) {
+	key, err := t.tssServer.GetLocalKey(mux.Vars(r)["pub_key"])
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the local key")
+		if errors.Is(err, tss.ErrKeyNotFound) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	t.writeJSON(w, key)
+}
+
+func (t *TssHttpServer) setKeyStateHandler(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	decoder := json.NewDecoder(r.Body)
+	var stateReq tss.KeyStateRequest
+	if err := decoder.Decode(&stateReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode key state request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	pubKey := mux.Vars(r)["pub_key"]
+	t.logger.Info().Str("pub key", pubKey).Str("state", string(stateReq.State)).Msg("received key state request")
+	key, err := t.tssServer.SetKeyState(pubKey, stateReq.State)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to set the key state")
+		switch {
+		case errors.Is(err, tss.ErrKeyNotFound):
+			w.WriteHeader(http.StatusNotFound)
+		case errors.Is(err, tss.ErrInvalidKeyState):
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+		return
+	}
+	t.writeJSON(w, key)
+}
+
+func (t *TssHttpServer) writeJSON(w http.ResponseWriter, resp interface{}) {
+	buf, err := json.Marshal(resp)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) keygenJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	t.logger.Info().Msg("receive key gen job request")
+	decoder := json.NewDecoder(r.Body)
+	var keygenReq keygen.Request
+	if err := decoder.Decode(&keygenReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode keygen request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	jobID, err := t.tssServer.KeygenAsync(keygenReq)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to submit key gen job")
+		writeSessionError(w, err)
+		return
+	}
+	t.writeJobSubmitted(w, jobID)
+}
+
+func (t *TssHttpServer) keySignJobHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	defer func() {
+		if err := r.Body.Close(); nil != err {
+			t.logger.Error().Err(err).Msg("fail to close request body")
+		}
+	}()
+	t.logger.Info().Msg("receive key sign job request")
+
+	var keySignReq keysign.Request
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&keySignReq); nil != err {
+		t.logger.Error().Err(err).Msg("fail to decode key sign request")
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	t.logger.Info().Msgf("request:%+v", keySignReq)
+	jobID, err := t.tssServer.KeySignAsync(keySignReq)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to submit key sign job")
+		writeSessionError(w, err)
+		return
+	}
+	t.writeJobSubmitted(w, jobID)
+}
+
+func (t *TssHttpServer) writeJobSubmitted(w http.ResponseWriter, jobID string) {
+	buf, err := json.Marshal(JobSubmitResponse{JobID: jobID})
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to marshal response to json")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+	_, err = w.Write(buf)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to write to response")
+	}
+}
+
+func (t *TssHttpServer) getJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := t.tssServer.GetJob(mux.Vars(r)["id"])
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get job")
+		w.WriteHeader(jobErrorToStatus(err))
+		return
+	}
+	t.writeJob(w, job)
+}
+
+func (t *TssHttpServer) cancelJobHandler(w http.ResponseWriter, r *http.Request) {
+	job, err := t.tssServer.CancelJob(mux.Vars(r)["id"])
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to cancel job")
+		w.WriteHeader(jobErrorToStatus(err))
+		return
+	}
+	t.writeJob(w, job)
+}
+
+func (t *TssHttpServer) writeJob(w http.ResponseWriter, job tss.Job) {
+	t.writeJSON(w, job)
+}
+
+func jobErrorToStatus(err error) int {
+	switch {
+	case errors.Is(err, tss.ErrJobNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, tss.ErrJobFinished):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// sessionErrorToStatus return 503 when the server is draining, so the client can tell the session is never started,
+// and 409 or 412 when the state of the pool key doesn't let it sign the request
+func sessionErrorToStatus(err error) int {
+	switch {
+	case errors.Is(err, tss.ErrDraining), errors.Is(err, common.ErrCurveBusy):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, tss.ErrKeyRetired):
+		return http.StatusConflict
+	case errors.Is(err, tss.ErrKeyRetiring):
+		return http.StatusPreconditionFailed
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeSessionError write the status of the given session error, the reason is sent along unless it is an internal
+// error, so the client can tell why the session is refused
+func writeSessionError(w http.ResponseWriter, err error) {
+	code := sessionErrorToStatus(err)
+	if code == http.StatusInternalServerError {
+		w.WriteHeader(code)
+		return
+	}
+	http.Error(w, err.Error(), code)
+}
+
 func (t *TssHttpServer) getNodeStatusHandler(w http.ResponseWriter, _ *http.Request) {
 	buf, err := json.Marshal(t.tssServer.GetStatus())
 	if err != nil {
@@ -147,12 +665,55 @@ func (t *TssHttpServer) Start() error {
 	if err := t.tssServer.Start(); err != nil {
 		return fmt.Errorf("fail to start tss server: %w", err)
 	}
-	if err := t.s.ListenAndServe(); err != nil {
-		if err != http.ErrServerClosed {
-			return fmt.Errorf("fail to start http server: %w", err)
+	listeners, err := t.listen()
+	if err != nil {
+		return err
+	}
+	errCh := make(chan error, len(listeners))
+	for _, el := range listeners {
+		go func(l net.Listener) {
+			errCh <- t.serve(l)
+		}(el)
+	}
+	for range listeners {
+		if err := <-errCh; err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
+// listen to the tcp address and the unix socket, either of them can be omitted
+func (t *TssHttpServer) listen() ([]net.Listener, error) {
+	var listeners []net.Listener
+	if len(t.s.Addr) != 0 {
+		l, err := net.Listen("tcp", t.s.Addr)
+		if err != nil {
+			return nil, fmt.Errorf("fail to listen to %s: %w", t.s.Addr, err)
+		}
+		listeners = append(listeners, l)
+	}
+	if len(t.unixSocket) != 0 {
+		l, err := listenUnix(t.unixSocket, t.unixSocketMode)
+		if err != nil {
+			for _, el := range listeners {
+				_ = el.Close()
+			}
+			return nil, err
+		}
+		listeners = append(listeners, l)
+	}
+	return listeners, nil
+}
+
+// serve the requests from the given listener till the server is stopped, only the tcp listener uses TLS
+func (t *TssHttpServer) serve(l net.Listener) error {
+	if t.s.TLSConfig != nil && l.Addr().Network() == "tcp" {
+		l = tls.NewListener(l, t.s.TLSConfig)
+	}
+	if err := t.s.Serve(l); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("fail to start http server: %w", err)
+	}
 	return nil
 }
 
@@ -177,6 +738,11 @@ func (t *TssHttpServer) Stop() error {
 	if err != nil {
 		log.Error().Err(err).Msg("Failed to shutdown the Tss server gracefully")
 	}
+	if len(t.unixSocket) != 0 {
+		if err := os.Remove(t.unixSocket); err != nil && !os.IsNotExist(err) {
+			log.Error().Err(err).Msg("fail to remove the unix socket")
+		}
+	}
 	t.tssServer.Stop()
 	return err
 }
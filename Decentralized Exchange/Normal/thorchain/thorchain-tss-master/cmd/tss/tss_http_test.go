@@ -1,18 +1,27 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	. "gopkg.in/check.v1"
 
+	"gitlab.com/thorchain/tss/go-tss/audit"
 	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+	"gitlab.com/thorchain/tss/go-tss/tss"
 )
 
 func TestPackage(t *testing.T) { TestingT(t) }
@@ -22,6 +31,10 @@ type TssHttpServerTestSuite struct {
 
 var _ = Suite(&TssHttpServerTestSuite{})
 
+func (TssHttpServerTestSuite) SetUpSuite(c *C) {
+	conversion.SetupBech32Prefix()
+}
+
 func (TssHttpServerTestSuite) TestNewTssHttpServer(c *C) {
 	tssServer := &MockTssServer{}
 	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
@@ -71,6 +84,15 @@ func (TssHttpServerTestSuite) TestGetNodeStatusHandler(c *C) {
 	c.Assert(json.Unmarshal(res.Body.Bytes(), &status), IsNil)
 }
 
+func (TssHttpServerTestSuite) TestMetricsHandler(c *C) {
+	s := NewTssHttpServer("127.0.0.1:8080", &MockTssServer{})
+	c.Assert(s, NotNil)
+	res := httptest.NewRecorder()
+	s.tssNewHandler().ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	c.Assert(strings.Contains(res.Body.String(), "go_goroutines"), Equals, true)
+}
+
 func (TssHttpServerTestSuite) TestKeygenHandler(c *C) {
 	normalKeygenRequest := `{"keys":["thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3", "thorpub1addwnpepqtspqyy6gk22u37ztra4hq3hdakc0w0k60sfy849mlml2vrpfr0wvm6uz09", "thorpub1addwnpepq2ryyje5zr09lq7gqptjwnxqsy2vcdngvwd6z7yt5yjcnyj8c8cn559xe69", "thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j"]}`
 	testCases := []struct {
@@ -187,6 +209,34 @@ func (TssHttpServerTestSuite) TestKeysignHandler(c *C) {
 				c.Assert(w.Code, Equals, http.StatusInternalServerError)
 			},
 		},
+		{
+			name: "retired key should return status conflict with the reason",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/keysign",
+					bytes.NewBufferString(normalKeySignRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.keyState = storage.KeyArchived
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusConflict)
+				c.Assert(strings.TrimSpace(w.Body.String()), Equals, "the pool key is retired, its state is archived")
+			},
+		},
+		{
+			name: "retiring key should return status precondition failed with the reason",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/keysign",
+					bytes.NewBufferString(normalKeySignRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.keyState = storage.KeyRetiring
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusPreconditionFailed)
+				c.Assert(strings.TrimSpace(w.Body.String()), Equals, tss.ErrKeyRetiring.Error())
+			},
+		},
 		{
 			name: "normal",
 			reqProvider: func() *http.Request {
@@ -215,3 +265,530 @@ func (TssHttpServerTestSuite) TestKeysignHandler(c *C) {
 		tc.resultChecker(c, res)
 	}
 }
+
+func (TssHttpServerTestSuite) TestKeysignBatchHandler(c *C) {
+	normalBatchKeySignRequest := `{
+    "pool_pub_key": "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+    "messages": ["aGVsbG93b3JsZA==", "aGVsbG93b3JsZDE="],
+    "signer_pub_keys": [
+        "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+        "thorpub1addwnpepqtspqyy6gk22u37ztra4hq3hdakc0w0k60sfy849mlml2vrpfr0wvm6uz09",
+        "thorpub1addwnpepq2ryyje5zr09lq7gqptjwnxqsy2vcdngvwd6z7yt5yjcnyj8c8cn559xe69",
+        "thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j"
+    ]
+}`
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "method get should return status method not allowed",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/keysign/batch", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+			},
+		},
+		{
+			name: "nil request body should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/keysign/batch", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "fail to keysign should return status internal server error",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/keysign/batch",
+					bytes.NewBufferString(normalBatchKeySignRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToKeySign = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/keysign/batch",
+					bytes.NewBufferString(normalBatchKeySignRequest))
+			},
+
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+				var resp []keysign.Response
+				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+				c.Assert(resp, HasLen, 2)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.keySignBatchHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestJobHandlers(c *C) {
+	normalKeySignRequest := `{"pool_pub_key":"thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3","message":"helloworld","signer_pub_keys":["thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"]}`
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	handler := s.tssNewHandler()
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/jobs/keysign", nil))
+	c.Assert(res.Code, Equals, http.StatusBadRequest)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, "/jobs/keysign", bytes.NewBufferString(normalKeySignRequest)))
+	c.Assert(res.Code, Equals, http.StatusAccepted)
+	var submitResp JobSubmitResponse
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &submitResp), IsNil)
+	c.Assert(submitResp.JobID, Not(Equals), "")
+
+	var job tss.Job
+	for i := 0; i < 100; i++ {
+		res = httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/jobs/"+submitResp.JobID, nil))
+		c.Assert(res.Code, Equals, http.StatusOK)
+		c.Assert(json.Unmarshal(res.Body.Bytes(), &job), IsNil)
+		if job.State.IsFinished() {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Assert(job.State, Equals, tss.JobDone)
+	c.Assert(job.Type, Equals, tss.JobKeySign)
+
+	// finished job can't be cancelled
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/jobs/"+submitResp.JobID, nil))
+	c.Assert(res.Code, Equals, http.StatusConflict)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/jobs/whatever", nil))
+	c.Assert(res.Code, Equals, http.StatusNotFound)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodDelete, "/jobs/whatever", nil))
+	c.Assert(res.Code, Equals, http.StatusNotFound)
+}
+
+func (TssHttpServerTestSuite) TestReshareHandler(c *C) {
+	normalReshareRequest := `{"pool_pub_key":"thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3","old_party_keys":["thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3", "thorpub1addwnpepqtspqyy6gk22u37ztra4hq3hdakc0w0k60sfy849mlml2vrpfr0wvm6uz09", "thorpub1addwnpepq2ryyje5zr09lq7gqptjwnxqsy2vcdngvwd6z7yt5yjcnyj8c8cn559xe69"], "new_party_keys":["thorpub1addwnpepqtspqyy6gk22u37ztra4hq3hdakc0w0k60sfy849mlml2vrpfr0wvm6uz09", "thorpub1addwnpepq2ryyje5zr09lq7gqptjwnxqsy2vcdngvwd6z7yt5yjcnyj8c8cn559xe69", "thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j"]}`
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "method get should return status method not allowed",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/reshare", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusMethodNotAllowed)
+			},
+		},
+		{
+			name: "nil request body should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/reshare", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "fail to reshare should return status internal server error",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/reshare",
+					bytes.NewBufferString(normalReshareRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToReshare = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/reshare",
+					bytes.NewBufferString(normalReshareRequest))
+			},
+
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+				var resp reshare.Response
+				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.reshareHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestDeriveHandler(c *C) {
+	normalDeriveRequest := `{"pool_pub_key":"thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3","derivation_path":"m/0/1"}`
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "nil request body should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/derive", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "fail to derive should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/derive",
+					bytes.NewBufferString(normalDeriveRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToDerive = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/derive",
+					bytes.NewBufferString(normalDeriveRequest))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+				var resp keysign.DeriveResponse
+				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+				c.Assert(resp.PubKey, Not(Equals), "")
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.deriveHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestGetPubKeyInfoHandler(c *C) {
+	s := NewTssHttpServer("127.0.0.1:8080", &MockTssServer{})
+	handler := s.tssNewHandler()
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/pubkey/whatever", nil))
+	c.Assert(res.Code, Equals, http.StatusBadRequest)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/pubkey/thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3?cosmos_prefix=thor", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var info conversion.PubKeyInfo
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &info), IsNil)
+	c.Assert(strings.HasPrefix(info.Cosmos, "thor1"), Equals, true)
+	c.Assert(strings.HasPrefix(info.BTCP2WPKH, "bc1"), Equals, true)
+}
+
+func (TssHttpServerTestSuite) TestCheckPartyHandler(c *C) {
+	normalCheckRequest := `{"keys":["thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"]}`
+	testCases := []struct {
+		name          string
+		reqProvider   func() *http.Request
+		setter        func(s *MockTssServer)
+		resultChecker func(c *C, w *httptest.ResponseRecorder)
+	}{
+		{
+			name: "nil request body should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/party/check", nil)
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "fail to check should return status bad request",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/party/check",
+					bytes.NewBufferString(normalCheckRequest))
+			},
+			setter: func(s *MockTssServer) {
+				s.failToCheck = true
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "normal",
+			reqProvider: func() *http.Request {
+				return httptest.NewRequest(http.MethodPost, "/party/check",
+					bytes.NewBufferString(normalCheckRequest))
+			},
+			resultChecker: func(c *C, w *httptest.ResponseRecorder) {
+				c.Assert(w.Code, Equals, http.StatusOK)
+				var resp tss.PartyCheckResponse
+				c.Assert(json.Unmarshal(w.Body.Bytes(), &resp), IsNil)
+				c.Assert(resp.AllReachable, Equals, true)
+				c.Assert(resp.Nodes, HasLen, 1)
+				c.Assert(resp.Nodes[0].Reachable, Equals, true)
+			},
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := tc.reqProvider()
+		res := httptest.NewRecorder()
+		s.checkPartyHandler(res, req)
+		tc.resultChecker(c, res)
+	}
+}
+
+func (TssHttpServerTestSuite) TestKeysHandlers(c *C) {
+	poolPubKey := "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	handler := s.tssNewHandler()
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var keys []storage.LocalStateInfo
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &keys), IsNil)
+	c.Assert(keys, HasLen, 1)
+	c.Assert(keys[0].PubKey, Equals, poolPubKey)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys/"+poolPubKey, nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var key storage.LocalStateInfo
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &key), IsNil)
+	c.Assert(key.PubKey, Equals, poolPubKey)
+	c.Assert(key.Threshold, Equals, 1)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys/whatever", nil))
+	c.Assert(res.Code, Equals, http.StatusNotFound)
+
+	tssServer.failToListKeys = true
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys", nil))
+	c.Assert(res.Code, Equals, http.StatusInternalServerError)
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys/"+poolPubKey, nil))
+	c.Assert(res.Code, Equals, http.StatusInternalServerError)
+}
+
+func (TssHttpServerTestSuite) TestSetKeyStateHandler(c *C) {
+	poolPubKey := "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"
+	testCases := []struct {
+		name       string
+		pubKey     string
+		body       string
+		setter     func(s *MockTssServer)
+		statusCode int
+	}{
+		{
+			name:       "nil request body should return status bad request",
+			pubKey:     poolPubKey,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:       "unknown key should return status not found",
+			pubKey:     "whatever",
+			body:       `{"state":"retiring"}`,
+			statusCode: http.StatusNotFound,
+		},
+		{
+			name:       "invalid transition should return status bad request",
+			pubKey:     poolPubKey,
+			body:       `{"state":"archived"}`,
+			statusCode: http.StatusBadRequest,
+		},
+		{
+			name:   "fail to set state should return status internal server error",
+			pubKey: poolPubKey,
+			body:   `{"state":"retiring"}`,
+			setter: func(s *MockTssServer) {
+				s.failToSetState = true
+			},
+			statusCode: http.StatusInternalServerError,
+		},
+		{
+			name:       "normal",
+			pubKey:     poolPubKey,
+			body:       `{"state":"retiring"}`,
+			statusCode: http.StatusOK,
+		},
+	}
+	for _, tc := range testCases {
+		c.Log(tc.name)
+		tssServer := &MockTssServer{}
+		s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+		c.Assert(s, NotNil)
+		if tc.setter != nil {
+			tc.setter(tssServer)
+		}
+		req := httptest.NewRequest(http.MethodPost, "/keys/"+tc.pubKey+"/state", bytes.NewBufferString(tc.body))
+		res := httptest.NewRecorder()
+		s.tssNewHandler().ServeHTTP(res, req)
+		c.Assert(res.Code, Equals, tc.statusCode)
+		if tc.statusCode == http.StatusOK {
+			var key storage.LocalStateInfo
+			c.Assert(json.Unmarshal(res.Body.Bytes(), &key), IsNil)
+			c.Assert(key.State, Equals, storage.KeyRetiring)
+		}
+	}
+}
+
+func (TssHttpServerTestSuite) TestGetAuditHandler(c *C) {
+	tssServer := &MockTssServer{}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	handler := s.tssNewHandler()
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var entries []audit.Entry
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &entries), IsNil)
+	c.Assert(entries, HasLen, 2)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/audit?limit=1", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &entries), IsNil)
+	c.Assert(entries, HasLen, 1)
+	c.Assert(entries[0].Seq, Equals, uint64(2))
+
+	for _, el := range []string{"whatever", "0", "-1"} {
+		res = httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/audit?limit="+el, nil))
+		c.Assert(res.Code, Equals, http.StatusBadRequest)
+	}
+
+	tssServer.failToAudit = true
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/audit", nil))
+	c.Assert(res.Code, Equals, http.StatusInternalServerError)
+}
+
+func (TssHttpServerTestSuite) TestGetTimelineHandler(c *C) {
+	s := NewTssHttpServer("127.0.0.1:8080", &MockTssServer{})
+	c.Assert(s, NotNil)
+	handler := s.tssNewHandler()
+
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/debug/timeline/whatever", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+	var timeline tss.SessionTimeline
+	c.Assert(json.Unmarshal(res.Body.Bytes(), &timeline), IsNil)
+	c.Assert(timeline.Summary.SessionID, Equals, "whatever")
+	c.Assert(timeline.Events, HasLen, 1)
+
+	res = httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/debug/timeline/unknown", nil))
+	c.Assert(res.Code, Equals, http.StatusNotFound)
+}
+
+func (TssHttpServerTestSuite) TestDraining(c *C) {
+	tssServer := &MockTssServer{draining: true}
+	s := NewTssHttpServer("127.0.0.1:8080", tssServer)
+	c.Assert(s, NotNil)
+	handler := s.tssNewHandler()
+	for _, path := range []string{"/keygen", "/keysign", "/keysign/batch", "/reshare", "/jobs/keygen", "/jobs/keysign"} {
+		res := httptest.NewRecorder()
+		handler.ServeHTTP(res, httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString("{}")))
+		c.Assert(res.Code, Equals, http.StatusServiceUnavailable, Commentf("path: %s", path))
+	}
+	// the other requests are still served
+	res := httptest.NewRecorder()
+	handler.ServeHTTP(res, httptest.NewRequest(http.MethodGet, "/keys", nil))
+	c.Assert(res.Code, Equals, http.StatusOK)
+}
+
+func (TssHttpServerTestSuite) TestEventsHandler(c *C) {
+	mock := &MockTssServer{eventBus: events.NewBus()}
+	s := NewTssHttpServer("127.0.0.1:8080", mock)
+	server := httptest.NewServer(s.tssNewHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events?type=whatever")
+	c.Assert(err, IsNil)
+	c.Assert(resp.Body.Close(), IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp, err = http.Get(server.URL + "/events?session_id=session1&type=party_formed,session_finished")
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "text/event-stream")
+	// the events of another server are not seen
+	events.NewBus().Publish(events.Event{SessionID: "session1", Type: events.PartyFormed})
+	mock.eventBus.Publish(events.Event{SessionID: "session1", Type: events.SessionCreated})
+	mock.eventBus.Publish(events.Event{SessionID: "session2", Type: events.PartyFormed})
+	mock.eventBus.Publish(events.Event{SessionID: "session1", Type: events.PartyFormed})
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		c.Assert(err, IsNil)
+		lines = append(lines, strings.TrimSpace(line))
+	}
+	c.Assert(lines[0], Matches, "id: [0-9]+")
+	c.Assert(lines[1], Equals, "event: party_formed")
+	var e events.Event
+	c.Assert(json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &e), IsNil)
+	c.Assert(e.SessionID, Equals, "session1")
+}
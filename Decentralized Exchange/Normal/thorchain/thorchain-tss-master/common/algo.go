@@ -0,0 +1,88 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// Algo is the signature scheme of the key
+type Algo string
+
+const (
+	// ECDSA over secp256k1, it is the default when the algorithm is not given
+	ECDSA Algo = "ecdsa"
+	// EdDSA over ed25519
+	EdDSA Algo = "eddsa"
+)
+
+// GetAlgo return the algorithm of the given name, the empty name is ECDSA
+func GetAlgo(name string) (Algo, error) {
+	switch Algo(name) {
+	case "", ECDSA:
+		return ECDSA, nil
+	case EdDSA:
+		return EdDSA, nil
+	default:
+		return "", fmt.Errorf("unknown algorithm(%s)", name)
+	}
+}
+
+// ErrCurveBusy is returned when a session asks for the curve while the sessions of the other algorithm hold it
+var ErrCurveBusy = errors.New("the curve is held by the sessions of the other algorithm")
+
+// tss-lib keeps the curve in a package level variable, so the sessions of different algorithms can't run at the same
+// time, the sessions of the same algorithm share the curve and run in parallel as they used to. A session of the other
+// algorithm is refused rather than queued, the curve is taken before the party is formed, a node queuing the session
+// would never join its party, and the parties of both algorithms across the nodes would wait on each other till they
+// time out
+var curveGuard = &struct {
+	lock     *sync.Mutex
+	algo     Algo
+	sessions int
+}{
+	lock: &sync.Mutex{},
+}
+
+// curveKey is the context key of the algorithm whose curve is held by the session
+type curveKey struct{}
+
+// AcquireCurve set the tss-lib curve for the given algorithm, ErrCurveBusy is returned while the sessions of the other
+// algorithm hold it, the returned function has to be called once the session is done. The returned context tells the
+// steps of the session that the curve is held, acquiring it again with that context returns straight away
+func AcquireCurve(ctx context.Context, algo Algo) (context.Context, func(), error) {
+	if held, ok := ctx.Value(curveKey{}).(Algo); ok {
+		if held != algo {
+			return ctx, nil, fmt.Errorf("the curve of %s is held, not %s", held, algo)
+		}
+		return ctx, func() {}, nil
+	}
+	curveGuard.lock.Lock()
+	defer curveGuard.lock.Unlock()
+	if curveGuard.sessions != 0 && curveGuard.algo != algo {
+		return ctx, nil, fmt.Errorf("%w, %s is running", ErrCurveBusy, curveGuard.algo)
+	}
+	if curveGuard.sessions == 0 && algo == EdDSA {
+		btss.SetCurve(edwards.Edwards())
+	}
+	curveGuard.algo = algo
+	curveGuard.sessions++
+	once := &sync.Once{}
+	release := func() { once.Do(releaseCurve) }
+	return context.WithValue(ctx, curveKey{}, algo), release, nil
+}
+
+func releaseCurve() {
+	curveGuard.lock.Lock()
+	defer curveGuard.lock.Unlock()
+	curveGuard.sessions--
+	// secp256k1 is the default curve of tss-lib, we always put it back
+	if curveGuard.sessions == 0 && curveGuard.algo == EdDSA {
+		btss.SetCurve(btcec.S256())
+	}
+}
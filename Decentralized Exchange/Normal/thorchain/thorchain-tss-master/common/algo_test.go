@@ -0,0 +1,78 @@
+package common
+
+import (
+	"context"
+	"errors"
+
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	. "gopkg.in/check.v1"
+)
+
+type algoSuite struct{}
+
+var _ = Suite(&algoSuite{})
+
+func (s *algoSuite) TestGetAlgo(c *C) {
+	algo, err := GetAlgo("")
+	c.Assert(err, IsNil)
+	c.Assert(algo, Equals, ECDSA)
+	algo, err = GetAlgo("eddsa")
+	c.Assert(err, IsNil)
+	c.Assert(algo, Equals, EdDSA)
+	_, err = GetAlgo("schnorr")
+	c.Assert(err, NotNil)
+}
+
+func (s *algoSuite) TestAcquireCurve(c *C) {
+	_, release1, err := AcquireCurve(context.Background(), ECDSA)
+	c.Assert(err, IsNil)
+	_, release2, err := AcquireCurve(context.Background(), ECDSA)
+	c.Assert(err, IsNil)
+	c.Assert(btss.EC(), Equals, btcec.S256())
+
+	// the EdDSA session is refused while the ECDSA sessions are running
+	_, _, err = AcquireCurve(context.Background(), EdDSA)
+	c.Assert(errors.Is(err, ErrCurveBusy), Equals, true)
+	release1()
+	// release twice doesn't count
+	release1()
+	_, _, err = AcquireCurve(context.Background(), EdDSA)
+	c.Assert(errors.Is(err, ErrCurveBusy), Equals, true)
+	release2()
+
+	_, release3, err := AcquireCurve(context.Background(), EdDSA)
+	c.Assert(err, IsNil)
+	_, ok := btss.EC().(*edwards.TwistedEdwardsCurve)
+	c.Assert(ok, Equals, true)
+	_, release4, err := AcquireCurve(context.Background(), EdDSA)
+	c.Assert(err, IsNil)
+	_, _, err = AcquireCurve(context.Background(), ECDSA)
+	c.Assert(errors.Is(err, ErrCurveBusy), Equals, true)
+	release3()
+	_, ok = btss.EC().(*edwards.TwistedEdwardsCurve)
+	c.Assert(ok, Equals, true)
+	release4()
+	c.Assert(btss.EC(), Equals, btcec.S256())
+	curveGuard.lock.Lock()
+	c.Assert(curveGuard.sessions, Equals, 0)
+	curveGuard.lock.Unlock()
+}
+
+func (s *algoSuite) TestAcquireCurveHeld(c *C) {
+	ctx, release, err := AcquireCurve(context.Background(), ECDSA)
+	c.Assert(err, IsNil)
+	sameCtx, releaseAgain, err := AcquireCurve(ctx, ECDSA)
+	c.Assert(err, IsNil)
+	c.Assert(sameCtx, Equals, ctx)
+	releaseAgain()
+	curveGuard.lock.Lock()
+	c.Assert(curveGuard.sessions, Equals, 1)
+	curveGuard.lock.Unlock()
+
+	_, _, err = AcquireCurve(ctx, EdDSA)
+	c.Assert(err, NotNil)
+	release()
+	c.Assert(btss.EC(), Equals, btcec.S256())
+}
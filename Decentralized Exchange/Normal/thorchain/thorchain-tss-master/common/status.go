@@ -6,4 +6,23 @@ const (
 	NA Status = iota
 	Success
 	Fail
+	Cancelled
+	// Rejected is the keysign the local policy refuses, or the one some signers refuse to join
+	Rejected
 )
+
+// String return the name of the status
+func (s Status) String() string {
+	switch s {
+	case Success:
+		return "success"
+	case Fail:
+		return "fail"
+	case Cancelled:
+		return "cancelled"
+	case Rejected:
+		return "rejected"
+	default:
+		return "na"
+	}
+}
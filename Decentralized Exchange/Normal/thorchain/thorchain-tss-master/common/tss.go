@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 
 	btss "github.com/binance-chain/tss-lib/tss"
@@ -11,11 +12,12 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	tcrypto "github.com/tendermint/tendermint/crypto"
-	"github.com/tendermint/tendermint/crypto/secp256k1"
 
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 )
 
@@ -23,8 +25,34 @@ import (
 type PartyInfo struct {
 	Party      btss.Party
 	PartyIDMap map[string]*btss.PartyID
+	// NewCommitteeParty is only set in key resharing when the local node is in both the old and the new
+	// committee, Party is the party of the old committee in that case
+	NewCommitteeParty btss.Party
 }
 
+// localParties return the local parties the given message should be applied to
+func (p *PartyInfo) localParties(routing *btss.MessageRouting) []btss.Party {
+	if p.NewCommitteeParty == nil {
+		if routing.From.Id == p.Party.PartyID().Id {
+			return nil
+		}
+		return []btss.Party{p.Party}
+	}
+	var parties []btss.Party
+	for _, party := range []btss.Party{p.Party, p.NewCommitteeParty} {
+		for _, el := range routing.To {
+			if el.Id == party.PartyID().Id && el.Id != routing.From.Id {
+				parties = append(parties, party)
+				break
+			}
+		}
+	}
+	return parties
+}
+
+// in key resharing, a local party sends at most a handful of messages to the other local party
+const loopbackBufferSize = 16
+
 type TssCommon struct {
 	conf                TssConfig
 	logger              zerolog.Logger
@@ -36,12 +64,16 @@ type TssCommon struct {
 	localPeerID         string
 	broadcastChannel    chan *messages.BroadcastMsgChan
 	TssMsg              chan *p2p.Message
-	P2PPeers            []peer.ID // most of tss message are broadcast, we store the peers ID to avoid iterating
+	loopbackMsg         chan *messages.WrappedMessage // messages between the local parties of a key resharing
+	P2PPeers            []peer.ID                     // most of tss message are broadcast, we store the peers ID to avoid iterating
 	msgID               string
 	privateKey          tcrypto.PrivKey
 	taskDone            chan struct{}
 	blameMgr            *blame.Manager
 	finishedPeers       map[string]bool
+	producedLock        *sync.Mutex
+	producedRounds      map[string]bool
+	eventBus            *events.Bus
 }
 
 func NewTssCommon(peerID string, broadcastChannel chan *messages.BroadcastMsgChan, conf TssConfig, msgID string, privKey tcrypto.PrivKey) *TssCommon {
@@ -55,14 +87,73 @@ func NewTssCommon(peerID string, broadcastChannel chan *messages.BroadcastMsgCha
 		unConfirmedMessages: make(map[string]*LocalCacheItem),
 		broadcastChannel:    broadcastChannel,
 		TssMsg:              make(chan *p2p.Message),
+		loopbackMsg:         make(chan *messages.WrappedMessage, loopbackBufferSize),
 		P2PPeers:            nil,
 		msgID:               msgID,
 		localPeerID:         peerID,
 		privateKey:          privKey,
 		taskDone:            make(chan struct{}),
-		blameMgr:            blame.NewBlameManager(),
+		blameMgr:            blame.NewBlameManager(conf.Bech32Prefix),
 		finishedPeers:       make(map[string]bool),
+		producedLock:        &sync.Mutex{},
+		producedRounds:      make(map[string]bool),
+	}
+}
+
+// SetEventBus set the bus the events of this session are published to, it has to be set before the session starts,
+// no event is published without it
+func (t *TssCommon) SetEventBus(eventBus *events.Bus) {
+	t.eventBus = eventBus
+}
+
+// publish the event of the given type of this session
+func (t *TssCommon) publish(eventType, round, peer string) {
+	t.eventBus.Publish(events.Event{
+		SessionID: t.msgID,
+		Type:      eventType,
+		Round:     round,
+		Peer:      peer,
+	})
+}
+
+// publishRoundProduced publish the local party produces the given round, only the first message of the round is
+// published
+func (t *TssCommon) publishRoundProduced(round string) {
+	t.producedLock.Lock()
+	produced := t.producedRounds[round]
+	t.producedRounds[round] = true
+	t.producedLock.Unlock()
+	if !produced {
+		t.publish(events.RoundProduced, round, "")
+	}
+}
+
+// getPeerOfParty return the p2p ID of the given party, empty if the party is unknown
+func (t *TssCommon) getPeerOfParty(partyID string) string {
+	peerID, ok := t.PartyIDtoP2PID[partyID]
+	if !ok {
+		return ""
 	}
+	return peerID.String()
+}
+
+// splitCacheKey return the party ID and the round of the given key of the message cache
+func splitCacheKey(key string) (string, string) {
+	idx := strings.LastIndex(key, "-")
+	if idx < 0 {
+		return "", key
+	}
+	return key[:idx], key[idx+1:]
+}
+
+// remotePeersCount return how many other nodes are in the party, a node may have more than one party ID when it is
+// in both committees of the resharing, so we count the distinct peers
+func (t *TssCommon) remotePeersCount() int {
+	peers := make(map[peer.ID]bool)
+	for _, el := range t.PartyIDtoP2PID {
+		peers[el] = true
+	}
+	return len(peers) - 1
 }
 
 func (t *TssCommon) renderToP2P(broadcastMsg *messages.BroadcastMsgChan) {
@@ -120,7 +211,7 @@ func (t *TssCommon) processInvalidMsgBlame(wireMsg *messages.WireMessage, err *b
 		storedMsg := t.blameMgr.GetRoundMgr().Get(key)
 		invalidMsgs = append(invalidMsgs, storedMsg)
 	}
-	pubkeys, errBlame := conversion.AccPubKeysFromPartyIDs(culpritsID, t.partyInfo.PartyIDMap)
+	pubkeys, errBlame := conversion.AccPubKeysFromPartyIDs(culpritsID, t.partyInfo.PartyIDMap, t.conf.Bech32Prefix)
 	if errBlame != nil {
 		t.logger.Error().Err(err.Cause()).Msgf("error in get the blame nodes")
 		t.blameMgr.GetBlame().SetBlame(blame.HashCheckFail, nil, unicast)
@@ -170,8 +261,10 @@ func (t *TssCommon) updateLocal(wireMsg *messages.WireMessage) error {
 	if !wireMsg.Routing.IsBroadcast {
 		t.blameMgr.SetLastUnicastPeer(dataOwnerPeerID, wireMsg.RoundInfo)
 	}
-	if _, err := partyInfo.Party.UpdateFromBytes(wireMsg.Message, partyID, wireMsg.Routing.IsBroadcast); nil != err {
-		return t.processInvalidMsgBlame(wireMsg, err)
+	for _, party := range partyInfo.localParties(wireMsg.Routing) {
+		if _, err := party.UpdateFromBytes(wireMsg.Message, partyID, wireMsg.Routing.IsBroadcast); nil != err {
+			return t.processInvalidMsgBlame(wireMsg, err)
+		}
 	}
 	return nil
 }
@@ -201,13 +294,13 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 	}
 
 	switch wrappedMsg.MessageType {
-	case messages.TSSKeyGenMsg, messages.TSSKeySignMsg:
+	case messages.TSSKeyGenMsg, messages.TSSKeySignMsg, messages.TSSReshareMsg, messages.TSSEdDSAKeyGenMsg, messages.TSSEdDSAKeySignMsg:
 		var wireMsg messages.WireMessage
 		if err := json.Unmarshal(wrappedMsg.Payload, &wireMsg); nil != err {
 			return fmt.Errorf("fail to unmarshal wire message: %w", err)
 		}
 		return t.processTSSMsg(&wireMsg, wrappedMsg.MessageType, false)
-	case messages.TSSKeyGenVerMsg, messages.TSSKeySignVerMsg:
+	case messages.TSSKeyGenVerMsg, messages.TSSKeySignVerMsg, messages.TSSEdDSAKeyGenVerMsg, messages.TSSEdDSAKeySignVerMsg, messages.TSSReshareVerMsg:
 		var bMsg messages.BroadcastConfirmMessage
 		if err := json.Unmarshal(wrappedMsg.Payload, &bMsg); nil != err {
 			return errors.New("fail to unmarshal broadcast confirm message")
@@ -230,7 +323,7 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 				return fmt.Errorf("duplicated notification from peer %s ignored", peerID)
 			}
 			t.finishedPeers[peerID] = true
-			if len(t.finishedPeers) == len(t.partyInfo.PartyIDMap)-1 {
+			if len(t.finishedPeers) == t.remotePeersCount() {
 				t.logger.Info().Msg("we get the confirm of the nodes that generate the signature")
 				close(t.taskDone)
 			}
@@ -242,6 +335,7 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 			return fmt.Errorf("fail to unmarshal wire message: %w", err)
 		}
 		if wireMsg.Msg == nil {
+			monitor.ObserveShareRequest(monitor.DirectionIn, monitor.ShareRequest)
 			decodedPeerID, err := peer.Decode(peerID)
 			if err != nil {
 				t.logger.Error().Err(err).Msg("error in decode the peer")
@@ -249,12 +343,14 @@ func (t *TssCommon) ProcessOneMessage(wrappedMsg *messages.WrappedMessage, peerI
 			}
 			return t.processRequestMsgFromPeer([]peer.ID{decodedPeerID}, &wireMsg, false)
 		}
+		monitor.ObserveShareRequest(monitor.DirectionIn, monitor.ShareResponse)
 		exist := t.blameMgr.GetShareMgr().QueryAndDelete(wireMsg.ReqHash)
 		if !exist {
 			t.logger.Debug().Msg("this request does not exit, maybe already processed")
 			return nil
 		}
 		t.logger.Info().Msg("we got the missing share from the peer")
+		t.publish(events.ShareReceived, wireMsg.Msg.RoundInfo, peerID)
 		return t.processTSSMsg(wireMsg.Msg, wireMsg.RequestType, true)
 	}
 
@@ -320,6 +416,8 @@ func (t *TssCommon) ProcessOutCh(msg btss.Message, msgType messages.THORChainTSS
 		t.logger.Error().Err(err).Msg("fail to generate the share's signature")
 		return err
 	}
+	t.blameMgr.GetTimeline().Add(blame.EventLocalMessage, msg.Type(), "")
+	t.publishRoundProduced(msg.Type())
 
 	wireMsg := messages.WireMessage{
 		Routing:   r,
@@ -337,15 +435,29 @@ func (t *TssCommon) ProcessOutCh(msg btss.Message, msgType messages.THORChainTSS
 		Payload:     wireMsgBytes,
 	}
 	peerIDs := make([]peer.ID, 0)
+	loopback := false
 	if len(r.To) == 0 {
 		peerIDs = t.P2PPeers
 	} else {
+		// in key resharing, a node can be in both committees, so it has two parties behind the same peer
+		added := make(map[peer.ID]bool)
 		for _, each := range r.To {
+			if each.Id == r.From.Id {
+				continue
+			}
 			peerID, ok := t.PartyIDtoP2PID[each.Id]
 			if !ok {
 				t.logger.Error().Msg("error in find the P2P ID")
 				continue
 			}
+			if peerID.String() == t.localPeerID {
+				loopback = true
+				continue
+			}
+			if added[peerID] {
+				continue
+			}
+			added[peerID] = true
 			peerIDs = append(peerIDs, peerID)
 		}
 	}
@@ -353,6 +465,9 @@ func (t *TssCommon) ProcessOutCh(msg btss.Message, msgType messages.THORChainTSS
 		WrappedMessage: wrappedMsg,
 		PeersID:        peerIDs,
 	})
+	if loopback {
+		t.loopbackMsg <- &wrappedMsg
+	}
 
 	return nil
 }
@@ -383,6 +498,9 @@ func (t *TssCommon) applyShare(localCacheItem *LocalCacheItem, threshold int, ke
 		return blame.ErrHashCheck
 	}
 
+	confirmedPeer := t.getPeerOfParty(localCacheItem.Msg.Routing.From.Id)
+	t.blameMgr.GetTimeline().Add(blame.EventPeerConfirmed, localCacheItem.Msg.RoundInfo, confirmedPeer)
+	t.publish(events.RoundConfirmed, localCacheItem.Msg.RoundInfo, confirmedPeer)
 	t.blameMgr.GetRoundMgr().Set(key, localCacheItem.Msg)
 	if err := t.updateLocal(localCacheItem.Msg); nil != err {
 		return fmt.Errorf("fail to update the message to local party: %w", err)
@@ -418,6 +536,9 @@ func (t *TssCommon) requestShareFromPeer(localCacheItem *LocalCacheItem, thresho
 		Msg:         nil,
 	}
 	t.blameMgr.GetShareMgr().Set(targetHash)
+	partyID, round := splitCacheKey(key)
+	t.blameMgr.GetTimeline().Add(blame.EventShareRequested, round, t.getPeerOfParty(partyID))
+	t.publish(events.ShareRequested, round, t.getPeerOfParty(partyID))
 	switch msgType {
 	case messages.TSSKeyGenVerMsg:
 		msg.RequestType = messages.TSSKeyGenMsg
@@ -425,7 +546,16 @@ func (t *TssCommon) requestShareFromPeer(localCacheItem *LocalCacheItem, thresho
 	case messages.TSSKeySignVerMsg:
 		msg.RequestType = messages.TSSKeySignMsg
 		return t.processRequestMsgFromPeer(peersIDs, msg, true)
-	case messages.TSSKeySignMsg, messages.TSSKeyGenMsg:
+	case messages.TSSEdDSAKeyGenVerMsg:
+		msg.RequestType = messages.TSSEdDSAKeyGenMsg
+		return t.processRequestMsgFromPeer(peersIDs, msg, true)
+	case messages.TSSEdDSAKeySignVerMsg:
+		msg.RequestType = messages.TSSEdDSAKeySignMsg
+		return t.processRequestMsgFromPeer(peersIDs, msg, true)
+	case messages.TSSReshareVerMsg:
+		msg.RequestType = messages.TSSReshareMsg
+		return t.processRequestMsgFromPeer(peersIDs, msg, true)
+	case messages.TSSKeySignMsg, messages.TSSKeyGenMsg, messages.TSSEdDSAKeyGenMsg, messages.TSSEdDSAKeySignMsg, messages.TSSReshareMsg:
 		msg.RequestType = msgType
 		return t.processRequestMsgFromPeer(peersIDs, msg, true)
 	default:
@@ -455,7 +585,18 @@ func (t *TssCommon) processVerMsg(broadcastConfirmMsg *messages.BroadcastConfirm
 	localCacheItem.UpdateConfirmList(broadcastConfirmMsg.P2PID, broadcastConfirmMsg.Hash)
 	t.logger.Debug().Msgf("total confirmed parties:%+v", localCacheItem.ConfirmedList)
 
-	threshold, err := GetThreshold(len(partyInfo.PartyIDMap))
+	// the peers confirming the resharing message depend on the committee it is sent to, which we only know once we
+	// have the message, it is sent to us directly, so we wait for it rather than requesting it
+	if msgType == messages.TSSReshareVerMsg && localCacheItem.Msg == nil {
+		return nil
+	}
+	var threshold int
+	var err error
+	if localCacheItem.Msg == nil {
+		threshold, err = GetThreshold(len(partyInfo.PartyIDMap))
+	} else {
+		threshold, err = t.getConfirmThreshold(localCacheItem.Msg, msgType)
+	}
 	if err != nil {
 		return err
 	}
@@ -466,6 +607,55 @@ func (t *TssCommon) processVerMsg(broadcastConfirmMsg *messages.BroadcastConfirm
 	return t.applyShare(localCacheItem, threshold, key, msgType)
 }
 
+// getConfirmPeers return the other peers that receive the given broadcast message and confirm its hash with us,
+// they are all the peers except the data owner, but the resharing message is only sent to the parties of one
+// committee
+func (t *TssCommon) getConfirmPeers(wireMsg *messages.WireMessage, msgType messages.THORChainTSSMessageType) ([]peer.ID, error) {
+	dataOwnerPeerID, ok := t.PartyIDtoP2PID[wireMsg.Routing.From.Id]
+	if !ok {
+		return nil, errors.New("error in find the data owner peerID")
+	}
+	if msgType != messages.TSSReshareMsg && msgType != messages.TSSReshareVerMsg {
+		var peerIDs []peer.ID
+		for _, el := range t.P2PPeers {
+			if el == dataOwnerPeerID {
+				continue
+			}
+			peerIDs = append(peerIDs, el)
+		}
+		return peerIDs, nil
+	}
+	// a node in both committees has two parties behind the same peer
+	added := make(map[peer.ID]bool)
+	var peerIDs []peer.ID
+	for _, el := range wireMsg.Routing.To {
+		peerID, ok := t.PartyIDtoP2PID[el.Id]
+		if !ok {
+			return nil, fmt.Errorf("fail to find the peer of party %s", el.Id)
+		}
+		if peerID == dataOwnerPeerID || peerID.String() == t.localPeerID || added[peerID] {
+			continue
+		}
+		added[peerID] = true
+		peerIDs = append(peerIDs, peerID)
+	}
+	return peerIDs, nil
+}
+
+// getConfirmThreshold return how many peers have to confirm the hash of the given broadcast message before we apply
+// it, for the resharing message it is the threshold of the peers the message is sent to
+func (t *TssCommon) getConfirmThreshold(wireMsg *messages.WireMessage, msgType messages.THORChainTSSMessageType) (int, error) {
+	if msgType != messages.TSSReshareMsg && msgType != messages.TSSReshareVerMsg {
+		return GetThreshold(len(t.getPartyInfo().PartyIDMap))
+	}
+	peerIDs, err := t.getConfirmPeers(wireMsg, msgType)
+	if err != nil {
+		return 0, err
+	}
+	// the confirming peers, the local node and the data owner
+	return GetThreshold(len(peerIDs) + 2)
+}
+
 func (t *TssCommon) broadcastHashToPeers(key, msgHash string, peerIDs []peer.ID, msgType messages.THORChainTSSMessageType) error {
 	if len(peerIDs) == 0 {
 		t.logger.Error().Msg("fail to get any peer ID")
@@ -498,17 +688,13 @@ func (t *TssCommon) broadcastHashToPeers(key, msgHash string, peerIDs []peer.ID,
 }
 
 func (t *TssCommon) receiverBroadcastHashToPeers(wireMsg *messages.WireMessage, msgType messages.THORChainTSSMessageType) error {
-	var peerIDs []peer.ID
-	dataOwnerPartyID := wireMsg.Routing.From.Id
-	dataOwnerPeerID, ok := t.PartyIDtoP2PID[dataOwnerPartyID]
-	if !ok {
-		return errors.New("error in find the data owner peerID")
+	peerIDs, err := t.getConfirmPeers(wireMsg, msgType)
+	if err != nil {
+		return err
 	}
-	for _, el := range t.P2PPeers {
-		if el == dataOwnerPeerID {
-			continue
-		}
-		peerIDs = append(peerIDs, el)
+	// the resharing message sent to the local node only has no one to confirm with
+	if len(peerIDs) == 0 && msgType == messages.TSSReshareMsg {
+		return nil
 	}
 	msgVerType := getBroadcastMessageType(msgType)
 	key := wireMsg.GetCacheKey()
@@ -539,14 +725,15 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		t.logger.Error().Msg("error in find the data owner")
 		return errors.New("error in find the data owner")
 	}
-	keyBytes := dataOwner.GetKey()
-	var pk secp256k1.PubKeySecp256k1
-	copy(pk[:], keyBytes)
+	pk := conversion.PartyKeyToSecp256PubKey(dataOwner.KeyInt())
 	ok = verifySignature(pk, wireMsg.Message, wireMsg.Sig, t.msgID)
 	if !ok {
 		t.logger.Error().Msg("fail to verify the signature")
 		return errors.New("signature verify failed")
 	}
+	if !forward {
+		t.blameMgr.GetTimeline().Add(blame.EventPeerMessage, wireMsg.RoundInfo, t.getPeerOfParty(dataOwner.Id))
+	}
 
 	// for the unicast message, we only update it local party
 	if !wireMsg.Routing.IsBroadcast {
@@ -554,6 +741,12 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		return t.updateLocal(wireMsg)
 	}
 
+	// in key resharing, the message from the other local party of a node in both committees needs no confirmation
+	if msgType == messages.TSSReshareMsg && t.getPeerOfParty(dataOwner.Id) == t.localPeerID {
+		t.blameMgr.GetRoundMgr().Set(wireMsg.GetCacheKey(), wireMsg)
+		return t.updateLocal(wireMsg)
+	}
+
 	// if not received the broadcast message , we save a copy locally , and then tell all others what we got
 	if !forward {
 		err := t.receiverBroadcastHashToPeers(wireMsg, msgType)
@@ -562,7 +755,6 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 		}
 	}
 
-	partyInfo := t.getPartyInfo()
 	key := wireMsg.GetCacheKey()
 	msgHash, err := conversion.BytesToHashString(wireMsg.Message)
 	if err != nil {
@@ -584,7 +776,7 @@ func (t *TssCommon) processTSSMsg(wireMsg *messages.WireMessage, msgType message
 	}
 	localCacheItem.UpdateConfirmList(t.localPeerID, msgHash)
 
-	threshold, err := GetThreshold(len(partyInfo.PartyIDMap))
+	threshold, err := t.getConfirmThreshold(wireMsg, msgType)
 	if err != nil {
 		return err
 	}
@@ -597,6 +789,12 @@ func getBroadcastMessageType(msgType messages.THORChainTSSMessageType) messages.
 		return messages.TSSKeyGenVerMsg
 	case messages.TSSKeySignMsg:
 		return messages.TSSKeySignVerMsg
+	case messages.TSSEdDSAKeyGenMsg:
+		return messages.TSSEdDSAKeyGenVerMsg
+	case messages.TSSEdDSAKeySignMsg:
+		return messages.TSSEdDSAKeySignVerMsg
+	case messages.TSSReshareMsg:
+		return messages.TSSReshareVerMsg
 	default:
 		return messages.Unknown // this should not happen
 	}
@@ -656,7 +854,11 @@ func (t *TssCommon) ProcessInboundMessages(finishChan chan struct{}, wg *sync.Wa
 			if err != nil {
 				t.logger.Error().Err(err).Msg("fail to process the received message")
 			}
-
+		case wrappedMsg := <-t.loopbackMsg:
+			err := t.ProcessOneMessage(wrappedMsg, t.localPeerID)
+			if err != nil {
+				t.logger.Error().Err(err).Msg("fail to process the message from the local party")
+			}
 		}
 	}
 }
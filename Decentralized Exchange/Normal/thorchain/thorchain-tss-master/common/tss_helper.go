@@ -22,6 +22,7 @@ import (
 	"github.com/rs/zerolog/log"
 	tcrypto "github.com/tendermint/tendermint/crypto"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 )
 
 func Contains(s []*btss.PartyID, e *btss.PartyID) bool {
@@ -44,6 +45,18 @@ func GetThreshold(value int) (int, error) {
 	return threshold, nil
 }
 
+// ValidateThreshold check the caller specified keygen threshold against the party size, as the keysign needs
+// threshold+1 signers, the threshold should be at least one and less than the party size
+func ValidateThreshold(threshold, partySize int) error {
+	if threshold < 1 {
+		return fmt.Errorf("invalid threshold(%d), it should be at least 1", threshold)
+	}
+	if threshold >= partySize {
+		return fmt.Errorf("invalid threshold(%d), it should be less than the party size(%d)", threshold, partySize)
+	}
+	return nil
+}
+
 func MsgToHashInt(msg []byte) (*big.Int, error) {
 	return hashToInt(msg, btcec.S256()), nil
 }
@@ -88,6 +101,7 @@ func InitLog(level string, pretty bool, serviceValue string) {
 	log.Logger = log.Output(out).With().Str("service", serviceValue).Logger()
 }
 
+// generateSignature sign the message along with the id of the session, the signature is only valid in that session
 func generateSignature(msg []byte, msgID string, privKey tcrypto.PrivKey) ([]byte, error) {
 	var dataForSigning bytes.Buffer
 	dataForSigning.Write(msg)
@@ -170,6 +184,11 @@ func (t *TssCommon) processRequestMsgFromPeer(peersID []peer.ID, msg *messages.T
 	if err != nil {
 		return fmt.Errorf("fail to marshal the request body %w", err)
 	}
+	if requester {
+		monitor.ObserveShareRequest(monitor.DirectionOut, monitor.ShareRequest)
+	} else {
+		monitor.ObserveShareRequest(monitor.DirectionOut, monitor.ShareResponse)
+	}
 	wrappedMsg := messages.WrappedMessage{
 		MessageType: messages.TSSControlMsg,
 		MsgID:       t.msgID,
@@ -69,6 +69,15 @@ func (t *TssTestSuite) TestGetThreshold(c *C) {
 	c.Assert(output, Equals, 65)
 }
 
+func (t *TssTestSuite) TestValidateThreshold(c *C) {
+	c.Assert(ValidateThreshold(0, 5), NotNil)
+	c.Assert(ValidateThreshold(-1, 5), NotNil)
+	c.Assert(ValidateThreshold(5, 5), NotNil)
+	c.Assert(ValidateThreshold(1, 5), IsNil)
+	c.Assert(ValidateThreshold(2, 5), IsNil)
+	c.Assert(ValidateThreshold(4, 5), IsNil)
+}
+
 func (t *TssTestSuite) TestMsgToHashInt(c *C) {
 	input := []byte("whatever")
 	result, err := MsgToHashInt(input)
@@ -383,6 +392,77 @@ func (t *TssTestSuite) TestProcessVerMessage(c *C) {
 	t.testProcessTaskDone(c, tssCommonStruct)
 }
 
+func fabricateReshareMsg(c *C, privKey tcrypto.PrivKey, partyID *btss.PartyID, to []*btss.PartyID, roundInfo, msg, msgID string) *messages.WrappedMessage {
+	wrappedMsg := fabricateTssMsg(c, privKey, partyID, roundInfo, msg, msgID, messages.TSSReshareMsg)
+	var wireMsg messages.WireMessage
+	c.Assert(json.Unmarshal(wrappedMsg.Payload, &wireMsg), IsNil)
+	wireMsg.Routing.To = to
+	payload, err := json.Marshal(wireMsg)
+	c.Assert(err, IsNil)
+	wrappedMsg.Payload = payload
+	return wrappedMsg
+}
+
+// TestProcessReshareVerMsg make sure the resharing broadcast message is confirmed among the peers it is sent to
+func (t *TssTestSuite) TestProcessReshareVerMsg(c *C) {
+	tssCommonStruct, _, partiesID := setupProcessVerMsgEnv(c, t.privKey, testBlamePubKeys, 4)
+	tssCommonStruct.msgID = "123"
+	broadcastChannel := make(chan *messages.BroadcastMsgChan, 10)
+	tssCommonStruct.broadcastChannel = broadcastChannel
+	sender := findSender(partiesID)
+	// the sender is only in the old committee, the message goes to the 3 parties of the new committee
+	var newCommittee []*btss.PartyID
+	for _, el := range tssCommonStruct.getPartyInfo().PartyIDMap {
+		if el.Id != sender.Id {
+			newCommittee = append(newCommittee, el)
+		}
+	}
+	c.Assert(newCommittee, HasLen, 3)
+	tssCommonStruct.SetLocalPeerID(tssCommonStruct.PartyIDtoP2PID[newCommittee[0].Id].String())
+	confirmPeer := tssCommonStruct.PartyIDtoP2PID[newCommittee[1].Id].String()
+	senderPeer := tssCommonStruct.PartyIDtoP2PID[sender.Id].String()
+
+	// the message is held till the other peer of the new committee confirms the hash
+	testMsg := "testReshareVerMsg"
+	roundInfo := "round testReshareVerMsg"
+	msgHash, err := conversion.BytesToHashString([]byte(testMsg))
+	c.Assert(err, IsNil)
+	msgKey := fmt.Sprintf("%s-%s", sender.Id, roundInfo)
+	err = tssCommonStruct.ProcessOneMessage(fabricateReshareMsg(c, t.privKey, sender, newCommittee, roundInfo, testMsg, "123"), senderPeer)
+	c.Assert(err, IsNil)
+	localItem := tssCommonStruct.TryGetLocalCacheItem(msgKey)
+	c.Assert(localItem, NotNil)
+	c.Assert(localItem.ConfirmedList, HasLen, 1)
+	verMsg := <-broadcastChannel
+	c.Assert(verMsg.WrappedMessage.MessageType, Equals, messages.TSSReshareVerMsg)
+	c.Assert(verMsg.PeersID, HasLen, 2)
+	wrappedVerMsg := fabricateVerMsg(c, msgHash, msgKey)
+	wrappedVerMsg.MessageType = messages.TSSReshareVerMsg
+	err = tssCommonStruct.ProcessOneMessage(wrappedVerMsg, confirmPeer)
+	c.Assert(err, ErrorMatches, "fail to update the message to local party.*")
+
+	// the data owner can't confirm its own message
+	testMsg = "testReshareVerMsgFromOwner"
+	roundInfo = "round testReshareVerMsgFromOwner"
+	msgHash, err = conversion.BytesToHashString([]byte(testMsg))
+	c.Assert(err, IsNil)
+	msgKey = fmt.Sprintf("%s-%s", sender.Id, roundInfo)
+	err = tssCommonStruct.ProcessOneMessage(fabricateReshareMsg(c, t.privKey, sender, newCommittee, roundInfo, testMsg, "123"), senderPeer)
+	c.Assert(err, IsNil)
+	wrappedVerMsg = fabricateVerMsg(c, msgHash, msgKey)
+	wrappedVerMsg.MessageType = messages.TSSReshareVerMsg
+	err = tssCommonStruct.ProcessOneMessage(wrappedVerMsg, senderPeer)
+	c.Assert(err, Equals, blame.ErrHashCheck)
+	c.Assert(tssCommonStruct.blameMgr.GetBlame().FailReason, Equals, blame.HashCheckFail)
+
+	// the confirmation that comes before the message waits for it
+	msgKey = fmt.Sprintf("%s-%s", sender.Id, "round testReshareVerMsgFirst")
+	wrappedVerMsg = fabricateVerMsg(c, msgHash, msgKey)
+	wrappedVerMsg.MessageType = messages.TSSReshareVerMsg
+	c.Assert(tssCommonStruct.ProcessOneMessage(wrappedVerMsg, confirmPeer), IsNil)
+	c.Assert(tssCommonStruct.TryGetLocalCacheItem(msgKey).Msg, IsNil)
+}
+
 func (t *TssTestSuite) TestTssCommon(c *C) {
 	pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3")
 	c.Assert(err, IsNil)
@@ -13,6 +13,21 @@ type TssConfig struct {
 	KeySignTimeout time.Duration
 	// Pre-parameter define the pre-parameter generations timeout
 	PreParamTimeout time.Duration
+	// PreParamPoolSize defines how many pre-parameters we keep ready for keygen, the pool is not refilled in
+	// background if it is zero, the keygen fails once the pre-parameters given to the node are used up
+	PreParamPoolSize int
+	// PreParamConcurrency defines how many CPU cores can be used to generate the pre-parameters
+	PreParamConcurrency int
+	// JobRetention defines how long do we keep the finished asynchronous jobs
+	JobRetention time.Duration
+	// DrainTimeout defines how long do we wait for the sessions in flight to finish before the server stops
+	DrainTimeout time.Duration
+	// Bech32Prefix defines the account prefix of the bech32 pub keys the server returns, thor is used if it is
+	// empty, the nodes of a pool should use the same prefix as the party order follows the encoded keys
+	Bech32Prefix string
+	// KeySignPolicyFile is the json file of the rules the keysign has to follow before the local node joins it, the
+	// node joins any keysign if it is empty
+	KeySignPolicyFile string
 }
 
 type TssStatus struct {
@@ -28,4 +43,6 @@ type TssStatus struct {
 	// FailedKeySign indicates how many times we run keysign unsuccessfully(the invalid http request is not counted as
 	// the failure of keysign)
 	FailedKeySign uint64 `json:"failed_keysign"`
+	// PreParamPoolDepth indicates how many pre-parameters are ready for keygen
+	PreParamPoolDepth int `json:"pre_param_pool_depth"`
 }
@@ -0,0 +1,120 @@
+package conversion
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	tcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/bech32"
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultCosmosPrefix is the bech32 prefix of the Cosmos address when the caller doesn't choose one
+const DefaultCosmosPrefix = "cosmos"
+
+// PubKeyInfo is the pool key in the encodings the chains use, the BTC and ETH addresses are only available for the
+// secp256k1 key
+type PubKeyInfo struct {
+	PubKeyCompressed   string `json:"pub_key_compressed"`             // hex
+	PubKeyUncompressed string `json:"pub_key_uncompressed,omitempty"` // hex
+	BTCP2WPKH          string `json:"btc_p2wpkh,omitempty"`
+	BTCP2PKH           string `json:"btc_p2pkh,omitempty"`
+	ETH                string `json:"eth,omitempty"` // with the EIP-55 checksum
+	Cosmos             string `json:"cosmos"`
+}
+
+// GetPubKeyInfo return the encodings of the given key, the Cosmos address uses the given prefix, or the default
+// one when it is empty
+func GetPubKeyInfo(pubKey tcrypto.PubKey, cosmosPrefix string) (PubKeyInfo, error) {
+	if len(cosmosPrefix) == 0 {
+		cosmosPrefix = DefaultCosmosPrefix
+	}
+	cosmosAddr, err := bech32.ConvertAndEncode(cosmosPrefix, pubKey.Address().Bytes())
+	if err != nil {
+		return PubKeyInfo{}, fmt.Errorf("fail to encode the cosmos address: %w", err)
+	}
+	switch pk := pubKey.(type) {
+	case secp256k1.PubKeySecp256k1:
+		return getSecp256k1PubKeyInfo(pk, cosmosAddr)
+	case ed25519.PubKeyEd25519:
+		return PubKeyInfo{
+			PubKeyCompressed: hex.EncodeToString(pk[:]),
+			Cosmos:           cosmosAddr,
+		}, nil
+	default:
+		return PubKeyInfo{}, fmt.Errorf("unsupported pub key type(%T)", pubKey)
+	}
+}
+
+func getSecp256k1PubKeyInfo(pk secp256k1.PubKeySecp256k1, cosmosAddr string) (PubKeyInfo, error) {
+	pub, err := btcec.ParsePubKey(pk[:], btcec.S256())
+	if err != nil {
+		return PubKeyInfo{}, fmt.Errorf("fail to parse the pub key: %w", err)
+	}
+	pubKeyHash := btcutil.Hash160(pk[:])
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+	if err != nil {
+		return PubKeyInfo{}, fmt.Errorf("fail to get the P2WPKH address: %w", err)
+	}
+	p2pkh, err := btcutil.NewAddressPubKeyHash(pubKeyHash, &chaincfg.MainNetParams)
+	if err != nil {
+		return PubKeyInfo{}, fmt.Errorf("fail to get the P2PKH address: %w", err)
+	}
+	uncompressed := pub.SerializeUncompressed()
+	return PubKeyInfo{
+		PubKeyCompressed:   hex.EncodeToString(pk[:]),
+		PubKeyUncompressed: hex.EncodeToString(uncompressed),
+		BTCP2WPKH:          p2wpkh.EncodeAddress(),
+		BTCP2PKH:           p2pkh.EncodeAddress(),
+		ETH:                getEthAddress(uncompressed),
+		Cosmos:             cosmosAddr,
+	}, nil
+}
+
+// getEthAddress return the last 20 bytes of the keccak256 of the uncompressed key without the prefix byte
+func getEthAddress(uncompressed []byte) string {
+	hasher := sha3.NewLegacyKeccak256()
+	// write to the hasher never fails
+	_, _ = hasher.Write(uncompressed[1:])
+	return toEthChecksumAddress(hex.EncodeToString(hasher.Sum(nil)[12:]))
+}
+
+// toEthChecksumAddress return the given lower case hex address in the mixed case of EIP-55
+func toEthChecksumAddress(addr string) string {
+	hasher := sha3.NewLegacyKeccak256()
+	_, _ = hasher.Write([]byte(addr))
+	checksum := hasher.Sum(nil)
+	var sb strings.Builder
+	sb.WriteString("0x")
+	for i, ch := range addr {
+		// the letter is in upper case when the matching nibble of the checksum is 8 or higher
+		nibble := checksum[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if ch >= 'a' && nibble&0x0f >= 8 {
+			ch -= 'a' - 'A'
+		}
+		sb.WriteRune(ch)
+	}
+	return sb.String()
+}
+
+// GetPubKeyInfoFromBech32 return the encodings of the given pool key, which is in bech32 or hex
+func GetPubKeyInfoFromBech32(poolPubKey, cosmosPrefix string) (PubKeyInfo, error) {
+	if len(poolPubKey) == 0 {
+		return PubKeyInfo{}, errors.New("empty pool pub key")
+	}
+	pk, err := ParsePubKey(poolPubKey)
+	if err != nil {
+		return PubKeyInfo{}, fmt.Errorf("fail to get pubkey from bech32 pubkey string(%s):%w", poolPubKey, err)
+	}
+	return GetPubKeyInfo(pk, cosmosPrefix)
+}
@@ -0,0 +1,80 @@
+package conversion
+
+import (
+	"encoding/hex"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+)
+
+type AddressTestSuite struct{}
+
+var _ = Suite(&AddressTestSuite{})
+
+func (*AddressTestSuite) SetUpSuite(c *C) {
+	SetupBech32Prefix()
+}
+
+func (*AddressTestSuite) TestGetPubKeyInfo(c *C) {
+	// the key of the private key 1, which is the generator point
+	buf, err := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	c.Assert(err, IsNil)
+	var pk secp256k1.PubKeySecp256k1
+	copy(pk[:], buf)
+	info, err := GetPubKeyInfo(pk, "")
+	c.Assert(err, IsNil)
+	c.Assert(info.PubKeyCompressed, Equals, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	c.Assert(info.PubKeyUncompressed, Equals, "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+	c.Assert(info.BTCP2WPKH, Equals, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+	c.Assert(info.BTCP2PKH, Equals, "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH")
+	c.Assert(info.ETH, Equals, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+	c.Assert(info.Cosmos, Equals, "cosmos1w508d6qejxtdg4y5r3zarvary0c5xw7k6ah60c")
+	info, err = GetPubKeyInfo(pk, "thor")
+	c.Assert(err, IsNil)
+	c.Assert(info.Cosmos, Equals, "thor1w508d6qejxtdg4y5r3zarvary0c5xw7ku6wp68")
+
+	// the public key of the test 1 of RFC 8032
+	buf, err = hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	c.Assert(err, IsNil)
+	var edPk ed25519.PubKeyEd25519
+	copy(edPk[:], buf)
+	info, err = GetPubKeyInfo(edPk, "")
+	c.Assert(err, IsNil)
+	c.Assert(info.PubKeyCompressed, Equals, "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	c.Assert(info.PubKeyUncompressed, Equals, "")
+	c.Assert(info.BTCP2WPKH, Equals, "")
+	c.Assert(info.ETH, Equals, "")
+	c.Assert(info.Cosmos, Equals, "cosmos1y8lrrhap2j3xzcntlp2qgm7jyudhhm2tc7hkue")
+}
+
+func (*AddressTestSuite) TestToEthChecksumAddress(c *C) {
+	// the examples of EIP-55
+	for _, addr := range []string{
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0xde709f2102306220921060314715629080e2fb77",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		c.Assert(toEthChecksumAddress(strings.ToLower(addr[2:])), Equals, addr)
+	}
+}
+
+func (*AddressTestSuite) TestGetPubKeyInfoFromBech32(c *C) {
+	poolPubKey := "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"
+	info, err := GetPubKeyInfoFromBech32(poolPubKey, "thor")
+	c.Assert(err, IsNil)
+	pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, poolPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(info.Cosmos, Equals, sdk.AccAddress(pk.Address()).String())
+	c.Assert(info.PubKeyCompressed, Equals, hex.EncodeToString(pk.Bytes()[len(pk.Bytes())-33:]))
+
+	_, err = GetPubKeyInfoFromBech32("", "")
+	c.Assert(err, NotNil)
+	_, err = GetPubKeyInfoFromBech32("whatever", "")
+	c.Assert(err, NotNil)
+}
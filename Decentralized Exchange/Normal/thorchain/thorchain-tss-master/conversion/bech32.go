@@ -1,9 +1,27 @@
 package conversion
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	tcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	cryptoamino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/bech32"
+)
+
+const (
+	// DefaultBech32Prefix is the account prefix of THORChain, it is used when the TssServer is not given one
+	DefaultBech32Prefix = "thor"
+	// pubKeySuffix is appended to the account prefix to get the prefix of the account pub key, as cosmos-sdk does
+	pubKeySuffix = "pub"
 )
 
+// SetupBech32Prefix set the THORChain prefixes to the global config of cosmos-sdk, go-tss itself doesn't rely on
+// the global config any more, the prefix of a TssServer comes from its TssConfig
 func SetupBech32Prefix() {
 	config := sdk.GetConfig()
 	// thorchain will import go-tss as a library , thus this is not needed, we copy the prefix here to avoid go-tss to import thorchain
@@ -11,3 +29,92 @@ func SetupBech32Prefix() {
 	config.SetBech32PrefixForValidator("thorv", "thorvpub")
 	config.SetBech32PrefixForConsensusNode("thorc", "thorcpub")
 }
+
+// GetBech32PubKeyPrefix return the prefix of the account pub key of the given account prefix, the default prefix is
+// used when it is empty
+func GetBech32PubKeyPrefix(prefix string) string {
+	if len(prefix) == 0 {
+		prefix = DefaultBech32Prefix
+	}
+	return prefix + pubKeySuffix
+}
+
+// Bech32ifyPubKey encode the given pub key in bech32 with the account pub key prefix of the given account prefix
+func Bech32ifyPubKey(prefix string, pubKey tcrypto.PubKey) (string, error) {
+	if pubKey == nil {
+		return "", errors.New("nil pub key")
+	}
+	return bech32.ConvertAndEncode(GetBech32PubKeyPrefix(prefix), pubKey.Bytes())
+}
+
+// ParsePubKey parse the pub key either in bech32, whatever the prefix is, or in raw hex, which is the 33 bytes
+// compressed secp256k1 key or the 32 bytes ed25519 key, the keys sent by the callers are parsed with
+// ParsePubKeyWithPrefix instead
+func ParsePubKey(pubKey string) (tcrypto.PubKey, error) {
+	return parsePubKey(pubKey, "")
+}
+
+// ParsePubKeyWithPrefix parse the pub key either in bech32 with the account pub key prefix of the given account
+// prefix, or in raw hex, the bech32 keys of any other prefix are rejected
+func ParsePubKeyWithPrefix(pubKey, prefix string) (tcrypto.PubKey, error) {
+	return parsePubKey(pubKey, GetBech32PubKeyPrefix(prefix))
+}
+
+// parsePubKey parse the pub key in bech32 or raw hex, the bech32 prefix is only checked when hrp is not empty
+func parsePubKey(pubKey, hrp string) (tcrypto.PubKey, error) {
+	if len(pubKey) == 0 {
+		return nil, errors.New("empty pub key")
+	}
+	if buf, err := hex.DecodeString(pubKey); err == nil {
+		switch len(buf) {
+		case secp256k1.PubKeySecp256k1Size:
+			var pk secp256k1.PubKeySecp256k1
+			copy(pk[:], buf)
+			return pk, nil
+		case ed25519.PubKeyEd25519Size:
+			var pk ed25519.PubKeyEd25519
+			copy(pk[:], buf)
+			return pk, nil
+		default:
+			return nil, fmt.Errorf("invalid hex pub key length(%d)", len(buf))
+		}
+	}
+	prefix, buf, err := bech32.DecodeAndConvert(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the bech32 pub key: %w", err)
+	}
+	if len(hrp) != 0 && prefix != hrp {
+		return nil, fmt.Errorf("invalid bech32 pub key prefix(%s), expect %s", prefix, hrp)
+	}
+	pk, err := cryptoamino.PubKeyFromBytes(buf)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the pub key: %w", err)
+	}
+	return pk, nil
+}
+
+// NormalizePubKey return the given bech32 or hex pub key in bech32 with the given account prefix, the bech32 key
+// must already have the account pub key prefix of the given account prefix
+func NormalizePubKey(pubKey, prefix string) (string, error) {
+	pk, err := ParsePubKeyWithPrefix(pubKey, prefix)
+	if err != nil {
+		return "", err
+	}
+	return Bech32ifyPubKey(prefix, pk)
+}
+
+// NormalizePubKeys return the given pub keys in bech32 with the given account prefix
+func NormalizePubKeys(pubKeys []string, prefix string) ([]string, error) {
+	if pubKeys == nil {
+		return nil, nil
+	}
+	result := make([]string, len(pubKeys))
+	for i, el := range pubKeys {
+		pk, err := NormalizePubKey(el, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("fail to parse pub key(%s): %w", el, err)
+		}
+		result[i] = pk
+	}
+	return result, nil
+}
@@ -0,0 +1,99 @@
+package conversion
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+)
+
+type Bech32TestSuite struct{}
+
+var _ = Suite(&Bech32TestSuite{})
+
+const testNodePubKey = "thorpub1addwnpepqtctt9l4fddeh0krvdpxmqsxa5z9xsa0ac6frqfhm9fq6c6u5lck5s8fm4n"
+
+func (*Bech32TestSuite) TestGetBech32PubKeyPrefix(c *C) {
+	c.Assert(GetBech32PubKeyPrefix(""), Equals, "thorpub")
+	c.Assert(GetBech32PubKeyPrefix("tthor"), Equals, "tthorpub")
+	c.Assert(GetBech32PubKeyPrefix("cosmos"), Equals, "cosmospub")
+}
+
+func (*Bech32TestSuite) TestParsePubKey(c *C) {
+	pk, err := ParsePubKey(testNodePubKey)
+	c.Assert(err, IsNil)
+	secpPk, ok := pk.(secp256k1.PubKeySecp256k1)
+	c.Assert(ok, Equals, true)
+
+	// the same key in raw hex
+	hexPk, err := ParsePubKey(hex.EncodeToString(secpPk[:]))
+	c.Assert(err, IsNil)
+	c.Assert(hexPk.Equals(pk), Equals, true)
+
+	// the same key with another prefix
+	cosmosPubKey, err := Bech32ifyPubKey("cosmos", pk)
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(cosmosPubKey, "cosmospub1"), Equals, true)
+	cosmosPk, err := ParsePubKey(cosmosPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(cosmosPk.Equals(pk), Equals, true)
+	cosmosPk, err = ParsePubKeyWithPrefix(cosmosPubKey, "cosmos")
+	c.Assert(err, IsNil)
+	c.Assert(cosmosPk.Equals(pk), Equals, true)
+	_, err = ParsePubKeyWithPrefix(cosmosPubKey, "")
+	c.Assert(err, NotNil)
+	_, err = ParsePubKeyWithPrefix(testNodePubKey, "tthor")
+	c.Assert(err, NotNil)
+	// the raw hex key has no prefix to check
+	hexPk, err = ParsePubKeyWithPrefix(hex.EncodeToString(secpPk[:]), "tthor")
+	c.Assert(err, IsNil)
+	c.Assert(hexPk.Equals(pk), Equals, true)
+
+	edPk := ed25519.GenPrivKey().PubKey().(ed25519.PubKeyEd25519)
+	parsed, err := ParsePubKey(hex.EncodeToString(edPk[:]))
+	c.Assert(err, IsNil)
+	c.Assert(parsed.Equals(edPk), Equals, true)
+
+	for _, el := range []string{"", "whatever", "0102", testNodePubKey[:len(testNodePubKey)-1]} {
+		_, err = ParsePubKey(el)
+		c.Assert(err, NotNil)
+	}
+}
+
+func (*Bech32TestSuite) TestNormalizePubKeys(c *C) {
+	pk, err := ParsePubKey(testNodePubKey)
+	c.Assert(err, IsNil)
+	secpPk := pk.(secp256k1.PubKeySecp256k1)
+	hexPubKey := hex.EncodeToString(secpPk[:])
+
+	keys, err := NormalizePubKeys([]string{testNodePubKey, hexPubKey}, "")
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{testNodePubKey, testNodePubKey})
+	keys, err = NormalizePubKeys([]string{hexPubKey}, "tthor")
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(keys[0], "tthorpub1"), Equals, true)
+	tthorPubKey := keys[0]
+	keys, err = NormalizePubKeys([]string{tthorPubKey, hexPubKey}, "tthor")
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{tthorPubKey, tthorPubKey})
+	// the key of another chain is not normalized into the prefix of this one
+	_, err = NormalizePubKeys([]string{testNodePubKey, hexPubKey}, "tthor")
+	c.Assert(err, NotNil)
+	keys, err = NormalizePubKeys(nil, "")
+	c.Assert(err, IsNil)
+	c.Assert(keys, IsNil)
+	_, err = NormalizePubKeys([]string{testNodePubKey, "whatever"}, "")
+	c.Assert(err, NotNil)
+
+	// the peer id doesn't depend on the encoding of the node key
+	peerID, err := GetPeerIDFromPubKey(testNodePubKey)
+	c.Assert(err, IsNil)
+	hexPeerID, err := GetPeerIDFromPubKey(hexPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(hexPeerID, Equals, peerID)
+	pubKey, err := GetPubKeyFromPeerID(peerID.String(), "tthor")
+	c.Assert(err, IsNil)
+	c.Assert(pubKey, Equals, tthorPubKey)
+}
@@ -12,7 +12,8 @@ import (
 	"github.com/binance-chain/go-sdk/common/types"
 	"github.com/binance-chain/tss-lib/crypto"
 	"github.com/btcsuite/btcd/btcec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 
 	btss "github.com/binance-chain/tss-lib/tss"
 	crypto2 "github.com/libp2p/go-libp2p-core/crypto"
@@ -35,34 +36,49 @@ func GetPeerIDFromPartyID(partyID *btss.PartyID) (peer.ID, error) {
 	if partyID == nil || !partyID.ValidateBasic() {
 		return "", errors.New("invalid partyID")
 	}
-	pkBytes := partyID.KeyInt().Bytes()
+	return GetPeerIDFromSecp256PubKey(PartyKeyToSecp256PubKey(partyID.KeyInt()))
+}
+
+// the party key is the secp256k1 public key of the node, the committee of a key resharing puts a tag above the
+// public key bits, so a node that is in both the old and the new committee has two different party keys
+const partyKeyTagShift = len(secp256k1.PubKeySecp256k1{}) * 8
+
+// PartyKeyToSecp256PubKey return the public key the given party key is derived from
+func PartyKeyToSecp256PubKey(key *big.Int) secp256k1.PubKeySecp256k1 {
+	mask := new(big.Int).Lsh(big.NewInt(1), uint(partyKeyTagShift))
+	mask.Sub(mask, big.NewInt(1))
 	var pk secp256k1.PubKeySecp256k1
-	copy(pk[:], pkBytes)
-	return GetPeerIDFromSecp256PubKey(pk)
+	copy(pk[:], new(big.Int).And(key, mask).Bytes())
+	return pk
 }
 
-func PartyIDtoPubKey(party *btss.PartyID) (string, error) {
+// GetPartyKeyTag return the committee tag of the given party key, it is 0 for the key created in keygen
+func GetPartyKeyTag(key *big.Int) int64 {
+	return new(big.Int).Rsh(key, uint(partyKeyTagShift)).Int64()
+}
+
+// PartyIDtoPubKey return the node pub key of the given party in bech32 with the given prefix
+func PartyIDtoPubKey(party *btss.PartyID, prefix string) (string, error) {
 	if party == nil || !party.ValidateBasic() {
 		return "", errors.New("invalid party")
 	}
-	partyKeyBytes := party.GetKey()
-	var pk secp256k1.PubKeySecp256k1
-	copy(pk[:], partyKeyBytes)
-	pubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, pk)
+	pk := PartyKeyToSecp256PubKey(party.KeyInt())
+	pubKey, err := Bech32ifyPubKey(prefix, pk)
 	if err != nil {
 		return "", err
 	}
 	return pubKey, nil
 }
 
-func AccPubKeysFromPartyIDs(partyIDs []string, partyIDMap map[string]*btss.PartyID) ([]string, error) {
+// AccPubKeysFromPartyIDs return the node pub keys of the given parties in bech32 with the given prefix
+func AccPubKeysFromPartyIDs(partyIDs []string, partyIDMap map[string]*btss.PartyID, prefix string) ([]string, error) {
 	pubKeys := make([]string, 0)
 	for _, partyID := range partyIDs {
 		blameParty, ok := partyIDMap[partyID]
 		if !ok {
 			return nil, errors.New("cannot find the blame party")
 		}
-		blamedPubKey, err := PartyIDtoPubKey(blameParty)
+		blamedPubKey, err := PartyIDtoPubKey(blameParty, prefix)
 		if err != nil {
 			return nil, err
 		}
@@ -105,29 +121,45 @@ func SetupIDMaps(parties map[string]*btss.PartyID, partyIDtoP2PID map[string]pee
 }
 
 func GetParties(keys []string, localPartyKey string) ([]*btss.PartyID, *btss.PartyID, error) {
+	partiesID, localPartyID, err := GetCommitteeParties(keys, localPartyKey, "", 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if localPartyID == nil {
+		return nil, nil, errors.New("local party is not in the list")
+	}
+	return partiesID, localPartyID, nil
+}
+
+// GetCommitteeParties return the sorted parties of the given keys, the party ID is prefixed with the given prefix
+// and the party key carries the given tag, the local party is nil if the local node is not one of the keys
+func GetCommitteeParties(keys []string, localPartyKey, idPrefix string, tag int64) ([]*btss.PartyID, *btss.PartyID, error) {
 	var localPartyID *btss.PartyID
 	var unSortedPartiesID []*btss.PartyID
 	sort.Strings(keys)
 	for idx, item := range keys {
-		pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, item)
+		pk, err := ParsePubKey(item)
 		if err != nil {
 			return nil, nil, fmt.Errorf("fail to get account pub key address(%s): %w", item, err)
 		}
-		secpPk := pk.(secp256k1.PubKeySecp256k1)
+		secpPk, ok := pk.(secp256k1.PubKeySecp256k1)
+		if !ok {
+			return nil, nil, fmt.Errorf("the node pub key(%s) is not a secp256k1 key", item)
+		}
 		key := new(big.Int).SetBytes(secpPk[:])
+		if tag != 0 {
+			key.Add(key, new(big.Int).Lsh(big.NewInt(tag), uint(partyKeyTagShift)))
+		}
 		// Set up the parameters
 		// Note: The `id` and `moniker` fields are for convenience to allow you to easily track participants.
 		// The `id` should be a unique string representing this party in the network and `moniker` can be anything (even left blank).
 		// The `uniqueKey` is a unique identifying key for this peer (such as its p2p public key) as a big.Int.
-		partyID := btss.NewPartyID(strconv.Itoa(idx), "", key)
+		partyID := btss.NewPartyID(idPrefix+strconv.Itoa(idx), "", key)
 		if item == localPartyKey {
 			localPartyID = partyID
 		}
 		unSortedPartiesID = append(unSortedPartiesID, partyID)
 	}
-	if localPartyID == nil {
-		return nil, nil, errors.New("local party is not in the list")
-	}
 
 	partiesID := btss.SortPartyIDs(unSortedPartiesID)
 	return partiesID, localPartyID, nil
@@ -137,10 +169,19 @@ func GetKeyGenUicast() string {
 	return messages.KEYGEN2aUnicast
 }
 
+// GetEdDSAKeyGenUnicast return the round of the unicast message in EdDSA keygen
+func GetEdDSAKeyGenUnicast() string {
+	return messages.EDDSAKEYGEN2aUnicast
+}
+
+// GetPreviousKeySignUicast return the round of the last unicast message before the given one, the EdDSA keysign
+// has no unicast message at all, so it returns empty
 func GetPreviousKeySignUicast(current string) string {
 	switch current {
 	case messages.KEYSIGN1b:
 		return messages.KEYSIGN1aUnicast
+	case messages.EDDSAKEYSIGN1, messages.EDDSAKEYSIGN2, messages.EDDSAKEYSIGN3:
+		return ""
 	default:
 		return messages.KEYSIGN2Unicast
 	}
@@ -151,7 +192,8 @@ func isOnCurve(x, y *big.Int) bool {
 	return curve.IsOnCurve(x, y)
 }
 
-func GetTssPubKey(pubKeyPoint *crypto.ECPoint) (string, types.AccAddress, error) {
+// GetTssPubKey return the pub key in bech32 with the given prefix and the address of the given ECDSA pool key
+func GetTssPubKey(pubKeyPoint *crypto.ECPoint, prefix string) (string, types.AccAddress, error) {
 	// we check whether the point is on curve according to Kudelski report
 	if pubKeyPoint == nil || !isOnCurve(pubKeyPoint.X(), pubKeyPoint.Y()) {
 		return "", types.AccAddress{}, errors.New("invalid points")
@@ -163,7 +205,20 @@ func GetTssPubKey(pubKeyPoint *crypto.ECPoint) (string, types.AccAddress, error)
 	}
 	var pubKeyCompressed secp256k1.PubKeySecp256k1
 	copy(pubKeyCompressed[:], tssPubKey.SerializeCompressed())
-	pubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, pubKeyCompressed)
+	pubKey, err := Bech32ifyPubKey(prefix, pubKeyCompressed)
+	addr := types.AccAddress(pubKeyCompressed.Address().Bytes())
+	return pubKey, addr, err
+}
+
+// GetEdDSATssPubKey return the pub key in bech32 with the given prefix and the address of the given EdDSA pool key
+func GetEdDSATssPubKey(pubKeyPoint *crypto.ECPoint, prefix string) (string, types.AccAddress, error) {
+	if pubKeyPoint == nil || !edwards.Edwards().IsOnCurve(pubKeyPoint.X(), pubKeyPoint.Y()) {
+		return "", types.AccAddress{}, errors.New("invalid points")
+	}
+	tssPubKey := edwards.NewPublicKey(pubKeyPoint.X(), pubKeyPoint.Y())
+	var pubKeyCompressed ed25519.PubKeyEd25519
+	copy(pubKeyCompressed[:], tssPubKey.Serialize())
+	pubKey, err := Bech32ifyPubKey(prefix, pubKeyCompressed)
 	addr := types.AccAddress(pubKeyCompressed.Address().Bytes())
 	return pubKey, addr, err
 }
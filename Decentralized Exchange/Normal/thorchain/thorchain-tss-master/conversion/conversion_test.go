@@ -9,7 +9,9 @@ import (
 	"github.com/binance-chain/tss-lib/crypto"
 	"github.com/btcsuite/btcd/btcec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 	. "gopkg.in/check.v1"
 )
@@ -50,11 +52,11 @@ func (p *ConversionTestSuite) TestAccPubKeysFromPartyIDs(c *C) {
 		keys = append(keys, k)
 	}
 
-	got, err := AccPubKeysFromPartyIDs(keys, partyIDMap)
+	got, err := AccPubKeysFromPartyIDs(keys, partyIDMap, DefaultBech32Prefix)
 	c.Assert(err, IsNil)
 	sort.Strings(got)
 	c.Assert(got, DeepEquals, p.testPubKeys)
-	got, err = AccPubKeysFromPartyIDs(nil, partyIDMap)
+	got, err = AccPubKeysFromPartyIDs(nil, partyIDMap, DefaultBech32Prefix)
 	c.Assert(err, Equals, nil)
 	c.Assert(len(got), Equals, 0)
 }
@@ -86,6 +88,32 @@ func (p *ConversionTestSuite) TestGetParties(c *C) {
 	c.Assert(err, NotNil)
 }
 
+func (p *ConversionTestSuite) TestGetCommitteeParties(c *C) {
+	oldParties, oldLocal, err := GetParties(p.testPubKeys, p.testPubKeys[0])
+	c.Assert(err, IsNil)
+	newParties, newLocal, err := GetCommitteeParties(p.testPubKeys, p.testPubKeys[0], "new-", 3)
+	c.Assert(err, IsNil)
+	c.Assert(newParties, HasLen, len(oldParties))
+	c.Assert(newLocal.Id, Equals, "new-"+oldLocal.Id)
+	c.Assert(newLocal.KeyInt().Cmp(oldLocal.KeyInt()), Not(Equals), 0)
+	c.Assert(GetPartyKeyTag(oldLocal.KeyInt()), Equals, int64(0))
+	c.Assert(GetPartyKeyTag(newLocal.KeyInt()), Equals, int64(3))
+	// the tag does not change the order of the parties
+	for i := range oldParties {
+		c.Assert(newParties[i].Id, Equals, "new-"+oldParties[i].Id)
+	}
+	pubKey, err := PartyIDtoPubKey(newLocal, DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	c.Assert(pubKey, Equals, p.testPubKeys[0])
+	peerID, err := GetPeerIDFromPartyID(newLocal)
+	c.Assert(err, IsNil)
+	c.Assert(peerID, Equals, p.localPeerID)
+
+	_, local, err := GetCommitteeParties(p.testPubKeys[1:], p.testPubKeys[0], "", 0)
+	c.Assert(err, IsNil)
+	c.Assert(local, IsNil)
+}
+
 //
 func (p *ConversionTestSuite) TestGetPeerIDFromPartyID(c *C) {
 	_, localParty, err := GetParties(p.testPubKeys, p.testPubKeys[0])
@@ -150,13 +178,13 @@ func (p *ConversionTestSuite) TestGetPeersID(c *C) {
 func (p *ConversionTestSuite) TestPartyIDtoPubKey(c *C) {
 	_, localParty, err := GetParties(p.testPubKeys, p.testPubKeys[0])
 	c.Assert(err, IsNil)
-	got, err := PartyIDtoPubKey(localParty)
+	got, err := PartyIDtoPubKey(localParty, DefaultBech32Prefix)
 	c.Assert(err, IsNil)
 	c.Assert(got, Equals, p.testPubKeys[0])
-	_, err = PartyIDtoPubKey(nil)
+	_, err = PartyIDtoPubKey(nil, DefaultBech32Prefix)
 	c.Assert(err, NotNil)
 	localParty.Index = -1
-	_, err = PartyIDtoPubKey(nil)
+	_, err = PartyIDtoPubKey(nil, DefaultBech32Prefix)
 	c.Assert(err, NotNil)
 }
 
@@ -208,23 +236,49 @@ func (p *ConversionTestSuite) TestTssPubKey(c *C) {
 	c.Assert(err, IsNil)
 	point, err := crypto.NewECPoint(btcec.S256(), sk.X, sk.Y)
 	c.Assert(err, IsNil)
-	_, _, err = GetTssPubKey(point)
+	_, _, err = GetTssPubKey(point, DefaultBech32Prefix)
 	c.Assert(err, IsNil)
 
 	// create an invalid point
 	invalidPoint := crypto.NewECPointNoCurveCheck(btcec.S256(), sk.X, new(big.Int).Add(sk.Y, big.NewInt(1)))
-	_, _, err = GetTssPubKey(invalidPoint)
+	_, _, err = GetTssPubKey(invalidPoint, DefaultBech32Prefix)
 	c.Assert(err, NotNil)
 
-	pk, addr, err := GetTssPubKey(nil)
+	pk, addr, err := GetTssPubKey(nil, DefaultBech32Prefix)
 	c.Assert(err, NotNil)
 	c.Assert(pk, Equals, "")
 	c.Assert(addr.Bytes(), HasLen, 0)
 	SetupBech32Prefix()
 	// var point crypto.ECPoint
 	c.Assert(json.Unmarshal([]byte(`{"Coords":[70074650318631491136896111706876206496089700125696166275258483716815143842813,72125378038650252881868972131323661098816214918201601489154946637636730727892]}`), &point), IsNil)
-	pk, addr, err = GetTssPubKey(point)
+	pk, addr, err = GetTssPubKey(point, DefaultBech32Prefix)
 	c.Assert(err, IsNil)
 	c.Assert(pk, Equals, "thorpub1addwnpepq2dwek9hkrlxjxadrlmy9fr42gqyq6029q0hked46l3u6a9fxqel6tma5eu")
 	c.Assert(addr.String(), Equals, "bnb17l7cyxqzg4xymnl0alrhqwja276s3rns4256c2")
 }
+
+func (p *ConversionTestSuite) TestEdDSATssPubKey(c *C) {
+	SetupBech32Prefix()
+	edPubKey := ed25519.GenPrivKey().PubKey().(ed25519.PubKeyEd25519)
+	pub, err := edwards.ParsePubKey(edPubKey[:])
+	c.Assert(err, IsNil)
+	point, err := crypto.NewECPoint(edwards.Edwards(), pub.X, pub.Y)
+	c.Assert(err, IsNil)
+	pk, addr, err := GetEdDSATssPubKey(point, DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	expectedPk, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, edPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(pk, Equals, expectedPk)
+	c.Assert(addr.Bytes(), DeepEquals, edPubKey.Address().Bytes())
+	ret, err := CheckKeyOnCurve(pk)
+	c.Assert(err, IsNil)
+	c.Assert(ret, Equals, true)
+
+	// the secp256k1 point is not on the curve of EdDSA
+	sk, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	_, _, err = GetEdDSATssPubKey(crypto.NewECPointNoCurveCheck(edwards.Edwards(), sk.X, sk.Y), DefaultBech32Prefix)
+	c.Assert(err, NotNil)
+	_, _, err = GetEdDSATssPubKey(nil, DefaultBech32Prefix)
+	c.Assert(err, NotNil)
+}
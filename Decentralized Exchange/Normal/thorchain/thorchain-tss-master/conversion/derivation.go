@@ -0,0 +1,124 @@
+package conversion
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+)
+
+// the indexes from 2^31 are the hardened ones, which need the private key to derive
+const hardenedKeyStart = uint32(0x80000000)
+
+// ParseDerivationPath parse the BIP32 path like m/0/1, only the non-hardened indexes are allowed as nobody holds the
+// private key of the pool
+func ParseDerivationPath(path string) ([]uint32, error) {
+	path = strings.TrimSpace(path)
+	if path == "" || path == "m" {
+		return nil, nil
+	}
+	parts := strings.Split(strings.TrimPrefix(path, "m/"), "/")
+	indexes := make([]uint32, len(parts))
+	for i, el := range parts {
+		if strings.HasSuffix(el, "'") || strings.HasSuffix(el, "h") {
+			return nil, fmt.Errorf("hardened index(%s) is not supported", el)
+		}
+		idx, err := strconv.ParseUint(el, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index(%s): %w", el, err)
+		}
+		if uint32(idx) >= hardenedKeyStart {
+			return nil, fmt.Errorf("index(%d) is out of the non-hardened range", idx)
+		}
+		indexes[i] = uint32(idx)
+	}
+	return indexes, nil
+}
+
+// PoolChainCode return the chain code of the pool key, the pool key comes out of the keygen without one, so it is
+// the SHA256 of the 33 bytes compressed pool key. The wallets build the extended pub key of the pool with this chain
+// code, depth 0, the zero parent fingerprint and child number, then the standard BIP32 public derivation gives the
+// same child keys as DeriveChildKey
+func PoolChainCode(pubKey *btcec.PublicKey) []byte {
+	chainCode := sha256.Sum256(pubKey.SerializeCompressed())
+	return chainCode[:]
+}
+
+// DeriveChildKey derive the child key of the given key along the path, and return the sum of the tweaks of each
+// level, the child key is the given key plus tweak*G, so the parties add the tweak to their shares to sign with it.
+// The derivation is the BIP32 public derivation with the chain code of PoolChainCode
+func DeriveChildKey(pubKey *btcec.PublicKey, path string) (*btcec.PublicKey, *big.Int, error) {
+	indexes, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	curve := btcec.S256()
+	code := PoolChainCode(pubKey)
+	tweak := big.NewInt(0)
+	child := pubKey
+	for _, idx := range indexes {
+		mac := hmac.New(sha512.New, code)
+		data := make([]byte, 37)
+		copy(data, child.SerializeCompressed())
+		binary.BigEndian.PutUint32(data[33:], idx)
+		if _, err := mac.Write(data); err != nil {
+			return nil, nil, fmt.Errorf("fail to hash the key: %w", err)
+		}
+		sum := mac.Sum(nil)
+		il := new(big.Int).SetBytes(sum[:32])
+		// BIP32 skips such index, the chance is lower than 1 in 2^127
+		if il.Cmp(curve.N) >= 0 {
+			return nil, nil, fmt.Errorf("invalid child key at index(%d)", idx)
+		}
+		x, y := curve.ScalarBaseMult(sum[:32])
+		x, y = curve.Add(child.X, child.Y, x, y)
+		if x.Sign() == 0 && y.Sign() == 0 {
+			return nil, nil, fmt.Errorf("invalid child key at index(%d)", idx)
+		}
+		child = &btcec.PublicKey{Curve: curve, X: x, Y: y}
+		tweak.Add(tweak, il)
+		tweak.Mod(tweak, curve.N)
+		code = sum[32:]
+	}
+	return child, tweak, nil
+}
+
+// GetDerivedPubKey return the pub key in bech32 with the given prefix and the address of the child key of the given
+// pool along the path, and the tweak the parties add to their shares to sign with the child key
+func GetDerivedPubKey(poolPubKey, path, prefix string) (string, types.AccAddress, *big.Int, error) {
+	pk, err := ParsePubKey(poolPubKey)
+	if err != nil {
+		return "", types.AccAddress{}, nil, fmt.Errorf("fail to get pubkey from bech32 pubkey string(%s):%w", poolPubKey, err)
+	}
+	secpPubKey, ok := pk.(secp256k1.PubKeySecp256k1)
+	if !ok {
+		return "", types.AccAddress{}, nil, errors.New("only the secp256k1 pool key can be derived")
+	}
+	parentKey, err := btcec.ParsePubKey(secpPubKey[:], btcec.S256())
+	if err != nil {
+		return "", types.AccAddress{}, nil, fmt.Errorf("fail to parse the pool key: %w", err)
+	}
+	child, tweak, err := DeriveChildKey(parentKey, path)
+	if err != nil {
+		return "", types.AccAddress{}, nil, err
+	}
+	point, err := crypto.NewECPoint(btcec.S256(), child.X, child.Y)
+	if err != nil {
+		return "", types.AccAddress{}, nil, fmt.Errorf("invalid child key: %w", err)
+	}
+	childPubKey, addr, err := GetTssPubKey(point, prefix)
+	if err != nil {
+		return "", types.AccAddress{}, nil, err
+	}
+	return childPubKey, addr, tweak, nil
+}
@@ -0,0 +1,117 @@
+package conversion
+
+import (
+	"math/big"
+	"strconv"
+
+	"github.com/binance-chain/go-sdk/common/types"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	. "gopkg.in/check.v1"
+)
+
+type DerivationTestSuite struct{}
+
+var _ = Suite(&DerivationTestSuite{})
+
+func (*DerivationTestSuite) SetUpSuite(c *C) {
+	SetupBech32Prefix()
+}
+
+func (*DerivationTestSuite) TestParseDerivationPath(c *C) {
+	for _, path := range []string{"", "m"} {
+		indexes, err := ParseDerivationPath(path)
+		c.Assert(err, IsNil)
+		c.Assert(indexes, HasLen, 0)
+	}
+	for _, path := range []string{"m/0/1/2147483647", "0/1/2147483647"} {
+		indexes, err := ParseDerivationPath(path)
+		c.Assert(err, IsNil)
+		c.Assert(indexes, DeepEquals, []uint32{0, 1, 2147483647})
+	}
+	for _, path := range []string{"m/0'/1", "m/0/1h", "m/2147483648", "m/a", "m//1", "m/-1", "x/0"} {
+		_, err := ParseDerivationPath(path)
+		c.Assert(err, NotNil, Commentf(path))
+	}
+}
+
+func (*DerivationTestSuite) TestDeriveChildKey(c *C) {
+	priKey, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	pubKey := priKey.PubKey()
+	child, tweak, err := DeriveChildKey(pubKey, "m")
+	c.Assert(err, IsNil)
+	c.Assert(child.IsEqual(pubKey), Equals, true)
+	c.Assert(tweak.Sign(), Equals, 0)
+
+	child, tweak, err = DeriveChildKey(pubKey, "m/0/1")
+	c.Assert(err, IsNil)
+	c.Assert(child.IsEqual(pubKey), Equals, false)
+	// the private key of the child is the private key of the parent plus the tweak
+	childPriKey := new(big.Int).Add(priKey.D, tweak)
+	childPriKey.Mod(childPriKey, btcec.S256().N)
+	x, y := btcec.S256().ScalarBaseMult(childPriKey.Bytes())
+	c.Assert(x.Cmp(child.X), Equals, 0)
+	c.Assert(y.Cmp(child.Y), Equals, 0)
+
+	child1, tweak1, err := DeriveChildKey(pubKey, "m/0/1")
+	c.Assert(err, IsNil)
+	c.Assert(child1.IsEqual(child), Equals, true)
+	c.Assert(tweak1.Cmp(tweak), Equals, 0)
+	child2, _, err := DeriveChildKey(pubKey, "m/0/2")
+	c.Assert(err, IsNil)
+	c.Assert(child2.IsEqual(child), Equals, false)
+
+	_, _, err = DeriveChildKey(pubKey, "m/0'")
+	c.Assert(err, NotNil)
+}
+
+func (*DerivationTestSuite) TestDeriveChildKeyAsBIP32(c *C) {
+	priKey, err := btcec.NewPrivateKey(btcec.S256())
+	c.Assert(err, IsNil)
+	pubKey := priKey.PubKey()
+	// the extended pub key the wallets build from the pool key
+	xpub := hdkeychain.NewExtendedKey(chaincfg.MainNetParams.HDPublicKeyID[:], pubKey.SerializeCompressed(),
+		PoolChainCode(pubKey), []byte{0, 0, 0, 0}, 0, 0, false)
+	for _, path := range [][]uint32{{0}, {0, 1}, {7, 2147483647, 3}} {
+		key := xpub
+		p := "m"
+		for _, idx := range path {
+			key, err = key.Child(idx)
+			c.Assert(err, IsNil)
+			p += "/" + strconv.FormatUint(uint64(idx), 10)
+		}
+		expected, err := key.ECPubKey()
+		c.Assert(err, IsNil)
+		child, _, err := DeriveChildKey(pubKey, p)
+		c.Assert(err, IsNil)
+		c.Assert(child.IsEqual(expected), Equals, true, Commentf(p))
+	}
+}
+
+func (*DerivationTestSuite) TestGetDerivedPubKey(c *C) {
+	poolPubKey := "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"
+	pubKey, addr, tweak, err := GetDerivedPubKey(poolPubKey, "m", DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	c.Assert(pubKey, Equals, poolPubKey)
+	c.Assert(tweak.Sign(), Equals, 0)
+	pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, poolPubKey)
+	c.Assert(err, IsNil)
+	poolAddr := types.AccAddress(pk.Address().Bytes())
+	c.Assert(addr.String(), Equals, poolAddr.String())
+
+	childPubKey, childAddr, _, err := GetDerivedPubKey(poolPubKey, "m/0/1", DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	c.Assert(childPubKey, Not(Equals), poolPubKey)
+	c.Assert(childAddr.String(), Not(Equals), poolAddr.String())
+
+	_, _, _, err = GetDerivedPubKey("whatever", "m/0/1", DefaultBech32Prefix)
+	c.Assert(err, NotNil)
+	eddsaPubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, ed25519.GenPrivKey().PubKey())
+	c.Assert(err, IsNil)
+	_, _, _, err = GetDerivedPubKey(eddsaPubKey, "m/0/1", DefaultBech32Prefix)
+	c.Assert(err, NotNil)
+}
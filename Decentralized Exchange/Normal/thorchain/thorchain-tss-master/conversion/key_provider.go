@@ -7,20 +7,24 @@ import (
 	"fmt"
 
 	"github.com/btcsuite/btcd/btcec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/libp2p/go-libp2p-core/peer"
 	tcrypto "github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
-// GetPeerIDFromPubKey get the peer.ID from bech32 format node pub key
+// GetPeerIDFromPubKey get the peer.ID from the node pub key in bech32 or hex
 func GetPeerIDFromPubKey(pubkey string) (peer.ID, error) {
-	pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, pubkey)
+	pk, err := ParsePubKey(pubkey)
 	if err != nil {
 		return "", fmt.Errorf("fail to parse account pub key(%s): %w", pubkey, err)
 	}
-	secpPubKey := pk.(secp256k1.PubKeySecp256k1)
+	secpPubKey, ok := pk.(secp256k1.PubKeySecp256k1)
+	if !ok {
+		return "", fmt.Errorf("the node pub key(%s) is not a secp256k1 key", pubkey)
+	}
 	ppk, err := crypto.UnmarshalSecp256k1PublicKey(secpPubKey[:])
 	if err != nil {
 		return "", fmt.Errorf("fail to convert pubkey to the crypto pubkey used in libp2p: %w", err)
@@ -54,11 +58,11 @@ func GetPeerIDs(pubkeys []string) ([]peer.ID, error) {
 	return peerIDs, nil
 }
 
-// GetPubKeysFromPeerIDs given a list of peer ids, and get a list og pub keys.
-func GetPubKeysFromPeerIDs(peers []string) ([]string, error) {
+// GetPubKeysFromPeerIDs given a list of peer ids, and get a list og pub keys in bech32 with the given prefix.
+func GetPubKeysFromPeerIDs(peers []string, prefix string) ([]string, error) {
 	var result []string
 	for _, item := range peers {
-		pKey, err := GetPubKeyFromPeerID(item)
+		pKey, err := GetPubKeyFromPeerID(item, prefix)
 		if err != nil {
 			return nil, fmt.Errorf("fail to get pubkey from peerID: %w", err)
 		}
@@ -67,8 +71,8 @@ func GetPubKeysFromPeerIDs(peers []string) ([]string, error) {
 	return result, nil
 }
 
-// GetPubKeyFromPeerID extract the pub key from PeerID
-func GetPubKeyFromPeerID(pID string) (string, error) {
+// GetPubKeyFromPeerID extract the pub key from PeerID, and return it in bech32 with the given prefix
+func GetPubKeyFromPeerID(pID, prefix string) (string, error) {
 	peerID, err := peer.Decode(pID)
 	if err != nil {
 		return "", fmt.Errorf("fail to decode peer id: %w", err)
@@ -83,7 +87,7 @@ func GetPubKeyFromPeerID(pID string) (string, error) {
 	}
 	var pubkey secp256k1.PubKeySecp256k1
 	copy(pubkey[:], rawBytes)
-	return sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, pubkey)
+	return Bech32ifyPubKey(prefix, pubkey)
 }
 
 func GetPriKey(priKeyString string) (tcrypto.PrivKey, error) {
@@ -95,10 +99,17 @@ func GetPriKey(priKeyString string) (tcrypto.PrivKey, error) {
 	if err != nil {
 		return nil, fmt.Errorf("fail to hex decode private key: %w", err)
 	}
+	return GetPriKeyFromRawBytes(rawBytes)
+}
+
+// GetPriKeyFromRawBytes return the secp256k1 private key of the given raw bytes, which is what the keystore keeps
+func GetPriKeyFromRawBytes(rawBytes []byte) (tcrypto.PrivKey, error) {
+	if len(rawBytes) < 32 {
+		return nil, fmt.Errorf("invalid private key length(%d)", len(rawBytes))
+	}
 	var keyBytesArray [32]byte
 	copy(keyBytesArray[:], rawBytes[:32])
-	priKey := secp256k1.PrivKeySecp256k1(keyBytesArray)
-	return priKey, nil
+	return secp256k1.PrivKeySecp256k1(keyBytesArray), nil
 }
 
 func GetPriKeyRawBytes(priKey tcrypto.PrivKey) ([]byte, error) {
@@ -112,14 +123,25 @@ func GetPriKeyRawBytes(priKey tcrypto.PrivKey) ([]byte, error) {
 }
 
 func CheckKeyOnCurve(pk string) (bool, error) {
-	pubKey, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, pk)
+	pubKey, err := ParsePubKey(pk)
 	if err != nil {
 		return false, fmt.Errorf("fail to parse pub key(%s): %w", pk, err)
 	}
-	rawPk := pubKey.(secp256k1.PubKeySecp256k1)
-	bPk, err := btcec.ParsePubKey(rawPk[:], btcec.S256())
-	if err != nil {
-		return false, err
+	switch rawPk := pubKey.(type) {
+	case secp256k1.PubKeySecp256k1:
+		bPk, err := btcec.ParsePubKey(rawPk[:], btcec.S256())
+		if err != nil {
+			return false, err
+		}
+		return isOnCurve(bPk.X, bPk.Y), nil
+	case ed25519.PubKeyEd25519:
+		// the pool key of EdDSA
+		ePk, err := edwards.ParsePubKey(rawPk[:])
+		if err != nil {
+			return false, err
+		}
+		return edwards.Edwards().IsOnCurve(ePk.X, ePk.Y), nil
+	default:
+		return false, fmt.Errorf("unsupported pub key type(%T)", pubKey)
 	}
-	return isOnCurve(bPk.X, bPk.Y), nil
 }
@@ -20,7 +20,7 @@ func TestGetPubKeysFromPeerIDs(t *testing.T) {
 		"16Uiu2HAmBdJRswX94UwYj6VLhh4GeUf9X3SjBRgTqFkeEMLmfk2M",
 		"16Uiu2HAkyR9dsFqkj1BqKw8ZHAUU2yur6ZLRJxPTiiVYP5uBMeMG",
 	}
-	result, err := GetPubKeysFromPeerIDs(input)
+	result, err := GetPubKeysFromPeerIDs(input, DefaultBech32Prefix)
 	if err != nil {
 		t.Error(err)
 		t.FailNow()
@@ -29,7 +29,7 @@ func TestGetPubKeysFromPeerIDs(t *testing.T) {
 	assert.Equal(t, "thorpub1addwnpepqtctt9l4fddeh0krvdpxmqsxa5z9xsa0ac6frqfhm9fq6c6u5lck5s8fm4n", result[0])
 	assert.Equal(t, "thorpub1addwnpepqga5cupfejfhtw507sh36fvwaekyjt5kwaw0cmgnpku0at2a87qqkp60t43", result[1])
 	input1 := append(input, "whatever")
-	result, err = GetPubKeysFromPeerIDs(input1)
+	result, err = GetPubKeysFromPeerIDs(input1, DefaultBech32Prefix)
 	assert.NotNil(t, err)
 	assert.Nil(t, result)
 }
@@ -49,6 +49,11 @@ func (*KeyProviderTestSuite) TestGetPriKey(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(result, NotNil)
 	c.Assert(result, HasLen, 32)
+	priKey, err := GetPriKeyFromRawBytes(result)
+	c.Assert(err, IsNil)
+	c.Assert(priKey.Equals(pk), Equals, true)
+	_, err = GetPriKeyFromRawBytes(result[:16])
+	c.Assert(err, NotNil)
 }
 
 func (KeyProviderTestSuite) TestGetPeerIDs(c *C) {
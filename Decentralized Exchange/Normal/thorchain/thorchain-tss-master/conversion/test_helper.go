@@ -3,7 +3,6 @@ package conversion
 import (
 	"math/rand"
 
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	atypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
@@ -12,7 +11,7 @@ import (
 // GetRandomPubKey for test
 func GetRandomPubKey() string {
 	_, pubKey, _ := atypes.KeyTestPubAddr()
-	bech32PubKey, _ := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, pubKey)
+	bech32PubKey, _ := Bech32ifyPubKey(DefaultBech32Prefix, pubKey)
 	return bech32PubKey
 }
 
@@ -0,0 +1,169 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+const (
+	SessionCreated     = "session_created"
+	PeerJoined         = "peer_joined"   // the peer answers the join party request
+	PartyFormed        = "party_formed"  // all the peers join the party
+	PartyTimeout       = "party_timeout" // the party isn't formed in time
+	RoundProduced      = "round_produced"
+	RoundConfirmed     = "round_confirmed" // the hash of the broadcast message of the peer is confirmed by the others
+	ShareRequested     = "share_requested"
+	ShareReceived      = "share_received"
+	SignatureBroadcast = "signature_broadcast" // the signature is sent to the nodes out of the keysign party
+	SessionFinished    = "session_finished"
+)
+
+// Types is all the types of the events
+var Types = []string{
+	SessionCreated,
+	PeerJoined,
+	PartyFormed,
+	PartyTimeout,
+	RoundProduced,
+	RoundConfirmed,
+	ShareRequested,
+	ShareReceived,
+	SignatureBroadcast,
+	SessionFinished,
+}
+
+// IsKnownType return true when the given type is one of the types of the events
+func IsKnownType(eventType string) bool {
+	for _, el := range Types {
+		if el == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriberBufferSize is how many events are kept for the slow subscriber, the newer ones are dropped once it is
+// full, so the tss sessions are never held up by the subscribers
+const subscriberBufferSize = 256
+
+// Event is what happens in a tss session, the peer is the p2p ID of the peer the event is about
+type Event struct {
+	ID        uint64       `json:"id"`
+	Time      time.Time    `json:"time"`
+	SessionID string       `json:"session_id"`
+	Type      string       `json:"type"`
+	Operation string       `json:"operation,omitempty"` // keygen, keysign or reshare, only set for the session events
+	Round     string       `json:"round,omitempty"`
+	Peer      string       `json:"peer,omitempty"`
+	Status    string       `json:"status,omitempty"` // only set once the session is finished
+	Blame     *blame.Blame `json:"blame,omitempty"`  // only set when the session is finished with blame
+	Error     string       `json:"error,omitempty"`
+}
+
+// Filter tell which events the subscriber wants, the empty fields match all the events
+type Filter struct {
+	SessionID string
+	Types     []string
+}
+
+// Match return true when the given event passes the filter
+func (f Filter) Match(e Event) bool {
+	if len(f.SessionID) != 0 && f.SessionID != e.SessionID {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, el := range f.Types {
+		if el == e.Type {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription receives the events that pass its filter from C till it is closed
+type Subscription struct {
+	C      <-chan Event
+	ch     chan Event
+	filter Filter
+	bus    *Bus
+}
+
+// Close stop the subscription, C is closed afterwards
+func (s *Subscription) Close() {
+	s.bus.unsubscribe(s)
+}
+
+// Bus hands the published events to the subscribers
+type Bus struct {
+	logger      zerolog.Logger
+	lock        *sync.Mutex
+	seq         uint64
+	subscribers map[*Subscription]bool
+}
+
+// NewBus create a new instance of Bus
+func NewBus() *Bus {
+	return &Bus{
+		logger:      log.With().Str("module", "events").Logger(),
+		lock:        &sync.Mutex{},
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Publish hand the given event to the subscribers, the ID and the time of the event are set here. The nil bus drops
+// the event, so the components used without a server don't need one
+func (b *Bus) Publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if len(b.subscribers) == 0 {
+		return
+	}
+	b.seq++
+	e.ID = b.seq
+	e.Time = time.Now()
+	for s := range b.subscribers {
+		if !s.filter.Match(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			b.logger.Warn().Str("type", e.Type).Str("session", e.SessionID).Msg("subscriber is full, drop the event")
+		}
+	}
+}
+
+// Subscribe return a new subscription of the events that pass the given filter
+func (b *Bus) Subscribe(filter Filter) *Subscription {
+	ch := make(chan Event, subscriberBufferSize)
+	s := &Subscription{
+		C:      ch,
+		ch:     ch,
+		filter: filter,
+		bus:    b,
+	}
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	b.subscribers[s] = true
+	return s
+}
+
+func (b *Bus) unsubscribe(s *Subscription) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if !b.subscribers[s] {
+		return
+	}
+	delete(b.subscribers, s)
+	close(s.ch)
+}
@@ -0,0 +1,57 @@
+package events
+
+import (
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type EventsTestSuite struct{}
+
+var _ = Suite(&EventsTestSuite{})
+
+func (s *EventsTestSuite) TestFilter(c *C) {
+	e := Event{SessionID: "session1", Type: PartyFormed}
+	c.Assert(Filter{}.Match(e), Equals, true)
+	c.Assert(Filter{SessionID: "session1"}.Match(e), Equals, true)
+	c.Assert(Filter{SessionID: "session2"}.Match(e), Equals, false)
+	c.Assert(Filter{Types: []string{PartyTimeout, PartyFormed}}.Match(e), Equals, true)
+	c.Assert(Filter{SessionID: "session1", Types: []string{PartyTimeout}}.Match(e), Equals, false)
+	c.Assert(IsKnownType(PartyFormed), Equals, true)
+	c.Assert(IsKnownType("whatever"), Equals, false)
+}
+
+func (s *EventsTestSuite) TestBus(c *C) {
+	bus := NewBus()
+	// nobody listens, the event is dropped
+	bus.Publish(Event{SessionID: "session1", Type: SessionCreated})
+
+	all := bus.Subscribe(Filter{})
+	session1 := bus.Subscribe(Filter{SessionID: "session1", Types: []string{SessionFinished}})
+	bus.Publish(Event{SessionID: "session1", Type: SessionCreated})
+	bus.Publish(Event{SessionID: "session2", Type: SessionFinished})
+	bus.Publish(Event{SessionID: "session1", Type: SessionFinished})
+
+	c.Assert(all.C, HasLen, 3)
+	e := <-session1.C
+	c.Assert(e.ID, Equals, uint64(3))
+	c.Assert(e.SessionID, Equals, "session1")
+	c.Assert(e.Time.IsZero(), Equals, false)
+	c.Assert(session1.C, HasLen, 0)
+
+	// the slow subscriber doesn't hold up the others
+	for i := 0; i < subscriberBufferSize; i++ {
+		bus.Publish(Event{SessionID: "session1", Type: SessionFinished})
+	}
+	c.Assert(all.C, HasLen, subscriberBufferSize)
+	c.Assert(session1.C, HasLen, subscriberBufferSize)
+
+	all.Close()
+	// close it twice is fine
+	all.Close()
+	session1.Close()
+	bus.Publish(Event{SessionID: "session1", Type: SessionFinished})
+	c.Assert(bus.subscribers, HasLen, 0)
+}
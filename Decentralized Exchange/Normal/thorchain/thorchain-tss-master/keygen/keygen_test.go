@@ -207,6 +207,69 @@ func (s *TssKeygenTestSuite) TestGenerateNewKey(c *C) {
 	}
 }
 
+func (s *TssKeygenTestSuite) TestGenerateNewKeyWithThreshold(c *C) {
+	sort.Strings(testPubKeys)
+	req := NewRequest(testPubKeys)
+	// all the four nodes are needed to sign
+	req.Threshold = 3
+	messageID, err := common.MsgToHashString([]byte(strings.Join(req.Keys, "") + "3"))
+	c.Assert(err, IsNil)
+	conf := common.TssConfig{
+		KeyGenTimeout:   60 * time.Second,
+		KeySignTimeout:  60 * time.Second,
+		PreParamTimeout: 5 * time.Second,
+	}
+	wg := sync.WaitGroup{}
+	lock := &sync.Mutex{}
+	keygenResult := make(map[int]*crypto.ECPoint)
+	for i := 0; i < s.partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			comm := s.comms[idx]
+			stopChan := make(chan struct{})
+			localPubKey := testPubKeys[idx]
+			keygenInstance := NewTssKeyGen(
+				comm.GetLocalPeerID(),
+				conf,
+				localPubKey,
+				comm.BroadcastMsgChan,
+				stopChan,
+				s.preParams[idx],
+				messageID,
+				s.stateMgrs[idx], s.nodePrivKeys[idx], s.comms[idx])
+			c.Assert(keygenInstance, NotNil)
+			keygenMsgChannel := keygenInstance.GetTssKeyGenChannels()
+			comm.SetSubscribe(messages.TSSKeyGenMsg, messageID, keygenMsgChannel)
+			comm.SetSubscribe(messages.TSSKeyGenVerMsg, messageID, keygenMsgChannel)
+			comm.SetSubscribe(messages.TSSControlMsg, messageID, keygenMsgChannel)
+			comm.SetSubscribe(messages.TSSTaskDone, messageID, keygenMsgChannel)
+			defer comm.CancelSubscribe(messages.TSSKeyGenMsg, messageID)
+			defer comm.CancelSubscribe(messages.TSSKeyGenVerMsg, messageID)
+			defer comm.CancelSubscribe(messages.TSSControlMsg, messageID)
+			defer comm.CancelSubscribe(messages.TSSTaskDone, messageID)
+			resp, err := keygenInstance.GenerateNewKey(req)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keygenResult[idx] = resp
+		}(i)
+	}
+	wg.Wait()
+	ans := keygenResult[0]
+	for _, el := range keygenResult {
+		c.Assert(el.Equals(ans), Equals, true)
+	}
+	poolPubKey, _, err := conversion.GetTssPubKey(ans, conversion.DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	for i := 0; i < s.partyNum; i++ {
+		localState, err := s.stateMgrs[i].GetLocalState(poolPubKey)
+		c.Assert(err, IsNil)
+		c.Assert(localState.Threshold, Equals, 3)
+		c.Assert(localState.LocalData.Ks, HasLen, 4)
+	}
+}
+
 func (s *TssKeygenTestSuite) TestGenerateNewKeyWithStop(c *C) {
 	sort.Strings(testPubKeys)
 	req := NewRequest(testPubKeys)
@@ -277,6 +340,22 @@ func (s *TssKeygenTestSuite) TestKeyGenWithError(c *C) {
 	c.Assert(generatedKey, IsNil)
 }
 
+func (s *TssKeygenTestSuite) TestKeyGenWithInvalidThreshold(c *C) {
+	sort.Strings(testPubKeys)
+	conf := common.TssConfig{}
+	stateManager := &storage.MockLocalStateManager{}
+	keyGenInstance := NewTssKeyGen("", conf, testPubKeys[0], nil, nil, s.preParams[0], "test", stateManager, s.nodePrivKeys[0], nil)
+	for _, threshold := range []int{-1, 4, 5} {
+		req := Request{
+			Keys:      testPubKeys[:],
+			Threshold: threshold,
+		}
+		generatedKey, err := keyGenInstance.GenerateNewKey(req)
+		c.Assert(err, NotNil)
+		c.Assert(generatedKey, IsNil)
+	}
+}
+
 func (s *TssKeygenTestSuite) TestCloseKeyGennotifyChannel(c *C) {
 	conf := common.TssConfig{}
 	stateManager := &storage.MockLocalStateManager{}
@@ -1,8 +1,21 @@
 package keygen
 
+import "gitlab.com/thorchain/tss/go-tss/common"
+
 // Request request to do keygen
 type Request struct {
 	Keys []string `json:"keys"`
+	// Threshold is optional, the keysign of the generated key needs Threshold+1 signers, when it is not given,
+	// the default ceil(2n/3)-1 is used
+	Threshold int `json:"threshold,omitempty"`
+	// Algo is optional, it is the signature scheme of the generated key, ECDSA is used when it is not given
+	Algo common.Algo `json:"algo,omitempty"`
+	// BlockHeight is optional, it tells the sessions of the same request apart, like the retry of a failed keygen, all
+	// the parties have to use the same one
+	BlockHeight int64 `json:"block_height,omitempty"`
+	// CosmosPrefix is optional, it is the bech32 prefix of the Cosmos address in the response, cosmos is used when
+	// it is not given
+	CosmosPrefix string `json:"cosmos_prefix,omitempty"`
 }
 
 // NewRequest creeate a new instance of keygen.Request
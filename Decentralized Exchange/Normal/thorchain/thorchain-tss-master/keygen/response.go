@@ -3,6 +3,7 @@ package keygen
 import (
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
 )
 
 // Response keygen response
@@ -11,6 +12,10 @@ type Response struct {
 	PoolAddress string        `json:"pool_address"`
 	Status      common.Status `json:"status"`
 	Blame       blame.Blame   `json:"blame"`
+	// PubKeyInfo is the pool key in the encodings of the other chains, it is only set once the keygen succeeds
+	PubKeyInfo *conversion.PubKeyInfo `json:"pub_key_info,omitempty"`
+	// Timeline tell where the time of the session goes, it is only set once the session starts
+	Timeline *blame.TimelineSummary `json:"timeline,omitempty"`
 }
 
 // NewResponse create a new instance of keygen.Response
@@ -1,6 +1,7 @@
 package keygen
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sync"
@@ -8,6 +9,7 @@ import (
 
 	bcrypto "github.com/binance-chain/tss-lib/crypto"
 	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	ekg "github.com/binance-chain/tss-lib/eddsa/keygen"
 	btss "github.com/binance-chain/tss-lib/tss"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -17,6 +19,7 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 	"gitlab.com/thorchain/tss/go-tss/storage"
 )
@@ -67,6 +70,15 @@ func (tKeyGen *TssKeyGen) GetTssCommonStruct() *common.TssCommon {
 }
 
 func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, error) {
+	return tKeyGen.GenerateNewKeyWithContext(context.Background(), keygenReq)
+}
+
+// GenerateNewKeyWithContext run the keygen, the keygen is aborted once the given context is done
+func (tKeyGen *TssKeyGen) GenerateNewKeyWithContext(ctx context.Context, keygenReq Request) (*bcrypto.ECPoint, error) {
+	algo, err := common.GetAlgo(string(keygenReq.Algo))
+	if err != nil {
+		return nil, err
+	}
 	partiesID, localPartyID, err := conversion.GetParties(keygenReq.Keys, tKeyGen.localNodePubKey)
 	if err != nil {
 		return nil, fmt.Errorf("fail to get keygen parties: %w", err)
@@ -76,22 +88,46 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 		ParticipantKeys: keygenReq.Keys,
 		LocalPartyKey:   tKeyGen.localNodePubKey,
 	}
+	if algo != common.ECDSA {
+		keyGenLocalStateItem.Algo = algo
+	}
 
 	threshold, err := common.GetThreshold(len(partiesID))
 	if err != nil {
 		return nil, err
 	}
-	ctx := btss.NewPeerContext(partiesID)
-	params := btss.NewParameters(ctx, localPartyID, len(partiesID), threshold)
+	if keygenReq.Threshold != 0 {
+		if err := common.ValidateThreshold(keygenReq.Threshold, len(partiesID)); err != nil {
+			return nil, err
+		}
+		threshold = keygenReq.Threshold
+		keyGenLocalStateItem.Threshold = threshold
+	}
+	peerCtx := btss.NewPeerContext(partiesID)
+	params := btss.NewParameters(peerCtx, localPartyID, len(partiesID), threshold)
 	outCh := make(chan btss.Message, len(partiesID))
-	endCh := make(chan bkg.LocalPartySaveData, len(partiesID))
 	errChan := make(chan struct{})
-	if tKeyGen.preParams == nil {
-		tKeyGen.logger.Error().Err(err).Msg("error, empty pre-parameters")
-		return nil, errors.New("error, empty pre-parameters")
+	var endCh chan bkg.LocalPartySaveData
+	var eddsaEndCh chan ekg.LocalPartySaveData
+	var keyGenParty btss.Party
+	if algo == common.EdDSA {
+		// EdDSA keygen doesn't need the pre-parameters
+		eddsaEndCh = make(chan ekg.LocalPartySaveData, len(partiesID))
+		keyGenParty = ekg.NewLocalParty(params, outCh, eddsaEndCh)
+	} else {
+		if tKeyGen.preParams == nil {
+			tKeyGen.logger.Error().Err(err).Msg("error, empty pre-parameters")
+			return nil, errors.New("error, empty pre-parameters")
+		}
+		endCh = make(chan bkg.LocalPartySaveData, len(partiesID))
+		keyGenParty = bkg.NewLocalParty(params, outCh, endCh, *tKeyGen.preParams)
 	}
+	_, releaseCurve, err := common.AcquireCurve(ctx, algo)
+	if err != nil {
+		return nil, fmt.Errorf("fail to set the curve of %s: %w", algo, err)
+	}
+	defer releaseCurve()
 	blameMgr := tKeyGen.tssCommonStruct.GetBlameMgr()
-	keyGenParty := bkg.NewLocalParty(params, outCh, endCh, *tKeyGen.preParams)
 	partyIDMap := conversion.SetupPartyIDMap(partiesID)
 	err1 := conversion.SetupIDMaps(partyIDMap, tKeyGen.tssCommonStruct.PartyIDtoP2PID)
 	err2 := conversion.SetupIDMaps(partyIDMap, blameMgr.PartyIDtoP2PID)
@@ -121,7 +157,7 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 	}()
 	go tKeyGen.tssCommonStruct.ProcessInboundMessages(tKeyGen.commStopChan, &keyGenWg)
 
-	r, err := tKeyGen.processKeyGen(errChan, outCh, endCh, keyGenLocalStateItem)
+	r, err := tKeyGen.processKeyGen(ctx, errChan, outCh, endCh, eddsaEndCh, keyGenLocalStateItem)
 	if err != nil {
 		close(tKeyGen.commStopChan)
 		return nil, fmt.Errorf("fail to process key sign: %w", err)
@@ -138,10 +174,21 @@ func (tKeyGen *TssKeyGen) GenerateNewKey(keygenReq Request) (*bcrypto.ECPoint, e
 	return r, err
 }
 
-func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
+func (tKeyGen *TssKeyGen) processKeyGen(ctx context.Context,
+	errChan chan struct{},
 	outCh <-chan btss.Message,
 	endCh <-chan bkg.LocalPartySaveData,
-	keyGenLocalStateItem storage.KeygenLocalState) (*bcrypto.ECPoint, error) {
+	eddsaEndCh <-chan ekg.LocalPartySaveData,
+	keyGenLocalStateItem storage.KeygenLocalState) (_ *bcrypto.ECPoint, err error) {
+	timer := monitor.NewSessionTimer(monitor.OpKeygen)
+	defer func() {
+		timer.Done(monitor.ResultOf(err))
+	}()
+	msgType, _ := GetMsgTypes(keyGenLocalStateItem.GetAlgo())
+	unicastMsgType := conversion.GetKeyGenUicast()
+	if keyGenLocalStateItem.GetAlgo() == common.EdDSA {
+		unicastMsgType = conversion.GetEdDSAKeyGenUnicast()
+	}
 	defer tKeyGen.logger.Info().Msg("finished keygen process")
 	tKeyGen.logger.Info().Msg("start to read messages from local party")
 	tssConf := tKeyGen.tssCommonStruct.GetConf()
@@ -155,6 +202,10 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 		case <-tKeyGen.stopChan: // when TSS processor receive signal to quit
 			return nil, errors.New("received exit signal")
 
+		case <-ctx.Done(): // when the caller cancel this keygen
+			tKeyGen.logger.Info().Msg("keygen is cancelled")
+			return nil, ctx.Err()
+
 		case <-time.After(tssConf.KeyGenTimeout):
 			// we bail out after KeyGenTimeoutSeconds
 			tKeyGen.logger.Error().Msgf("fail to generate message with %s", tssConf.KeyGenTimeout.String())
@@ -163,7 +214,6 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 				tKeyGen.logger.Error().Msg("fail to start the keygen, the last produced message of this node is none")
 				return nil, errors.New("timeout before shared message is generated")
 			}
-			unicastMsgType := conversion.GetKeyGenUicast()
 			blameNodesUnicast, err := blameMgr.GetUnicastBlame(unicastMsgType)
 			if err != nil {
 				tKeyGen.logger.Error().Err(err).Msg("error in get unicast blame")
@@ -183,8 +233,9 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 
 		case msg := <-outCh:
 			tKeyGen.logger.Debug().Msgf(">>>>>>>>>>msg: %s", msg.String())
+			timer.Round(msg.Type())
 			blameMgr.SetLastMsg(msg)
-			err := tKeyGen.tssCommonStruct.ProcessOutCh(msg, messages.TSSKeyGenMsg)
+			err := tKeyGen.tssCommonStruct.ProcessOutCh(msg, msgType)
 			if err != nil {
 				tKeyGen.logger.Error().Err(err).Msg("fail to process the message")
 				return nil, err
@@ -192,24 +243,53 @@ func (tKeyGen *TssKeyGen) processKeyGen(errChan chan struct{},
 
 		case msg := <-endCh:
 			tKeyGen.logger.Debug().Msgf("keygen finished successfully: %s", msg.ECDSAPub.Y().String())
-			err := tKeyGen.tssCommonStruct.NotifyTaskDone()
-			if err != nil {
-				tKeyGen.logger.Error().Err(err).Msg("fail to broadcast the keysign done")
-			}
-			pubKey, _, err := conversion.GetTssPubKey(msg.ECDSAPub)
+			pubKey, _, err := conversion.GetTssPubKey(msg.ECDSAPub, tKeyGen.tssCommonStruct.GetConf().Bech32Prefix)
 			if err != nil {
 				return nil, fmt.Errorf("fail to get thorchain pubkey: %w", err)
 			}
 			keyGenLocalStateItem.LocalData = msg
 			keyGenLocalStateItem.PubKey = pubKey
-			if err := tKeyGen.stateManager.SaveLocalState(keyGenLocalStateItem); err != nil {
-				return nil, fmt.Errorf("fail to save keygen result to storage: %w", err)
-			}
-			address := tKeyGen.p2pComm.ExportPeerAddress()
-			if err := tKeyGen.stateManager.SaveAddressBook(address); err != nil {
-				tKeyGen.logger.Error().Err(err).Msg("fail to save the peer addresses")
+			if err := tKeyGen.saveKeyGenResult(keyGenLocalStateItem); err != nil {
+				return nil, err
 			}
 			return msg.ECDSAPub, nil
+
+		case msg := <-eddsaEndCh:
+			tKeyGen.logger.Debug().Msgf("EdDSA keygen finished successfully: %s", msg.EDDSAPub.Y().String())
+			pubKey, _, err := conversion.GetEdDSATssPubKey(msg.EDDSAPub, tKeyGen.tssCommonStruct.GetConf().Bech32Prefix)
+			if err != nil {
+				return nil, fmt.Errorf("fail to get thorchain pubkey: %w", err)
+			}
+			keyGenLocalStateItem.EdDSALocalData = &msg
+			keyGenLocalStateItem.PubKey = pubKey
+			if err := tKeyGen.saveKeyGenResult(keyGenLocalStateItem); err != nil {
+				return nil, err
+			}
+			return msg.EDDSAPub, nil
 		}
 	}
 }
+
+// saveKeyGenResult tell the other parties we are done, and save the key share and the peer addresses
+func (tKeyGen *TssKeyGen) saveKeyGenResult(keyGenLocalStateItem storage.KeygenLocalState) error {
+	err := tKeyGen.tssCommonStruct.NotifyTaskDone()
+	if err != nil {
+		tKeyGen.logger.Error().Err(err).Msg("fail to broadcast the keygen done")
+	}
+	if err := tKeyGen.stateManager.SaveLocalState(keyGenLocalStateItem); err != nil {
+		return fmt.Errorf("fail to save keygen result to storage: %w", err)
+	}
+	address := tKeyGen.p2pComm.ExportPeerAddress()
+	if err := tKeyGen.stateManager.SaveAddressBook(address); err != nil {
+		tKeyGen.logger.Error().Err(err).Msg("fail to save the peer addresses")
+	}
+	return nil
+}
+
+// GetMsgTypes return the message types used by the keygen of the given algorithm
+func GetMsgTypes(algo common.Algo) (messages.THORChainTSSMessageType, messages.THORChainTSSMessageType) {
+	if algo == common.EdDSA {
+		return messages.TSSEdDSAKeyGenMsg, messages.TSSEdDSAKeyGenVerMsg
+	}
+	return messages.TSSKeyGenMsg, messages.TSSKeyGenVerMsg
+}
@@ -0,0 +1,49 @@
+package keysign
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// DeriveLocalState return the local state of the child key, which is the pool key plus tweak*G. Every party adds the
+// same tweak to the secret share, and tweak*G to the public shares, the shares then sum up to the child key. The given
+// state is left untouched
+func DeriveLocalState(state storage.KeygenLocalState, tweak *big.Int) (storage.KeygenLocalState, error) {
+	if state.GetAlgo() != common.ECDSA {
+		return storage.KeygenLocalState{}, errors.New("only the ECDSA key can be derived")
+	}
+	if state.LocalData.Xi == nil || state.LocalData.ECDSAPub == nil {
+		return storage.KeygenLocalState{}, errors.New("invalid local state, no key share")
+	}
+	if tweak.Sign() == 0 {
+		return state, nil
+	}
+	curve := btcec.S256()
+	tweakPoint := crypto.ScalarBaseMult(curve, tweak)
+	derived := state
+	derived.LocalData.Xi = new(big.Int).Mod(new(big.Int).Add(state.LocalData.Xi, tweak), curve.N)
+	derived.LocalData.BigXj = make([]*crypto.ECPoint, len(state.LocalData.BigXj))
+	for i, el := range state.LocalData.BigXj {
+		if el == nil {
+			return storage.KeygenLocalState{}, fmt.Errorf("invalid local state, the public share(%d) is missing", i)
+		}
+		p, err := el.Add(tweakPoint)
+		if err != nil {
+			return storage.KeygenLocalState{}, fmt.Errorf("fail to tweak the public share(%d): %w", i, err)
+		}
+		derived.LocalData.BigXj[i] = p
+	}
+	pub, err := state.LocalData.ECDSAPub.Add(tweakPoint)
+	if err != nil {
+		return storage.KeygenLocalState{}, fmt.Errorf("fail to tweak the pool key: %w", err)
+	}
+	derived.LocalData.ECDSAPub = pub
+	return derived, nil
+}
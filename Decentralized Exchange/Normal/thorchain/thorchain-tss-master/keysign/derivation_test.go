@@ -0,0 +1,60 @@
+package keysign
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type DerivationTestSuite struct{}
+
+var _ = Suite(&DerivationTestSuite{})
+
+func (*DerivationTestSuite) TestDeriveLocalState(c *C) {
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var state storage.KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &state), IsNil)
+	xi := new(big.Int).Set(state.LocalData.Xi)
+	pub := state.LocalData.ECDSAPub
+	bigXj := append([]*crypto.ECPoint{}, state.LocalData.BigXj...)
+
+	tweak := big.NewInt(12345)
+	tweakPoint := crypto.ScalarBaseMult(btcec.S256(), tweak)
+	derived, err := DeriveLocalState(state, tweak)
+	c.Assert(err, IsNil)
+	expectedPub, err := pub.Add(tweakPoint)
+	c.Assert(err, IsNil)
+	c.Assert(derived.LocalData.ECDSAPub.Equals(expectedPub), Equals, true)
+	c.Assert(derived.LocalData.Xi.Cmp(new(big.Int).Add(xi, tweak)), Equals, 0)
+	c.Assert(derived.LocalData.BigXj, HasLen, len(bigXj))
+	for i, el := range bigXj {
+		expected, err := el.Add(tweakPoint)
+		c.Assert(err, IsNil)
+		c.Assert(derived.LocalData.BigXj[i].Equals(expected), Equals, true)
+	}
+	// the local state of the pool is left untouched
+	c.Assert(state.LocalData.Xi.Cmp(xi), Equals, 0)
+	c.Assert(state.LocalData.ECDSAPub.Equals(pub), Equals, true)
+	for i, el := range bigXj {
+		c.Assert(state.LocalData.BigXj[i].Equals(el), Equals, true)
+	}
+
+	// the zero tweak is the pool key itself
+	derived, err = DeriveLocalState(state, big.NewInt(0))
+	c.Assert(err, IsNil)
+	c.Assert(derived.LocalData.ECDSAPub.Equals(pub), Equals, true)
+
+	_, err = DeriveLocalState(storage.KeygenLocalState{}, tweak)
+	c.Assert(err, NotNil)
+	state.Algo = common.EdDSA
+	_, err = DeriveLocalState(state, tweak)
+	c.Assert(err, NotNil)
+}
@@ -84,6 +84,18 @@ func (m *MockLocalStateManager) GetLocalState(pubKey string) (storage.KeygenLoca
 	return state, nil
 }
 
+func (m *MockLocalStateManager) ListLocalStates() ([]storage.LocalStateInfo, error) {
+	return nil, nil
+}
+
+func (m *MockLocalStateManager) GetLocalStateInfo(pubKey string) (storage.LocalStateInfo, error) {
+	return storage.LocalStateInfo{}, os.ErrNotExist
+}
+
+func (m *MockLocalStateManager) ArchiveLocalState(pubKey string, buf []byte) error {
+	return nil
+}
+
 func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	return nil
 }
@@ -92,6 +104,14 @@ func (s *MockLocalStateManager) RetrieveP2PAddresses() (addr.AddrList, error) {
 	return nil, os.ErrNotExist
 }
 
+func (s *MockLocalStateManager) SavePreParamPool(buf []byte) error {
+	return nil
+}
+
+func (s *MockLocalStateManager) RetrievePreParamPool() ([]byte, error) {
+	return nil, os.ErrNotExist
+}
+
 type TssKeysisgnTestSuite struct {
 	comms        []*p2p.Communication
 	partyNum     int
@@ -7,9 +7,11 @@ import (
 	"math/big"
 
 	bc "github.com/binance-chain/tss-lib/common"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/tendermint/btcd/btcec"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
 )
 
 // Notifier
@@ -45,16 +47,47 @@ func NewNotifier(messageID string, message []byte, poolPubKey string) (*Notifier
 // go-tss respect the payload it receives , assume the payload had been hashed already by whoever send it in.
 func (n *Notifier) verifySignature(data *bc.SignatureData) (bool, error) {
 	// we should be able to use any of the pubkeys to verify the signature
-	pubKey, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, n.poolPubKey)
+	pubKey, err := conversion.ParsePubKey(n.poolPubKey)
 	if err != nil {
-		return false, fmt.Errorf("fail to get pubkey from bech32 pubkey string(%s):%w", n.poolPubKey, err)
+		return false, fmt.Errorf("fail to parse the pool pubkey(%s):%w", n.poolPubKey, err)
+	}
+	switch pk := pubKey.(type) {
+	case secp256k1.PubKeySecp256k1:
+		pub, err := btcec.ParsePubKey(pk[:], btcec.S256())
+		if err != nil {
+			return false, err
+		}
+		if !ecdsa.Verify(pub.ToECDSA(), n.message, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)) {
+			return false, nil
+		}
+		// the recovery id is handed to the callers as it is, so it must be there and recover the pool key as well
+		return verifyRecoveryID(pub, n.message, data), nil
+	case ed25519.PubKeyEd25519:
+		// EdDSA signs the message itself, R and S are in the Ed25519 encoding
+		sig := make([]byte, 0, len(data.R)+len(data.S))
+		sig = append(sig, data.R...)
+		sig = append(sig, data.S...)
+		return pk.VerifyBytes(n.message, sig), nil
+	default:
+		return false, fmt.Errorf("unsupported pool pub key type(%T)", pubKey)
+	}
+}
+
+// verifyRecoveryID check whether the public key recovered from the signature with the recovery id is the given one
+func verifyRecoveryID(pub *btcec.PublicKey, message []byte, data *bc.SignatureData) bool {
+	if len(data.SignatureRecovery) != 1 || data.SignatureRecovery[0] > 3 || len(data.R) > 32 || len(data.S) > 32 {
+		return false
 	}
-	pk := pubKey.(secp256k1.PubKeySecp256k1)
-	pub, err := btcec.ParsePubKey(pk[:], btcec.S256())
+	// the compact signature is the recovery flag followed by R and S, the flag of the uncompressed key is 27 + id
+	sig := make([]byte, 65)
+	sig[0] = 27 + data.SignatureRecovery[0]
+	copy(sig[33-len(data.R):33], data.R)
+	copy(sig[65-len(data.S):], data.S)
+	recovered, _, err := btcec.RecoverCompact(btcec.S256(), sig, message)
 	if err != nil {
-		return false, err
+		return false
 	}
-	return ecdsa.Verify(pub.ToECDSA(), n.message, new(big.Int).SetBytes(data.R), new(big.Int).SetBytes(data.S)), nil
+	return recovered.IsEqual(pub)
 }
 
 // ProcessSignature is to verify whether the signature is valid
@@ -6,6 +6,8 @@ import (
 	"io/ioutil"
 
 	bc "github.com/binance-chain/tss-lib/common"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	. "gopkg.in/check.v1"
 
 	"gitlab.com/thorchain/tss/go-tss/common"
@@ -40,6 +42,26 @@ func (NotifierTestSuite) TestNewNotifier(c *C) {
 	c.Assert(ch, NotNil)
 }
 
+func (NotifierTestSuite) TestNotifierEdDSA(c *C) {
+	sk := ed25519.GenPrivKey()
+	poolPubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, sk.PubKey())
+	c.Assert(err, IsNil)
+	msg := []byte("hello")
+	sig, err := sk.Sign(msg)
+	c.Assert(err, IsNil)
+	n, err := NewNotifier("hello", msg, poolPubKey)
+	c.Assert(err, IsNil)
+	// the signature of another message
+	invalidSig, err := sk.Sign([]byte("world"))
+	c.Assert(err, IsNil)
+	finish, err := n.ProcessSignature(&bc.SignatureData{R: invalidSig[:32], S: invalidSig[32:]})
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, false)
+	finish, err = n.ProcessSignature(&bc.SignatureData{R: sig[:32], S: sig[32:]})
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, true)
+}
+
 func (NotifierTestSuite) TestNotifierHappyPath(c *C) {
 	messageToSign := "yhEwrxWuNBGnPT/L7PNnVWg7gFWNzCYTV+GuX3tKRH8="
 	buf, err := base64.StdEncoding.DecodeString(messageToSign)
@@ -68,6 +90,18 @@ func (NotifierTestSuite) TestNotifierHappyPath(c *C) {
 	finish, err := n.ProcessSignature(&sigInvalid)
 	c.Assert(err, IsNil)
 	c.Assert(finish, Equals, false)
+	// valid signature with the wrong recovery id, we should continue to listen
+	sigWrongRecovery := signature
+	sigWrongRecovery.SignatureRecovery = []byte{signature.SignatureRecovery[0] ^ 1}
+	finish, err = n.ProcessSignature(&sigWrongRecovery)
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, false)
+	// valid signature without the recovery id, we should continue to listen
+	sigNoRecovery := signature
+	sigNoRecovery.SignatureRecovery = nil
+	finish, err = n.ProcessSignature(&sigNoRecovery)
+	c.Assert(err, IsNil)
+	c.Assert(finish, Equals, false)
 	// valid signature from a keysign peer , we should accept it and bail out
 	finish, err = n.ProcessSignature(&signature)
 	c.Assert(err, IsNil)
@@ -1,10 +1,24 @@
 package keysign
 
+import "gitlab.com/thorchain/tss/go-tss/common"
+
 // Request request to sign a message
 type Request struct {
 	PoolPubKey    string   `json:"pool_pub_key"` // pub key of the pool that we would like to send this message from
 	Message       string   `json:"message"`      // base64 encoded message to be signed
 	SignerPubKeys []string `json:"signer_pub_keys"`
+	// Algo is optional, the algorithm of the pool key is used when it is not given, otherwise they have to match
+	Algo common.Algo `json:"algo,omitempty"`
+	// DerivationPath is optional, the message is signed with the non-hardened BIP32 child key of the pool, like m/0/1,
+	// the chain code of the pool is the SHA256 of the compressed pool key
+	DerivationPath string `json:"derivation_path,omitempty"`
+	// BlockHeight is optional, it tells the sessions of the same request apart, like the retry of a failed keysign, all
+	// the parties have to use the same one
+	BlockHeight int64 `json:"block_height,omitempty"`
+	// Migration tells the request moves the funds out of the pool, only the migration is signed by the retiring key
+	Migration bool `json:"migration,omitempty"`
+	// Metadata is optional, it tells the local keysign policy what the request is about, like the tx id
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 func NewRequest(pk, msg string, signers []string) Request {
@@ -14,3 +28,32 @@ func NewRequest(pk, msg string, signers []string) Request {
 		SignerPubKeys: signers,
 	}
 }
+
+// DeriveRequest request to derive the child key of the pool
+type DeriveRequest struct {
+	PoolPubKey     string `json:"pool_pub_key"`
+	DerivationPath string `json:"derivation_path"` // the non-hardened BIP32 path, like m/0/1
+}
+
+// BatchRequest request to sign a batch of messages with the same pool and signers in one party session
+type BatchRequest struct {
+	PoolPubKey    string   `json:"pool_pub_key"`    // pub key of the pool that we would like to send these messages from
+	Messages      []string `json:"messages"`        // base64 encoded messages to be signed
+	SignerPubKeys []string `json:"signer_pub_keys"` // the signers of all the messages in this batch
+	// BlockHeight is optional, it tells the sessions of the same request apart, like the retry of a failed keysign, all
+	// the parties have to use the same one
+	BlockHeight int64 `json:"block_height,omitempty"`
+	// Migration tells the requests move the funds out of the pool, only the migration is signed by the retiring key
+	Migration bool `json:"migration,omitempty"`
+	// Metadata is optional, it tells the local keysign policy what the requests are about, like the tx id
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// NewBatchRequest create a new instance of keysign.BatchRequest
+func NewBatchRequest(pk string, msgs []string, signers []string) BatchRequest {
+	return BatchRequest{
+		PoolPubKey:    pk,
+		Messages:      msgs,
+		SignerPubKeys: signers,
+	}
+}
@@ -7,17 +7,36 @@ import (
 
 // Response key sign response
 type Response struct {
-	R      string        `json:"r"`
-	S      string        `json:"s"`
-	Status common.Status `json:"status"`
-	Blame  blame.Blame   `json:"blame"`
+	R          string        `json:"r"`
+	S          string        `json:"s"`
+	RecoveryID *int          `json:"recovery_id,omitempty"` // the ECDSA recovery id, 0 to 3, nil for the EdDSA signature
+	Status     common.Status `json:"status"`
+	Blame      blame.Blame   `json:"blame"`
+	// Timeline tell where the time of the session goes, it is only set once the session starts
+	Timeline *blame.TimelineSummary `json:"timeline,omitempty"`
 }
 
-func NewResponse(r, s string, status common.Status, blame blame.Blame) Response {
+// DeriveResponse the child key of the pool
+type DeriveResponse struct {
+	PubKey  string `json:"pub_key"`
+	Address string `json:"address"`
+}
+
+func NewResponse(r, s string, recoveryID *int, status common.Status, blame blame.Blame) Response {
 	return Response{
-		R:      r,
-		S:      s,
-		Status: status,
-		Blame:  blame,
+		R:          r,
+		S:          s,
+		RecoveryID: recoveryID,
+		Status:     status,
+		Blame:      blame,
+	}
+}
+
+// GetRecoveryID return the recovery id of the given signature, it is nil when the signature has none
+func GetRecoveryID(signatureRecovery []byte) *int {
+	if len(signatureRecovery) == 0 {
+		return nil
 	}
+	recoveryID := int(signatureRecovery[0])
+	return &recoveryID
 }
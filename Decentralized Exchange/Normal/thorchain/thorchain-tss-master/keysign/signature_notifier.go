@@ -16,7 +16,9 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 )
 
@@ -39,18 +41,25 @@ type SignatureNotifier struct {
 	notifiers    map[string]*Notifier
 	messages     chan *signatureItem
 	wg           *sync.WaitGroup
+	pendingLock  *sync.Mutex
+	pending      int             // how many signatures are handed to the notifier but not sent yet
+	flushed      []chan struct{} // closed once no signature is pending
+	eventBus     *events.Bus
 }
 
-// NewSignatureNotifier create a new instance of SignatureNotifier
-func NewSignatureNotifier(host host.Host) *SignatureNotifier {
+// NewSignatureNotifier create a new instance of SignatureNotifier, the broadcast of the signatures is published to
+// the given bus, which can be nil
+func NewSignatureNotifier(host host.Host, eventBus *events.Bus) *SignatureNotifier {
 	s := &SignatureNotifier{
 		logger:       log.With().Str("module", "signature_notifier").Logger(),
 		host:         host,
+		eventBus:     eventBus,
 		notifierLock: &sync.Mutex{},
 		notifiers:    make(map[string]*Notifier),
 		stopChan:     make(chan struct{}),
 		messages:     make(chan *signatureItem),
 		wg:           &sync.WaitGroup{},
+		pendingLock:  &sync.Mutex{},
 	}
 	host.SetStreamHandler(signatureNotifierProtocol, s.handleStream)
 	return s
@@ -126,6 +135,7 @@ func (s *SignatureNotifier) sendMessageToPeer() {
 			if err := s.sendOneMsgToPeer(msg); err != nil {
 				s.logger.Error().Err(err).Msgf("fail to send message(%s) to peer:%s", msg.messageID, msg.peerID)
 			}
+			s.addPending(-1)
 		}
 	}
 }
@@ -173,6 +183,7 @@ func (s *SignatureNotifier) sendOneMsgToPeer(m *signatureItem) error {
 
 // BroadcastSignature sending the keysign signature to all other peers
 func (s *SignatureNotifier) BroadcastSignature(messageID string, sig *bc.SignatureData, peers []peer.ID) error {
+	s.eventBus.Publish(events.Event{SessionID: messageID, Type: events.SignatureBroadcast})
 	return s.broadcastCommon(messageID, sig, peers)
 }
 
@@ -182,6 +193,7 @@ func (s *SignatureNotifier) broadcastCommon(messageID string, sig *bc.SignatureD
 			// don't send the signature to itself
 			continue
 		}
+		s.addPending(1)
 		select {
 		case s.messages <- &signatureItem{
 			messageID:     messageID,
@@ -189,12 +201,46 @@ func (s *SignatureNotifier) broadcastCommon(messageID string, sig *bc.SignatureD
 			signatureData: sig,
 		}:
 		case <-s.stopChan:
+			s.addPending(-1)
 			return nil
 		}
 	}
 	return nil
 }
 
+func (s *SignatureNotifier) addPending(delta int) {
+	s.pendingLock.Lock()
+	defer s.pendingLock.Unlock()
+	s.pending += delta
+	if s.pending != 0 {
+		return
+	}
+	for _, el := range s.flushed {
+		close(el)
+	}
+	s.flushed = nil
+}
+
+// Flush wait till all the signatures handed to the notifier are sent to the peers, or the given context is done
+func (s *SignatureNotifier) Flush(ctx context.Context) error {
+	s.pendingLock.Lock()
+	if s.pending == 0 {
+		s.pendingLock.Unlock()
+		return nil
+	}
+	flushed := make(chan struct{})
+	s.flushed = append(s.flushed, flushed)
+	s.pendingLock.Unlock()
+	select {
+	case <-flushed:
+		return nil
+	case <-s.stopChan:
+		return errors.New("signature notifier is stopped")
+	case <-ctx.Done():
+		return fmt.Errorf("fail to send all the signatures: %w", ctx.Err())
+	}
+}
+
 // BroadcastFailed will send keysign failed message to the nodes that are not in the keysign party
 func (s *SignatureNotifier) BroadcastFailed(messageID string, peers []peer.ID) error {
 	return s.broadcastCommon(messageID, nil, peers)
@@ -213,7 +259,7 @@ func (s *SignatureNotifier) removeNotifier(n *Notifier) {
 }
 
 // WaitForSignature wait until keysign finished and signature is available
-func (s *SignatureNotifier) WaitForSignature(messageID string, message []byte, poolPubKey string, timeout time.Duration) (*bc.SignatureData, error) {
+func (s *SignatureNotifier) WaitForSignature(ctx context.Context, messageID string, message []byte, poolPubKey string, timeout time.Duration) (*bc.SignatureData, error) {
 	n, err := NewNotifier(messageID, message, poolPubKey)
 	if err != nil {
 		return nil, fmt.Errorf("fail to create notifier")
@@ -221,12 +267,18 @@ func (s *SignatureNotifier) WaitForSignature(messageID string, message []byte, p
 	s.addToNotifiers(n)
 	defer s.removeNotifier(n)
 
+	start := time.Now()
 	select {
 	case d := <-n.GetResponseChannel():
+		monitor.ObserveSignatureWait(time.Since(start), monitor.ResultSuccess)
 		return d, nil
 	case <-s.stopChan:
 		return nil, errors.New("request to exit")
+	case <-ctx.Done():
+		monitor.ObserveSignatureWait(time.Since(start), monitor.ResultCancelled)
+		return nil, ctx.Err()
 	case <-time.After(timeout):
+		monitor.ObserveSignatureWait(time.Since(start), monitor.ResultTimeout)
 		return nil, fmt.Errorf("timeout: didn't receive signature after %s", timeout)
 	}
 }
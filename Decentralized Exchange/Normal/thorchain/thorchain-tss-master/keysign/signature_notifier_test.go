@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"sync"
 	"testing"
@@ -58,9 +59,9 @@ func TestSignatureNotifierHappyPath(t *testing.T) {
 	if err := mn.ConnectAllButSelf(); err != nil {
 		t.Error(err)
 	}
-	n1 := NewSignatureNotifier(h1)
-	n2 := NewSignatureNotifier(h2)
-	n3 := NewSignatureNotifier(h3)
+	n1 := NewSignatureNotifier(h1, nil)
+	n2 := NewSignatureNotifier(h2, nil)
+	n3 := NewSignatureNotifier(h3, nil)
 	assert.NotNil(t, n1)
 	assert.NotNil(t, n2)
 	assert.NotNil(t, n3)
@@ -81,7 +82,7 @@ func TestSignatureNotifierHappyPath(t *testing.T) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		sig, err := n1.WaitForSignature(messageID, buf, poolPubKey, time.Second*30)
+		sig, err := n1.WaitForSignature(context.Background(), messageID, buf, poolPubKey, time.Second*30)
 		assert.Nil(t, err)
 		assert.NotNil(t, sig)
 	}()
@@ -93,3 +94,48 @@ func TestSignatureNotifierHappyPath(t *testing.T) {
 	}))
 	wg.Wait()
 }
+
+func TestSignatureNotifierFlush(t *testing.T) {
+	id1 := tnet.RandIdentityOrFatal(t)
+	id2 := tnet.RandIdentityOrFatal(t)
+	mn := mocknet.New(context.Background())
+	h1, err := mn.AddPeer(id1.PrivateKey(), tnet.RandLocalTCPAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := mn.AddPeer(id2.PrivateKey(), tnet.RandLocalTCPAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mn.LinkAll(); err != nil {
+		t.Error(err)
+	}
+	if err := mn.ConnectAllButSelf(); err != nil {
+		t.Error(err)
+	}
+	n1 := NewSignatureNotifier(h1, nil)
+	n2 := NewSignatureNotifier(h2, nil)
+	n2.Start()
+	defer n2.Stop()
+	// nothing to flush
+	assert.Nil(t, n1.Flush(context.Background()))
+
+	// the notifier isn't started yet, the signature stays in the queue
+	go func() {
+		assert.Nil(t, n1.BroadcastFailed("whatever", []peer.ID{h2.ID()}))
+	}()
+	assert.Eventually(t, func() bool {
+		n1.pendingLock.Lock()
+		defer n1.pendingLock.Unlock()
+		return n1.pending == 1
+	}, time.Second, 10*time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.True(t, errors.Is(n1.Flush(ctx), context.DeadlineExceeded))
+
+	n1.Start()
+	defer n1.Stop()
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	assert.Nil(t, n1.Flush(ctx))
+}
@@ -1,15 +1,18 @@
 package keysign
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"sync"
 	"time"
 
 	bc "github.com/binance-chain/tss-lib/common"
 	"github.com/binance-chain/tss-lib/ecdsa/signing"
+	esigning "github.com/binance-chain/tss-lib/eddsa/signing"
 	btss "github.com/binance-chain/tss-lib/tss"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -19,6 +22,7 @@ import (
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
 	"gitlab.com/thorchain/tss/go-tss/storage"
 )
@@ -59,7 +63,21 @@ func (tKeySign *TssKeySign) GetTssCommonStruct() *common.TssCommon {
 
 // signMessage
 func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage.KeygenLocalState, parties []string) (*bc.SignatureData, error) {
-	partiesID, localPartyID, err := conversion.GetParties(parties, localStateItem.LocalPartyKey)
+	return tKeySign.SignMessageWithContext(context.Background(), msgToSign, localStateItem, parties)
+}
+
+// SignMessageWithContext sign the message, the keysign is aborted once the given context is done
+func (tKeySign *TssKeySign) SignMessageWithContext(ctx context.Context, msgToSign []byte, localStateItem storage.KeygenLocalState, parties []string) (*bc.SignatureData, error) {
+	// the party keys have to match the keys the shares were created for, which carry a tag once the key is reshared
+	algo := localStateItem.GetAlgo()
+	var tag int64
+	if shareID := localStateItem.GetShareID(); shareID != nil {
+		tag = conversion.GetPartyKeyTag(shareID)
+	}
+	partiesID, localPartyID, err := conversion.GetCommitteeParties(parties, localStateItem.LocalPartyKey, "", tag)
+	if err == nil && localPartyID == nil {
+		err = errors.New("local party is not in the list")
+	}
 	tKeySign.localParty = localPartyID
 	if err != nil {
 		return nil, fmt.Errorf("fail to form key sign party: %w", err)
@@ -68,23 +86,42 @@ func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage
 		tKeySign.logger.Info().Msgf("we are not in this rounds key sign")
 		return nil, nil
 	}
-	threshold, err := common.GetThreshold(len(localStateItem.ParticipantKeys))
+	threshold, err := localStateItem.GetThreshold()
 	if err != nil {
 		return nil, errors.New("fail to get threshold")
 	}
 
 	tKeySign.logger.Debug().Msgf("local party: %+v", localPartyID)
-	ctx := btss.NewPeerContext(partiesID)
-	params := btss.NewParameters(ctx, localPartyID, len(partiesID), threshold)
+	peerCtx := btss.NewPeerContext(partiesID)
+	params := btss.NewParameters(peerCtx, localPartyID, len(partiesID), threshold)
 	outCh := make(chan btss.Message, len(partiesID))
 	endCh := make(chan bc.SignatureData, len(partiesID))
 	errCh := make(chan struct{})
-	m, err := common.MsgToHashInt(msgToSign)
+	var keySignParty btss.Party
+	if algo == common.EdDSA {
+		if localStateItem.EdDSALocalData == nil {
+			return nil, errors.New("invalid local state, no EdDSA key share")
+		}
+		// EdDSA signs the message itself rather than the hash, tss-lib takes the message as an integer, which would
+		// drop the leading zero bytes
+		if len(msgToSign) == 0 || msgToSign[0] == 0 {
+			return nil, errors.New("the message to sign with EdDSA can't be empty or start with a zero byte")
+		}
+		m := new(big.Int).SetBytes(msgToSign)
+		keySignParty = esigning.NewLocalParty(m, params, *localStateItem.EdDSALocalData, outCh, endCh)
+	} else {
+		m, err := common.MsgToHashInt(msgToSign)
+		if err != nil {
+			return nil, fmt.Errorf("fail to convert msg to hash int: %w", err)
+		}
+		keySignParty = signing.NewLocalParty(m, params, localStateItem.LocalData, outCh, endCh)
+	}
+	_, releaseCurve, err := common.AcquireCurve(ctx, algo)
 	if err != nil {
-		return nil, fmt.Errorf("fail to convert msg to hash int: %w", err)
+		return nil, fmt.Errorf("fail to set the curve of %s: %w", algo, err)
 	}
+	defer releaseCurve()
 	blameMgr := tKeySign.tssCommonStruct.GetBlameMgr()
-	keySignParty := signing.NewLocalParty(m, params, localStateItem.LocalData, outCh, endCh)
 	partyIDMap := conversion.SetupPartyIDMap(partiesID)
 	err1 := conversion.SetupIDMaps(partyIDMap, tKeySign.tssCommonStruct.PartyIDtoP2PID)
 	err2 := conversion.SetupIDMaps(partyIDMap, blameMgr.PartyIDtoP2PID)
@@ -116,7 +153,7 @@ func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage
 		tKeySign.logger.Debug().Msg("local party is ready")
 	}()
 	go tKeySign.tssCommonStruct.ProcessInboundMessages(tKeySign.commStopChan, &keySignWg)
-	result, err := tKeySign.processKeySign(errCh, outCh, endCh)
+	result, err := tKeySign.processKeySign(ctx, algo, errCh, outCh, endCh)
 	if err != nil {
 		close(tKeySign.commStopChan)
 		return nil, fmt.Errorf("fail to process key sign: %w", err)
@@ -134,8 +171,13 @@ func (tKeySign *TssKeySign) SignMessage(msgToSign []byte, localStateItem storage
 	return result, nil
 }
 
-func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan btss.Message, endCh <-chan bc.SignatureData) (*bc.SignatureData, error) {
+func (tKeySign *TssKeySign) processKeySign(ctx context.Context, algo common.Algo, errChan chan struct{}, outCh <-chan btss.Message, endCh <-chan bc.SignatureData) (_ *bc.SignatureData, err error) {
+	timer := monitor.NewSessionTimer(monitor.OpKeySign)
+	defer func() {
+		timer.Done(monitor.ResultOf(err))
+	}()
 	defer tKeySign.logger.Info().Msg("key sign finished")
+	msgType, _ := GetMsgTypes(algo)
 	tKeySign.logger.Info().Msg("start to read messages from local party")
 	tssConf := tKeySign.tssCommonStruct.GetConf()
 	blameMgr := tKeySign.tssCommonStruct.GetBlameMgr()
@@ -146,6 +188,9 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 			return nil, errors.New("error channel closed fail to start local party")
 		case <-tKeySign.stopChan: // when TSS processor receive signal to quit
 			return nil, errors.New("received exit signal")
+		case <-ctx.Done(): // when the caller cancel this keysign
+			tKeySign.logger.Info().Msg("keysign is cancelled")
+			return nil, ctx.Err()
 		case <-time.After(tssConf.KeySignTimeout):
 			// we bail out after KeySignTimeoutSeconds
 			tKeySign.logger.Error().Msgf("fail to sign message with %s", tssConf.KeySignTimeout.String())
@@ -160,8 +205,8 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 				} else {
 					blameMgr.GetBlame().SetBlame(blame.TssTimeout, blameNodesUnicast, false)
 				}
-			} else {
-				blameNodesUnicast, err := blameMgr.GetUnicastBlame(conversion.GetPreviousKeySignUicast(lastMsg.Type()))
+			} else if previousUnicast := conversion.GetPreviousKeySignUicast(lastMsg.Type()); previousUnicast != "" {
+				blameNodesUnicast, err := blameMgr.GetUnicastBlame(previousUnicast)
 				if err != nil {
 					tKeySign.logger.Error().Err(err).Msg("error in get unicast blame")
 				}
@@ -170,6 +215,9 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 				} else {
 					blameMgr.GetBlame().SetBlame(blame.TssTimeout, blameNodesUnicast, false)
 				}
+			} else {
+				// there is no unicast message in EdDSA keysign
+				blameMgr.GetBlame().SetBlame(blame.TssTimeout, nil, false)
 			}
 			blameNodesBroadcast, err := blameMgr.GetBroadcastBlame(lastMsg.Type())
 			if err != nil {
@@ -179,14 +227,21 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 			return nil, blame.ErrTssTimeOut
 		case msg := <-outCh:
 			tKeySign.logger.Debug().Msgf(">>>>>>>>>>key sign msg: %s", msg.String())
+			timer.Round(msg.Type())
 			tKeySign.tssCommonStruct.GetBlameMgr().SetLastMsg(msg)
-			err := tKeySign.tssCommonStruct.ProcessOutCh(msg, messages.TSSKeySignMsg)
+			err := tKeySign.tssCommonStruct.ProcessOutCh(msg, msgType)
 			if err != nil {
 				return nil, err
 			}
 
 		case msg := <-endCh:
 			tKeySign.logger.Debug().Msg("we have done the key sign")
+			if algo == common.EdDSA {
+				// tss-lib gives R as a big endian integer, we return R and S in the Ed25519 encoding, so R||S is
+				// the signature
+				msg.R = msg.Signature[:32]
+				msg.S = msg.Signature[32:]
+			}
 			err := tKeySign.tssCommonStruct.NotifyTaskDone()
 			if err != nil {
 				tKeySign.logger.Error().Err(err).Msg("fail to broadcast the keysign done")
@@ -200,6 +255,14 @@ func (tKeySign *TssKeySign) processKeySign(errChan chan struct{}, outCh <-chan b
 	}
 }
 
+// GetMsgTypes return the message types used by the keysign of the given algorithm
+func GetMsgTypes(algo common.Algo) (messages.THORChainTSSMessageType, messages.THORChainTSSMessageType) {
+	if algo == common.EdDSA {
+		return messages.TSSEdDSAKeySignMsg, messages.TSSEdDSAKeySignVerMsg
+	}
+	return messages.TSSKeySignMsg, messages.TSSKeySignVerMsg
+}
+
 func (tKeySign *TssKeySign) WriteKeySignResult(w http.ResponseWriter, R, S string, status common.Status) {
 	signResp := Response{
 		R:      R,
@@ -0,0 +1,232 @@
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+
+	"github.com/binance-chain/go-sdk/common/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+)
+
+// ErrInvalidMAC is returned when the MAC of the keystore doesn't match, which is mostly because of the wrong
+// passphrase, the key is never decrypted in that case
+var ErrInvalidMAC = errors.New("invalid keystore MAC, the passphrase is wrong or the keystore is corrupted")
+
+const (
+	cipherName = "aes-256-ctr"
+	kdfName    = "pbkdf2"
+	kdfPRF     = "hmac-sha256"
+	// kdfIterations is the pbkdf2 iterations of the new keystores
+	kdfIterations = 262144
+	// the pbkdf2 iterations of the keystores we read have to be within these bounds, fewer iterations make the
+	// passphrase cheap to brute force, more stall the start for no gain
+	minKDFIterations = kdfIterations
+	maxKDFIterations = 16 * kdfIterations
+	kdfKeyLen        = 32
+)
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// CryptoJSON is the encrypted key and how it is encrypted
+type CryptoJSON struct {
+	Cipher       string                 `json:"cipher"`
+	CipherText   string                 `json:"ciphertext"`
+	CipherParams cipherParams           `json:"cipherparams"`
+	KDF          string                 `json:"kdf"`
+	KDFParams    map[string]interface{} `json:"kdfparams"`
+	MAC          string                 `json:"mac"`
+}
+
+// EncryptedKey is the keystore file of the binance key, the key is encrypted by aes-256-ctr with the key derived from
+// the passphrase by pbkdf2
+type EncryptedKey struct {
+	Crypto  CryptoJSON `json:"crypto"`
+	Id      string     `json:"id"`
+	Version int        `json:"version"`
+}
+
+// Encrypt create a keystore of the given private key, protected by the given passphrase
+func Encrypt(privKey []byte, passphrase string) (*EncryptedKey, error) {
+	salt, err := generateRandomBytes(32)
+	if err != nil {
+		return nil, err
+	}
+	iv, err := generateRandomBytes(16)
+	if err != nil {
+		return nil, err
+	}
+	kdfParams := make(map[string]interface{}, 4)
+	kdfParams["prf"] = kdfPRF
+	kdfParams["dklen"] = kdfKeyLen
+	kdfParams["salt"] = hex.EncodeToString(salt)
+	kdfParams["c"] = kdfIterations
+
+	derivedKey := pbkdf2.Key([]byte(passphrase), salt, kdfIterations, kdfKeyLen, sha256.New)
+	cipherText, err := aesCTRXOR(derivedKey[:32], privKey, iv)
+	if err != nil {
+		return nil, err
+	}
+	mac, err := getMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptedKey{
+		Crypto: CryptoJSON{
+			Cipher:       cipherName,
+			CipherText:   hex.EncodeToString(cipherText),
+			CipherParams: cipherParams{IV: hex.EncodeToString(iv)},
+			KDF:          kdfName,
+			KDFParams:    kdfParams,
+			MAC:          hex.EncodeToString(mac),
+		},
+		Id:      id.String(),
+		Version: 1,
+	}, nil
+}
+
+// Decrypt return the private key in the given keystore, the MAC is checked before the key is decrypted
+func Decrypt(key *EncryptedKey, passphrase string) ([]byte, error) {
+	if key.Crypto.Cipher != cipherName {
+		return nil, fmt.Errorf("cipher(%s) is not supported", key.Crypto.Cipher)
+	}
+	if key.Crypto.KDF != kdfName {
+		return nil, fmt.Errorf("kdf(%s) is not supported", key.Crypto.KDF)
+	}
+	if prf, _ := key.Crypto.KDFParams["prf"].(string); prf != kdfPRF {
+		return nil, fmt.Errorf("prf(%v) is not supported", key.Crypto.KDFParams["prf"])
+	}
+	iterations, err := getKDFIterations(key.Crypto.KDFParams["c"])
+	if err != nil {
+		return nil, err
+	}
+	keyLen, _ := key.Crypto.KDFParams["dklen"].(float64)
+	if keyLen != kdfKeyLen {
+		return nil, errors.New("invalid kdf params")
+	}
+	saltHex, _ := key.Crypto.KDFParams["salt"].(string)
+	salt, err := hex.DecodeString(saltHex)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the salt: %w", err)
+	}
+	iv, err := hex.DecodeString(key.Crypto.CipherParams.IV)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the iv: %w", err)
+	}
+	cipherText, err := hex.DecodeString(key.Crypto.CipherText)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the cipher text: %w", err)
+	}
+	expectedMAC, err := hex.DecodeString(key.Crypto.MAC)
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the MAC: %w", err)
+	}
+
+	derivedKey := pbkdf2.Key([]byte(passphrase), salt, iterations, kdfKeyLen, sha256.New)
+	mac, err := getMAC(derivedKey, cipherText)
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare(mac, expectedMAC) != 1 {
+		return nil, ErrInvalidMAC
+	}
+	return aesCTRXOR(derivedKey[:32], cipherText, iv)
+}
+
+// getKDFIterations return the pbkdf2 iterations of the keystore, it has to be an integer within the bounds
+func getKDFIterations(c interface{}) (int, error) {
+	// the numbers in the json file are decoded as float64
+	iterations, ok := c.(float64)
+	if !ok || iterations != math.Trunc(iterations) || iterations < minKDFIterations || iterations > maxKDFIterations {
+		return 0, fmt.Errorf("invalid kdf iterations(%v), it has to be an integer from %d to %d", c, minKDFIterations, maxKDFIterations)
+	}
+	return int(iterations), nil
+}
+
+// Load read the keystore from the given file and decrypt the private key in it
+func Load(file, passphrase string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("fail to read the keystore: %w", err)
+	}
+	var key EncryptedKey
+	if err := json.Unmarshal(buf, &key); err != nil {
+		return nil, fmt.Errorf("fail to unmarshal the keystore: %w", err)
+	}
+	return Decrypt(&key, passphrase)
+}
+
+// Save encrypt the given private key and write the keystore to the given file, which is only readable by the owner.
+// The existing file is never overwritten
+func Save(file string, privKey []byte, passphrase string) error {
+	key, err := Encrypt(privKey, passphrase)
+	if err != nil {
+		return fmt.Errorf("fail to encrypt the key: %w", err)
+	}
+	buf, err := json.Marshal(key)
+	if err != nil {
+		return fmt.Errorf("fail to marshal the keystore: %w", err)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return fmt.Errorf("fail to create the keystore: %w", err)
+	}
+	if _, err := f.Write(buf); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fail to write the keystore: %w", err)
+	}
+	return f.Close()
+}
+
+// getMAC return the MAC of the cipher text, it is the keccak512 of the second half of the derived key and the cipher
+// text
+func getMAC(derivedKey, cipherText []byte) ([]byte, error) {
+	hasher := sha3.NewLegacyKeccak512()
+	if _, err := hasher.Write(derivedKey[16:32]); err != nil {
+		return nil, err
+	}
+	if _, err := hasher.Write(cipherText); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
+	aesBlock, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != aesBlock.BlockSize() {
+		return nil, errors.New("invalid iv length")
+	}
+	stream := cipher.NewCTR(aesBlock, iv)
+	outText := make([]byte, len(inText))
+	stream.XORKeyStream(outText, inText)
+	return outText, nil
+}
+
+func generateRandomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	// Note that err == nil only if we read len(b) bytes.
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,81 @@
+package keystore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type KeystoreTestSuite struct{}
+
+var _ = Suite(&KeystoreTestSuite{})
+
+var testPrivKey = []byte("0123456789abcdef0123456789abcdef")
+
+func (s *KeystoreTestSuite) TestEncryptDecrypt(c *C) {
+	key, err := Encrypt(testPrivKey, "passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(key.Crypto.Cipher, Equals, "aes-256-ctr")
+	c.Assert(key.Crypto.KDF, Equals, "pbkdf2")
+
+	// the keystore is read back from json as the numbers of the kdf params are float64 then
+	buf, err := json.Marshal(key)
+	c.Assert(err, IsNil)
+	var loaded EncryptedKey
+	c.Assert(json.Unmarshal(buf, &loaded), IsNil)
+	privKey, err := Decrypt(&loaded, "passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(privKey, DeepEquals, testPrivKey)
+
+	_, err = Decrypt(&loaded, "wrong passphrase")
+	c.Assert(errors.Is(err, ErrInvalidMAC), Equals, true)
+
+	// the tampered cipher text is refused before decryption
+	cipherText, err := hex.DecodeString(loaded.Crypto.CipherText)
+	c.Assert(err, IsNil)
+	cipherText[0] ^= 0xff
+	loaded.Crypto.CipherText = hex.EncodeToString(cipherText)
+	_, err = Decrypt(&loaded, "passphrase")
+	c.Assert(errors.Is(err, ErrInvalidMAC), Equals, true)
+
+	loaded.Crypto.KDF = "scrypt"
+	_, err = Decrypt(&loaded, "passphrase")
+	c.Assert(err, NotNil)
+}
+
+func (s *KeystoreTestSuite) TestKDFIterations(c *C) {
+	key, err := Encrypt(testPrivKey, "passphrase")
+	c.Assert(err, IsNil)
+	buf, err := json.Marshal(key)
+	c.Assert(err, IsNil)
+	for _, el := range []interface{}{float64(1), float64(kdfIterations - 1), float64(kdfIterations) + 0.5, float64(maxKDFIterations + 1), 1e300, -1.0, "262144", nil} {
+		var loaded EncryptedKey
+		c.Assert(json.Unmarshal(buf, &loaded), IsNil)
+		loaded.Crypto.KDFParams["c"] = el
+		_, err = Decrypt(&loaded, "passphrase")
+		c.Assert(err, NotNil, Commentf("%v", el))
+	}
+}
+
+func (s *KeystoreTestSuite) TestSaveLoad(c *C) {
+	file := filepath.Join(c.MkDir(), "keystore.json")
+	c.Assert(Save(file, testPrivKey, "passphrase"), IsNil)
+	privKey, err := Load(file, "passphrase")
+	c.Assert(err, IsNil)
+	c.Assert(privKey, DeepEquals, testPrivKey)
+	_, err = Load(file, "wrong passphrase")
+	c.Assert(errors.Is(err, ErrInvalidMAC), Equals, true)
+	// the existing keystore is never overwritten
+	c.Assert(Save(file, testPrivKey, "passphrase"), NotNil)
+
+	c.Assert(ioutil.WriteFile(file+".bad", []byte("whatever"), 0o600), IsNil)
+	_, err = Load(file+".bad", "passphrase")
+	c.Assert(err, NotNil)
+}
@@ -16,4 +16,20 @@ const (
 	KEYSIGN7         = "binance.tss-lib.ecdsa.signing.SignRound7Message"
 	KEYSIGN8         = "binance.tss-lib.ecdsa.signing.SignRound8Message"
 	KEYSIGN9         = "binance.tss-lib.ecdsa.signing.SignRound9Message"
+
+	RESHARE1         = "binance.tss-lib.ecdsa.resharing.DGRound1Message"
+	RESHARE2a        = "binance.tss-lib.ecdsa.resharing.DGRound2Message1"
+	RESHARE2b        = "binance.tss-lib.ecdsa.resharing.DGRound2Message2"
+	RESHARE3aUnicast = "binance.tss-lib.ecdsa.resharing.DGRound3Message1"
+	RESHARE3b        = "binance.tss-lib.ecdsa.resharing.DGRound3Message2"
+	RESHARE4         = "binance.tss-lib.ecdsa.resharing.DGRound4Message"
+
+	// the EdDSA messages of tss-lib are registered without the package name
+	EDDSAKEYGEN1         = "KGRound1Message"
+	EDDSAKEYGEN2aUnicast = "KGRound2Message1"
+	EDDSAKEYGEN2b        = "KGRound2Message2"
+
+	EDDSAKEYSIGN1 = "SignRound1Message"
+	EDDSAKEYSIGN2 = "SignRound2Message"
+	EDDSAKEYSIGN3 = "SignRound3Message"
 )
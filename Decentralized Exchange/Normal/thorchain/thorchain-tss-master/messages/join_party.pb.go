@@ -59,6 +59,7 @@ func (JoinPartyResponse_ResponseType) EnumDescriptor() ([]byte, []int) {
 
 type JoinPartyRequest struct {
 	ID                   string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Refused              bool     `protobuf:"varint,2,opt,name=Refused,proto3" json:"Refused,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -100,6 +101,13 @@ func (m *JoinPartyRequest) GetID() string {
 	return ""
 }
 
+func (m *JoinPartyRequest) GetRefused() bool {
+	if m != nil {
+		return m.Refused
+	}
+	return false
+}
+
 type JoinPartyResponse struct {
 	ID                   string                         `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	Type                 JoinPartyResponse_ResponseType `protobuf:"varint,2,opt,name=type,proto3,enum=messages.JoinPartyResponse_ResponseType" json:"type,omitempty"`
@@ -168,19 +176,20 @@ func init() {
 func init() { proto.RegisterFile("join_party.proto", fileDescriptor_4d19f49aa56fa857) }
 
 var fileDescriptor_4d19f49aa56fa857 = []byte{
-	// 224 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xb1, 0x4a, 0xc4, 0x40,
-	0x10, 0x40, 0xdd, 0xe4, 0xf0, 0xbc, 0x39, 0x89, 0xeb, 0x54, 0x29, 0x43, 0xaa, 0x54, 0x29, 0xb4,
-	0xb5, 0x4c, 0x13, 0x11, 0x39, 0xd6, 0xb3, 0xb1, 0x91, 0x78, 0x37, 0x48, 0x94, 0xdb, 0x59, 0x33,
-	0x1b, 0x64, 0xbf, 0xd4, 0xdf, 0x91, 0x3d, 0x0c, 0x04, 0xaf, 0xdb, 0xc7, 0xbe, 0x37, 0x03, 0x03,
-	0xfa, 0x83, 0x7b, 0xfb, 0xea, 0xba, 0xc1, 0x87, 0xda, 0x0d, 0xec, 0x19, 0x2f, 0x0e, 0x24, 0xd2,
-	0xbd, 0x93, 0x94, 0x25, 0xe8, 0x7b, 0xee, 0xed, 0x26, 0x7e, 0x1a, 0xfa, 0x1a, 0x49, 0x3c, 0x66,
-	0x90, 0xb4, 0x4d, 0xae, 0x0a, 0x55, 0xad, 0x4c, 0xd2, 0x36, 0xe5, 0x8f, 0x82, 0xeb, 0x99, 0x24,
-	0x8e, 0xad, 0xd0, 0x7f, 0x0b, 0xef, 0x60, 0xe1, 0x83, 0xa3, 0x3c, 0x29, 0x54, 0x95, 0xdd, 0x54,
-	0xf5, 0xb4, 0xa2, 0x3e, 0x49, 0xeb, 0xe9, 0xb1, 0x0d, 0x8e, 0xcc, 0xb1, 0xc2, 0x1c, 0x96, 0x1b,
-	0xa2, 0xa1, 0x6d, 0x24, 0x4f, 0x8b, 0xb4, 0x5a, 0x99, 0x09, 0xcb, 0x17, 0xb8, 0x9c, 0xfb, 0xb8,
-	0x86, 0xe5, 0xb3, 0xfd, 0xb4, 0xfc, 0x6d, 0xf5, 0x59, 0x84, 0xa7, 0x71, 0xb7, 0x23, 0x11, 0xad,
-	0x22, 0x6c, 0xfb, 0x03, 0xf1, 0xe8, 0x75, 0x82, 0x08, 0xd9, 0x03, 0x75, 0x7b, 0x1a, 0x1e, 0xd9,
-	0x1b, 0xea, 0xf6, 0x41, 0xa7, 0x78, 0x05, 0xeb, 0xbf, 0x34, 0x0e, 0xd7, 0x8b, 0xb7, 0xf3, 0xe3,
-	0x39, 0x6e, 0x7f, 0x03, 0x00, 0x00, 0xff, 0xff, 0x62, 0x93, 0xfb, 0x4e, 0x22, 0x01, 0x00, 0x00,
+	// 231 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0x41, 0x4b, 0xc3, 0x40,
+	0x10, 0x85, 0xdd, 0xa4, 0xd8, 0x76, 0x2a, 0x71, 0xdd, 0x53, 0x8e, 0x21, 0xa7, 0x9c, 0x72, 0xd0,
+	0x6b, 0x8f, 0xb9, 0x44, 0x44, 0xca, 0x5a, 0x2f, 0x5e, 0x24, 0x36, 0x4f, 0x89, 0xd2, 0xdd, 0x35,
+	0xb3, 0x41, 0xf2, 0x4b, 0xfd, 0x3b, 0xb2, 0xc1, 0x40, 0xa1, 0xb7, 0xf9, 0x98, 0xf9, 0xe6, 0xc1,
+	0x23, 0xf9, 0x69, 0x3b, 0xf3, 0xea, 0x9a, 0xde, 0x8f, 0xa5, 0xeb, 0xad, 0xb7, 0x6a, 0x75, 0x04,
+	0x73, 0xf3, 0x01, 0xce, 0xb7, 0x24, 0xef, 0x6d, 0x67, 0x76, 0x61, 0xa9, 0xf1, 0x3d, 0x80, 0xbd,
+	0x4a, 0x28, 0xaa, 0xab, 0x54, 0x64, 0xa2, 0x58, 0xeb, 0xa8, 0xae, 0x54, 0x4a, 0x4b, 0x8d, 0xf7,
+	0x81, 0xd1, 0xa6, 0x51, 0x26, 0x8a, 0x95, 0x9e, 0x31, 0xff, 0x15, 0x74, 0x73, 0xa2, 0xb3, 0xb3,
+	0x86, 0x71, 0xe6, 0x6f, 0x69, 0xe1, 0x47, 0x87, 0x49, 0x4e, 0x6e, 0x8b, 0x72, 0x0e, 0x2f, 0xcf,
+	0xd4, 0x72, 0x1e, 0xf6, 0xa3, 0x83, 0x9e, 0xac, 0x90, 0xbe, 0x03, 0xfa, 0xba, 0xe2, 0x34, 0xce,
+	0xe2, 0x62, 0xad, 0x67, 0xcc, 0x5f, 0xe8, 0xea, 0xf4, 0x5e, 0x6d, 0x68, 0xf9, 0x6c, 0xbe, 0x8c,
+	0xfd, 0x31, 0xf2, 0x22, 0xc0, 0xd3, 0x70, 0x38, 0x80, 0x59, 0x8a, 0x00, 0xfb, 0xee, 0x08, 0x3b,
+	0x78, 0x19, 0x29, 0x45, 0xc9, 0x03, 0x9a, 0x16, 0xfd, 0xa3, 0xf5, 0x1a, 0x4d, 0x3b, 0xca, 0x58,
+	0x5d, 0xd3, 0xe6, 0x5f, 0x0d, 0xcf, 0xe5, 0xe2, 0xed, 0x72, 0x2a, 0xea, 0xee, 0x6f, 0x00, 0xc4,
+	0xbf, 0x64, 0xfb, 0x3c, 0x01, 0x00, 0x00,
 }
@@ -23,6 +23,18 @@ const (
 	TSSControlMsg
 	// TSSTaskDone is the message of Tss process notification
 	TSSTaskDone
+	// TSSReshareMsg is the message generated by tss lib for key resharing
+	TSSReshareMsg
+	// TSSEdDSAKeyGenMsg is the message generated by tss lib for EdDSA keygen
+	TSSEdDSAKeyGenMsg
+	// TSSEdDSAKeySignMsg is the message generated by tss lib for EdDSA sign
+	TSSEdDSAKeySignMsg
+	// TSSEdDSAKeyGenVerMsg is the message we create to make sure every party receive the same EdDSA keygen broadcast message
+	TSSEdDSAKeyGenVerMsg
+	// TSSEdDSAKeySignVerMsg is the message we create to make sure every party receive the same EdDSA sign broadcast message
+	TSSEdDSAKeySignVerMsg
+	// TSSReshareVerMsg is the message we create to make sure every party of the committee receive the same resharing broadcast message
+	TSSReshareVerMsg
 	// Unknown is the message indicates the undefined message type
 	Unknown
 )
@@ -38,6 +50,18 @@ func (msgType THORChainTSSMessageType) String() string {
 		return "TSSKeyGenVerMsg"
 	case TSSKeySignVerMsg:
 		return "TSSKeySignVerMsg"
+	case TSSReshareMsg:
+		return "TSSReshareMsg"
+	case TSSEdDSAKeyGenMsg:
+		return "TSSEdDSAKeyGenMsg"
+	case TSSEdDSAKeySignMsg:
+		return "TSSEdDSAKeySignMsg"
+	case TSSEdDSAKeyGenVerMsg:
+		return "TSSEdDSAKeyGenVerMsg"
+	case TSSEdDSAKeySignVerMsg:
+		return "TSSEdDSAKeySignVerMsg"
+	case TSSReshareVerMsg:
+		return "TSSReshareVerMsg"
 	default:
 		return "Unknown"
 	}
@@ -16,10 +16,16 @@ var _ = Suite(&THORChainTSSMessageTypeSuite{})
 
 func (THORChainTSSMessageTypeSuite) TestTHORChainTSSMessageType_String(c *C) {
 	m := map[THORChainTSSMessageType]string{
-		TSSKeyGenMsg:     "TSSKeyGenMsg",
-		TSSKeySignMsg:    "TSSKeySignMsg",
-		TSSKeyGenVerMsg:  "TSSKeyGenVerMsg",
-		TSSKeySignVerMsg: "TSSKeySignVerMsg",
+		TSSKeyGenMsg:          "TSSKeyGenMsg",
+		TSSKeySignMsg:         "TSSKeySignMsg",
+		TSSKeyGenVerMsg:       "TSSKeyGenVerMsg",
+		TSSKeySignVerMsg:      "TSSKeySignVerMsg",
+		TSSReshareMsg:         "TSSReshareMsg",
+		TSSEdDSAKeyGenMsg:     "TSSEdDSAKeyGenMsg",
+		TSSEdDSAKeySignMsg:    "TSSEdDSAKeySignMsg",
+		TSSEdDSAKeyGenVerMsg:  "TSSEdDSAKeyGenVerMsg",
+		TSSEdDSAKeySignVerMsg: "TSSEdDSAKeySignVerMsg",
+		TSSReshareVerMsg:      "TSSReshareVerMsg",
 	}
 	for k, v := range m {
 		c.Assert(k.String(), Equals, v)
@@ -0,0 +1,243 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+const namespace = "tss"
+
+const (
+	OpKeygen  = "keygen"
+	OpKeySign = "keysign"
+	OpReshare = "reshare"
+)
+
+const (
+	// DirectionIn is the label of the messages received from the peers
+	DirectionIn = "in"
+	// DirectionOut is the label of the messages sent to the peers
+	DirectionOut = "out"
+)
+
+const (
+	// ShareRequest is the label of the request for a missing share
+	ShareRequest = "request"
+	// ShareResponse is the label of the missing share sent back to the requester
+	ShareResponse = "response"
+)
+
+const (
+	ResultSuccess   = "success"
+	ResultTimeout   = "timeout"
+	ResultRefused   = "refused"
+	ResultCancelled = "cancelled"
+	ResultError     = "error"
+)
+
+// the durations of the tss sessions range from a few hundred milliseconds to a few minutes
+var durationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300}
+
+var (
+	registry = prometheus.NewRegistry()
+
+	joinPartyDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "join_party_duration_seconds",
+		Help:      "How long it takes to form the party",
+		Buckets:   durationBuckets,
+	}, []string{"operation", "result"})
+	joinPartyTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "join_party_timeouts_total",
+		Help:      "How many times the party isn't formed in time",
+	}, []string{"operation"})
+	roundDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "round_duration_seconds",
+		Help:      "How long each round of the tss session takes",
+		Buckets:   durationBuckets,
+	}, []string{"operation", "round"})
+	sessionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "session_duration_seconds",
+		Help:      "How long the whole tss session takes once the party is formed",
+		Buckets:   durationBuckets,
+	}, []string{"operation", "result"})
+	p2pMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "p2p_messages_total",
+		Help:      "How many messages are sent to or received from the peers",
+	}, []string{"direction", "type"})
+	p2pBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "p2p_bytes_total",
+		Help:      "How many bytes are sent to or received from the peers",
+	}, []string{"direction", "type"})
+	shareRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "share_requests_total",
+		Help:      "How many missing shares are requested from or sent back to the peers",
+	}, []string{"direction", "kind"})
+	blameReasons = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blame_total",
+		Help:      "How many sessions fail with the blame of the given reason",
+	}, []string{"operation", "reason"})
+	blameNodes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "blame_nodes_total",
+		Help:      "How many times the given node is blamed",
+	}, []string{"operation", "node"})
+	signatureWaits = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "signature_wait_duration_seconds",
+		Help:      "How long the node out of the keysign party waits for the signature",
+		Buckets:   durationBuckets,
+	}, []string{"result"})
+	httpUnauthorized = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_unauthorized_total",
+		Help:      "How many http requests are refused for the missing or invalid credentials",
+	}, []string{"reason"})
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		joinPartyDuration,
+		joinPartyTimeouts,
+		roundDuration,
+		sessionDuration,
+		p2pMessages,
+		p2pBytes,
+		shareRequests,
+		blameReasons,
+		blameNodes,
+		signatureWaits,
+		httpUnauthorized,
+	)
+}
+
+// Handler return the http handler exporting the metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// ResultOf return the result label of a session ends with the given error
+func ResultOf(err error) string {
+	switch {
+	case err == nil:
+		return ResultSuccess
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return ResultCancelled
+	case errors.Is(err, blame.ErrTssTimeOut):
+		return ResultTimeout
+	default:
+		return ResultError
+	}
+}
+
+// ObserveJoinParty record how long it takes to form the party of the given operation and the result
+func ObserveJoinParty(operation string, duration time.Duration, result string) {
+	joinPartyDuration.WithLabelValues(operation, result).Observe(duration.Seconds())
+	if result == ResultTimeout {
+		joinPartyTimeouts.WithLabelValues(operation).Inc()
+	}
+}
+
+// ObserveMessage record a message of the given type sent to or received from a peer
+func ObserveMessage(direction, msgType string, size int) {
+	p2pMessages.WithLabelValues(direction, msgType).Inc()
+	p2pBytes.WithLabelValues(direction, msgType).Add(float64(size))
+}
+
+// ObserveShareRequest record a missing share request or response sent to or received from a peer
+func ObserveShareRequest(direction, kind string) {
+	shareRequests.WithLabelValues(direction, kind).Inc()
+}
+
+// ObserveBlame record the reason and the nodes of the given blame, the empty blame is ignored
+func ObserveBlame(operation string, b blame.Blame) {
+	if len(b.FailReason) == 0 {
+		return
+	}
+	blameReasons.WithLabelValues(operation, b.FailReason).Inc()
+	for _, el := range b.BlameNodes {
+		blameNodes.WithLabelValues(operation, el.Pubkey).Inc()
+	}
+}
+
+// ObserveSignatureWait record how long the node waits for the signature of a keysign it is not part of
+func ObserveSignatureWait(duration time.Duration, result string) {
+	signatureWaits.WithLabelValues(result).Observe(duration.Seconds())
+}
+
+// ObserveUnauthorized record an http request refused for the given reason
+func ObserveUnauthorized(reason string) {
+	httpUnauthorized.WithLabelValues(reason).Inc()
+}
+
+// SessionTimer times a tss session and each of its rounds, the rounds are told apart by the type of the messages
+// the local party produces
+type SessionTimer struct {
+	operation  string
+	lock       *sync.Mutex
+	start      time.Time
+	round      string
+	roundStart time.Time
+	now        func() time.Time
+}
+
+// NewSessionTimer start to time a session of the given operation
+func NewSessionTimer(operation string) *SessionTimer {
+	return newSessionTimer(operation, time.Now)
+}
+
+func newSessionTimer(operation string, now func() time.Time) *SessionTimer {
+	start := now()
+	return &SessionTimer{
+		operation:  operation,
+		lock:       &sync.Mutex{},
+		start:      start,
+		roundStart: start,
+		now:        now,
+	}
+}
+
+// Round tell the timer the local party produces a message of the given round, the previous round ends when the
+// local party moves on to the next round
+func (s *SessionTimer) Round(round string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if round == s.round {
+		return
+	}
+	now := s.now()
+	if len(s.round) != 0 {
+		roundDuration.WithLabelValues(s.operation, s.round).Observe(now.Sub(s.roundStart).Seconds())
+	}
+	s.round = round
+	s.roundStart = now
+}
+
+// Done stop the timer, the last round ends with the session
+func (s *SessionTimer) Done(result string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	now := s.now()
+	if len(s.round) != 0 {
+		roundDuration.WithLabelValues(s.operation, s.round).Observe(now.Sub(s.roundStart).Seconds())
+		s.round = ""
+	}
+	sessionDuration.WithLabelValues(s.operation, result).Observe(now.Sub(s.start).Seconds())
+}
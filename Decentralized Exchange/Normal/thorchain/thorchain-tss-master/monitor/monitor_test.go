@@ -0,0 +1,111 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type MonitorTestSuite struct{}
+
+var _ = Suite(&MonitorTestSuite{})
+
+// histogram return the sample count and sum of the histogram of the given name and labels
+func histogram(c *C, name string, labels map[string]string) (uint64, float64) {
+	families, err := registry.Gather()
+	c.Assert(err, IsNil)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := 0
+			for _, el := range m.GetLabel() {
+				if labels[el.GetName()] == el.GetValue() {
+					matched++
+				}
+			}
+			if matched == len(labels) {
+				return m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+			}
+		}
+	}
+	return 0, 0
+}
+
+func (s *MonitorTestSuite) TestResultOf(c *C) {
+	c.Assert(ResultOf(nil), Equals, ResultSuccess)
+	c.Assert(ResultOf(context.Canceled), Equals, ResultCancelled)
+	c.Assert(ResultOf(fmt.Errorf("keygen: %w", context.DeadlineExceeded)), Equals, ResultCancelled)
+	c.Assert(ResultOf(blame.ErrTssTimeOut), Equals, ResultTimeout)
+	c.Assert(ResultOf(errors.New("whatever")), Equals, ResultError)
+}
+
+func (s *MonitorTestSuite) TestCounters(c *C) {
+	ObserveJoinParty("test-join", time.Second, ResultSuccess)
+	ObserveJoinParty("test-join", time.Second*10, ResultTimeout)
+	c.Assert(testutil.ToFloat64(joinPartyTimeouts.WithLabelValues("test-join")), Equals, 1.0)
+	count, _ := histogram(c, "tss_join_party_duration_seconds", map[string]string{"operation": "test-join", "result": ResultTimeout})
+	c.Assert(count, Equals, uint64(1))
+
+	ObserveMessage(DirectionOut, "test-msg", 10)
+	ObserveMessage(DirectionOut, "test-msg", 20)
+	c.Assert(testutil.ToFloat64(p2pMessages.WithLabelValues(DirectionOut, "test-msg")), Equals, 2.0)
+	c.Assert(testutil.ToFloat64(p2pBytes.WithLabelValues(DirectionOut, "test-msg")), Equals, 30.0)
+
+	ObserveBlame("test-blame", blame.Blame{})
+	ObserveBlame("test-blame", blame.NewBlame(blame.TssTimeout, []blame.Node{{Pubkey: "a"}, {Pubkey: "b"}}))
+	ObserveBlame("test-blame", blame.NewBlame(blame.HashCheckFail, []blame.Node{{Pubkey: "a"}}))
+	c.Assert(testutil.ToFloat64(blameReasons.WithLabelValues("test-blame", blame.TssTimeout)), Equals, 1.0)
+	c.Assert(testutil.ToFloat64(blameNodes.WithLabelValues("test-blame", "a")), Equals, 2.0)
+	c.Assert(testutil.ToFloat64(blameNodes.WithLabelValues("test-blame", "b")), Equals, 1.0)
+}
+
+func (s *MonitorTestSuite) TestSessionTimer(c *C) {
+	now := time.Now()
+	timer := newSessionTimer("test-session", func() time.Time { return now })
+	now = now.Add(time.Second)
+	timer.Round("round1")
+	now = now.Add(time.Second * 2)
+	timer.Round("round1")
+	timer.Round("round2")
+	now = now.Add(time.Second * 3)
+	timer.Done(ResultSuccess)
+
+	count, sum := histogram(c, "tss_round_duration_seconds", map[string]string{"operation": "test-session", "round": "round1"})
+	c.Assert(count, Equals, uint64(1))
+	c.Assert(sum, Equals, 2.0)
+	count, sum = histogram(c, "tss_round_duration_seconds", map[string]string{"operation": "test-session", "round": "round2"})
+	c.Assert(count, Equals, uint64(1))
+	c.Assert(sum, Equals, 3.0)
+	count, sum = histogram(c, "tss_session_duration_seconds", map[string]string{"operation": "test-session", "result": ResultSuccess})
+	c.Assert(count, Equals, uint64(1))
+	c.Assert(sum, Equals, 6.0)
+}
+
+func (s *MonitorTestSuite) TestHandler(c *C) {
+	ObserveShareRequest(DirectionIn, ShareRequest)
+	server := httptest.NewServer(Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	c.Assert(err, IsNil)
+	defer resp.Body.Close()
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	body, err := ioutil.ReadAll(resp.Body)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(body), `tss_share_requests_total{direction="in",kind="request"}`), Equals, true)
+	c.Assert(strings.Contains(string(body), "go_goroutines"), Equals, true)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 )
 
 var joinPartyProtocol protocol.ID = "/p2p/join-party"
@@ -163,6 +164,7 @@ func (c *Communication) readFromStream(stream network.Stream) {
 			return
 		}
 		c.logger.Debug().Msgf(">>>>>>>[%s] %s", wrappedMsg.MessageType, string(wrappedMsg.Payload))
+		monitor.ObserveMessage(monitor.DirectionIn, wrappedMsg.MessageType.String(), len(dataBuf))
 		channel := c.getSubscriber(wrappedMsg.MessageType, wrappedMsg.MsgID)
 		if nil == channel {
 			c.logger.Info().Msgf("no MsgID %s found for this message", wrappedMsg.MsgID)
@@ -411,6 +413,11 @@ func (c *Communication) ProcessBroadcast() {
 				continue
 			}
 			c.logger.Debug().Msgf("broadcast message %s to %+v", msg.WrappedMessage, msg.PeersID)
+			for _, el := range msg.PeersID {
+				if el != c.host.ID() {
+					monitor.ObserveMessage(monitor.DirectionOut, msg.WrappedMessage.MessageType.String(), len(wrappedMsgBytes))
+				}
+			}
 			c.Broadcast(msg.PeersID, wrappedMsgBytes)
 
 		case <-c.stopChan:
@@ -14,22 +14,35 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/messages"
 )
 
 var errJoinPartyTimeout = errors.New("fail to join party, timeout")
 
+// JoinPartyRefusedError is returned when some of the peers refuse to join the party
+type JoinPartyRefusedError struct {
+	Peers []peer.ID
+}
+
+func (e *JoinPartyRefusedError) Error() string {
+	return fmt.Sprintf("fail to join party, refused by %v", e.Peers)
+}
+
 type PartyCoordinator struct {
 	logger             zerolog.Logger
 	host               host.Host
 	stopChan           chan struct{}
 	timeout            time.Duration
 	peersGroup         map[string]*PeerStatus
+	refusedParties     map[string]time.Time
 	joinPartyGroupLock *sync.Mutex
+	eventBus           *events.Bus
 }
 
-// NewPartyCoordinator create a new instance of PartyCoordinator
-func NewPartyCoordinator(host host.Host, timeout time.Duration) *PartyCoordinator {
+// NewPartyCoordinator create a new instance of PartyCoordinator, the events of the party are published to the given
+// bus, which can be nil
+func NewPartyCoordinator(host host.Host, timeout time.Duration, eventBus *events.Bus) *PartyCoordinator {
 	// if no timeout is given, default to 10 seconds
 	if timeout.Nanoseconds() == 0 {
 		timeout = 10 * time.Second
@@ -40,7 +53,9 @@ func NewPartyCoordinator(host host.Host, timeout time.Duration) *PartyCoordinato
 		stopChan:           make(chan struct{}),
 		timeout:            timeout,
 		peersGroup:         make(map[string]*PeerStatus),
+		refusedParties:     make(map[string]time.Time),
 		joinPartyGroupLock: &sync.Mutex{},
+		eventBus:           eventBus,
 	}
 	host.SetStreamHandler(joinPartyProtocol, pc.HandleStream)
 	return pc
@@ -75,17 +90,39 @@ func (pc *PartyCoordinator) HandleStream(stream network.Stream) {
 	}
 	pc.joinPartyGroupLock.Lock()
 	peerGroup, ok := pc.peersGroup[msg.ID]
+	_, refused := pc.refusedParties[msg.ID]
 	pc.joinPartyGroupLock.Unlock()
+	// the peer starts to join the party after we refuse it, tell it again so it doesn't wait for us
+	if refused && !msg.Refused {
+		go func() {
+			if err := pc.sendRequestToPeer(&messages.JoinPartyRequest{ID: msg.ID, Refused: true}, remotePeer); err != nil {
+				logger.Error().Err(err).Msg("fail to send the refusal to peer")
+			}
+		}()
+		return
+	}
 	if !ok {
 		pc.logger.Info().Msg("this party is not ready")
 		return
 	}
+	if msg.Refused {
+		logger.Info().Str("msgID", msg.ID).Msg("peer refuses to join the party")
+		if err := peerGroup.refusePeer(remotePeer); err != nil {
+			pc.logger.Error().Err(err).Msg("receive refusal from unknown peer")
+		}
+		return
+	}
 	newFound, err := peerGroup.updatePeer(remotePeer)
 	if err != nil {
 		pc.logger.Error().Err(err).Msg("receive msg from unknown peer")
 		return
 	}
 	if newFound {
+		pc.eventBus.Publish(events.Event{
+			SessionID: msg.ID,
+			Type:      events.PeerJoined,
+			Peer:      remotePeer.String(),
+		})
 		peerGroup.newFound <- true
 	}
 }
@@ -164,8 +201,35 @@ func (pc *PartyCoordinator) sendRequestToPeer(msg *messages.JoinPartyRequest, re
 	return nil
 }
 
-// JoinPartyWithRetry this method provide the functionality to join party with retry and back off
-func (pc *PartyCoordinator) JoinPartyWithRetry(msg *messages.JoinPartyRequest, peers []string) ([]peer.ID, error) {
+// RefuseParty tell the given peers we won't join the party of the given message, so they give up the party straight
+// away rather than waiting for the timeout, the peers asking to join the party later are told as well
+func (pc *PartyCoordinator) RefuseParty(messageID string, peers []string) error {
+	pIDs, err := pc.getPeerIDs(peers)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	pc.joinPartyGroupLock.Lock()
+	for id, refusedAt := range pc.refusedParties {
+		if now.Sub(refusedAt) > pc.timeout*2 {
+			delete(pc.refusedParties, id)
+		}
+	}
+	pc.refusedParties[messageID] = now
+	pc.joinPartyGroupLock.Unlock()
+	var others []peer.ID
+	for _, el := range pIDs {
+		if el != pc.host.ID() {
+			others = append(others, el)
+		}
+	}
+	pc.sendRequestToAll(&messages.JoinPartyRequest{ID: messageID, Refused: true}, others)
+	return nil
+}
+
+// JoinPartyWithRetry this method provide the functionality to join party with retry and back off, it gives up
+// as soon as the given context is done
+func (pc *PartyCoordinator) JoinPartyWithRetry(ctx context.Context, msg *messages.JoinPartyRequest, peers []string) ([]peer.ID, error) {
 	peerGroup, err := pc.createJoinPartyGroups(msg.ID, peers)
 	if err != nil {
 		pc.logger.Error().Err(err).Msg("fail to create the join party group")
@@ -200,21 +264,37 @@ func (pc *PartyCoordinator) JoinPartyWithRetry(msg *messages.JoinPartyRequest, p
 					close(done)
 					return
 				}
+			case <-peerGroup.refused:
+				close(done)
+				return
 			case <-time.After(pc.timeout):
 				// timeout
 				close(done)
 				return
+			case <-ctx.Done():
+				close(done)
+				return
 			}
 		}
 	}()
 
 	wg.Wait()
+	if ctx.Err() != nil {
+		pc.logger.Info().Str("msgID", msg.ID).Msg("join party is cancelled")
+		return nil, ctx.Err()
+	}
 	onlinePeers, _ := peerGroup.getPeersStatus()
+	if refusedPeers := peerGroup.getRefusedPeers(); len(refusedPeers) > 0 {
+		pc.logger.Info().Str("msgID", msg.ID).Msgf("peers %v refuse to join the party", refusedPeers)
+		return append(onlinePeers, pc.host.ID()), &JoinPartyRefusedError{Peers: refusedPeers}
+	}
 	pc.sendRequestToAll(msg, onlinePeers)
 	// we always set ourselves as online
 	onlinePeers = append(onlinePeers, pc.host.ID())
 	if len(onlinePeers) == len(peers) {
+		pc.eventBus.Publish(events.Event{SessionID: msg.ID, Type: events.PartyFormed})
 		return onlinePeers, nil
 	}
+	pc.eventBus.Publish(events.Event{SessionID: msg.ID, Type: events.PartyTimeout})
 	return onlinePeers, errJoinPartyTimeout
 }
@@ -2,6 +2,7 @@ package p2p
 
 import (
 	"context"
+	"errors"
 	"math/rand"
 	"sort"
 	"sync"
@@ -9,6 +10,7 @@ import (
 	"time"
 
 	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
 	tnet "github.com/libp2p/go-libp2p-testing/net"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/stretchr/testify/assert"
@@ -48,7 +50,7 @@ func TestNewPartyCoordinator(t *testing.T) {
 
 	timeout := time.Second * 10
 	for _, el := range hosts {
-		pcs = append(pcs, *NewPartyCoordinator(el, timeout))
+		pcs = append(pcs, *NewPartyCoordinator(el, timeout, nil))
 		peers = append(peers, el.ID().String())
 	}
 
@@ -71,7 +73,7 @@ func TestNewPartyCoordinator(t *testing.T) {
 			defer wg.Done()
 			// we simulate different nodes join at different time
 			time.Sleep(time.Second * time.Duration(rand.Int()%10))
-			onlinePeers, err := coordinator.JoinPartyWithRetry(&joinPartyReq, peers)
+			onlinePeers, err := coordinator.JoinPartyWithRetry(context.Background(), &joinPartyReq, peers)
 			if err != nil {
 				t.Error(err)
 			}
@@ -90,7 +92,7 @@ func TestNewPartyCoordinatorTimeOut(t *testing.T) {
 	var pcs []*PartyCoordinator
 	var peers []string
 	for _, el := range hosts {
-		pcs = append(pcs, NewPartyCoordinator(el, timeout))
+		pcs = append(pcs, NewPartyCoordinator(el, timeout, nil))
 	}
 	sort.Slice(pcs, func(i, j int) bool {
 		return pcs[i].host.ID().String() > pcs[j].host.ID().String()
@@ -116,7 +118,7 @@ func TestNewPartyCoordinatorTimeOut(t *testing.T) {
 		wg.Add(1)
 		go func(coordinator *PartyCoordinator) {
 			defer wg.Done()
-			onlinePeers, err := coordinator.JoinPartyWithRetry(&joinPartyReq, peers)
+			onlinePeers, err := coordinator.JoinPartyWithRetry(context.Background(), &joinPartyReq, peers)
 			assert.Errorf(t, err, errJoinPartyTimeout.Error())
 			var onlinePeersStr []string
 			for _, el := range onlinePeers {
@@ -132,6 +134,66 @@ func TestNewPartyCoordinatorTimeOut(t *testing.T) {
 	wg.Wait()
 }
 
+func TestNewPartyCoordinatorCancel(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 2)
+	pc := NewPartyCoordinator(hosts[0], time.Second*10, nil)
+	defer pc.Stop()
+	peers := []string{hosts[0].ID().String(), hosts[1].ID().String()}
+
+	joinPartyReq := messages.JoinPartyRequest{
+		ID: conversion.RandStringBytesMask(64),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	onlinePeers, err := pc.JoinPartyWithRetry(ctx, &joinPartyReq, peers)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Nil(t, onlinePeers)
+	// we should give up straight away rather than waiting for the party timeout
+	assert.True(t, time.Since(start) < time.Second*5)
+}
+
+func TestNewPartyCoordinatorRefuse(t *testing.T) {
+	ApplyDeadline = false
+	hosts := setupHosts(t, 3)
+	var pcs []*PartyCoordinator
+	var peers []string
+	for _, el := range hosts {
+		pcs = append(pcs, NewPartyCoordinator(el, time.Second*10, nil))
+		peers = append(peers, el.ID().String())
+	}
+	defer func() {
+		for _, el := range pcs {
+			el.Stop()
+		}
+	}()
+
+	msgID := conversion.RandStringBytesMask(64)
+	joinPartyReq := messages.JoinPartyRequest{
+		ID: msgID,
+	}
+	// the first peer refuses before the last one starts to join, the last one learns it once it asks the first
+	assert.Nil(t, pcs[0].RefuseParty(msgID, peers))
+	start := time.Now()
+	wg := sync.WaitGroup{}
+	for _, el := range pcs[1:] {
+		wg.Add(1)
+		go func(coordinator *PartyCoordinator) {
+			defer wg.Done()
+			_, err := coordinator.JoinPartyWithRetry(context.Background(), &joinPartyReq, peers)
+			var refusedErr *JoinPartyRefusedError
+			if assert.True(t, errors.As(err, &refusedErr)) {
+				assert.Equal(t, refusedErr.Peers, []peer.ID{hosts[0].ID()})
+			}
+		}(el)
+	}
+	wg.Wait()
+	// we should give up straight away rather than waiting for the party timeout
+	assert.True(t, time.Since(start) < time.Second*5)
+	assert.NotNil(t, pcs[0].RefuseParty(msgID, []string{"whatever"}))
+}
+
 func TestGetPeerIDs(t *testing.T) {
 	ApplyDeadline = false
 	id1 := tnet.RandIdentityOrFatal(t)
@@ -145,7 +207,7 @@ func TestGetPeerIDs(t *testing.T) {
 	}
 	p1 := h1.ID()
 	timeout := time.Second * 5
-	pc := NewPartyCoordinator(h1, timeout)
+	pc := NewPartyCoordinator(h1, timeout, nil)
 	r, err := pc.getPeerIDs([]string{})
 	assert.Nil(t, err)
 	assert.Len(t, r, 0)
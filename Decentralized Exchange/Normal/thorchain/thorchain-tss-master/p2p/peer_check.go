@@ -0,0 +1,119 @@
+package p2p
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	swarm "github.com/libp2p/go-libp2p-swarm"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	maddr "github.com/multiformats/go-multiaddr"
+)
+
+// TimeoutPeerCheck bounds how long do we try to connect to and ping a peer in the reachability check
+const TimeoutPeerCheck = time.Second * 10
+
+// PeerReachability is the result of the reachability check of a peer
+type PeerReachability struct {
+	PeerID       string   `json:"peer_id"`
+	Reachable    bool     `json:"reachable"`
+	RTT          string   `json:"rtt,omitempty"`
+	Addresses    []string `json:"addresses"`
+	AgentVersion string   `json:"agent_version,omitempty"`
+	Error        string   `json:"error,omitempty"`
+}
+
+// CheckPeers connect to and ping the given peers in parallel, the check of each peer gives up after the given timeout
+// or once the context is done, the results are in the order of the peers
+func CheckPeers(ctx context.Context, h host.Host, peers []peer.ID, timeout time.Duration) []PeerReachability {
+	results := make([]PeerReachability, len(peers))
+	var wg sync.WaitGroup
+	for i, el := range peers {
+		wg.Add(1)
+		go func(idx int, pID peer.ID) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			results[idx] = checkPeer(checkCtx, h, pID)
+		}(i, el)
+	}
+	wg.Wait()
+	return results
+}
+
+func checkPeer(ctx context.Context, h host.Host, pID peer.ID) PeerReachability {
+	result := PeerReachability{
+		PeerID: pID.String(),
+	}
+	// the local node is always reachable
+	if pID == h.ID() {
+		result.Reachable = true
+		result.RTT = time.Duration(0).String()
+		result.Addresses = addrsToStrings(h.Addrs())
+		return result
+	}
+	rtt, err := pingPeer(ctx, h, pID)
+	result.Addresses = peerAddrs(h, pID)
+	if agent, errAgent := h.Peerstore().Get(pID, "AgentVersion"); errAgent == nil {
+		if version, ok := agent.(string); ok {
+			result.AgentVersion = version
+		}
+	}
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Reachable = true
+	result.RTT = rtt.String()
+	return result
+}
+
+func pingPeer(ctx context.Context, h host.Host, pID peer.ID) (time.Duration, error) {
+	// the failed dial of an earlier session puts the peer in the backoff, we want to know whether it can be reached now
+	if sw, ok := h.Network().(*swarm.Swarm); ok {
+		sw.Backoff().Clear(pID)
+	}
+	// the addresses come from the peer store, which is filled by the DHT and the bootstrap nodes
+	if err := h.Connect(ctx, peer.AddrInfo{ID: pID}); err != nil {
+		return 0, err
+	}
+	pingCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	select {
+	case ret, ok := <-ping.Ping(pingCtx, h, pID):
+		if !ok {
+			return 0, errors.New("ping is aborted")
+		}
+		return ret.RTT, ret.Error
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// peerAddrs return the addresses of the peer in the peer store, and the ones of the live connections to the peer
+func peerAddrs(h host.Host, pID peer.ID) []string {
+	addrs := h.Peerstore().Addrs(pID)
+	for _, el := range h.Network().ConnsToPeer(pID) {
+		addrs = append(addrs, el.RemoteMultiaddr())
+	}
+	seen := make(map[string]bool)
+	var result []string
+	for _, el := range addrsToStrings(addrs) {
+		if !seen[el] {
+			seen[el] = true
+			result = append(result, el)
+		}
+	}
+	return result
+}
+
+func addrsToStrings(addrs []maddr.Multiaddr) []string {
+	result := make([]string, len(addrs))
+	for i, el := range addrs {
+		result[i] = el.String()
+	}
+	return result
+}
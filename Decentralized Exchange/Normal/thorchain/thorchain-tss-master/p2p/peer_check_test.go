@@ -0,0 +1,46 @@
+package p2p
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	tnet "github.com/libp2p/go-libp2p-testing/net"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPeers(t *testing.T) {
+	hosts := setupHosts(t, 3)
+	for _, el := range hosts {
+		ping.NewPingService(el)
+	}
+	unknownPeer := tnet.RandIdentityOrFatal(t).ID()
+	peers := []peer.ID{hosts[0].ID(), hosts[1].ID(), unknownPeer, hosts[2].ID()}
+
+	start := time.Now()
+	results := CheckPeers(context.Background(), hosts[0], peers, time.Second*2)
+	assert.True(t, time.Since(start) < time.Second*3)
+	assert.Len(t, results, len(peers))
+	for i, el := range results {
+		assert.Equal(t, peers[i].String(), el.PeerID)
+	}
+	// the local node
+	assert.True(t, results[0].Reachable)
+	assert.NotEmpty(t, results[0].Addresses)
+	for _, el := range []PeerReachability{results[1], results[3]} {
+		assert.True(t, el.Reachable, el.Error)
+		assert.NotEmpty(t, el.RTT)
+		assert.NotEmpty(t, el.Addresses)
+		assert.Empty(t, el.Error)
+	}
+	assert.False(t, results[2].Reachable)
+	assert.NotEmpty(t, results[2].Error)
+
+	// the cancelled check gives up straight away
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results = CheckPeers(ctx, hosts[1], []peer.ID{hosts[2].ID()}, time.Second*2)
+	assert.False(t, results[0].Reachable)
+}
@@ -11,6 +11,8 @@ type PeerStatus struct {
 	peersResponse  map[peer.ID]bool
 	peerStatusLock *sync.RWMutex
 	newFound       chan bool
+	refusedPeers   []peer.ID
+	refused        chan struct{}
 }
 
 func NewPeerStatus(peerNodes []peer.ID, myPeerID peer.ID) *PeerStatus {
@@ -25,6 +27,7 @@ func NewPeerStatus(peerNodes []peer.ID, myPeerID peer.ID) *PeerStatus {
 		peersResponse:  dat,
 		peerStatusLock: &sync.RWMutex{},
 		newFound:       make(chan bool, len(peerNodes)),
+		refused:        make(chan struct{}, 1),
 	}
 	return peerStatus
 }
@@ -63,3 +66,29 @@ func (ps *PeerStatus) updatePeer(peerNode peer.ID) (bool, error) {
 	}
 	return false, nil
 }
+
+// refusePeer mark the given peer as the one refusing to join the party
+func (ps *PeerStatus) refusePeer(peerNode peer.ID) error {
+	ps.peerStatusLock.Lock()
+	defer ps.peerStatusLock.Unlock()
+	if _, ok := ps.peersResponse[peerNode]; !ok {
+		return errors.New("key not found")
+	}
+	for _, el := range ps.refusedPeers {
+		if el == peerNode {
+			return nil
+		}
+	}
+	ps.refusedPeers = append(ps.refusedPeers, peerNode)
+	select {
+	case ps.refused <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (ps *PeerStatus) getRefusedPeers() []peer.ID {
+	ps.peerStatusLock.RLock()
+	defer ps.peerStatusLock.RUnlock()
+	return append([]peer.ID(nil), ps.refusedPeers...)
+}
@@ -0,0 +1,281 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+)
+
+// ErrRefused is returned when the keysign request breaks the policy of the pool
+var ErrRefused = errors.New("keysign refused by the local policy")
+
+// TimeWindow is the time of the day, in UTC, during which the pool signs, the window goes past the midnight when
+// the end is earlier than the start
+type TimeWindow struct {
+	Start string `json:"start"` // HH:MM
+	End   string `json:"end"`   // HH:MM
+}
+
+// Rule is the keysign policy of a pool, the zero value of each field means no limit
+type Rule struct {
+	MaxPerMinute     int          `json:"max_per_minute,omitempty"`
+	MaxPerDay        int          `json:"max_per_day,omitempty"`
+	Windows          []TimeWindow `json:"windows,omitempty"`
+	RequiredMetadata []string     `json:"required_metadata,omitempty"` // the keys the request metadata must carry
+	MessagePrefixes  []string     `json:"message_prefixes,omitempty"`  // hex, every message must start with one of them
+}
+
+// Rules is the content of the rules file, the pools are keyed by the pool pub key in bech32 or hex
+type Rules struct {
+	Default *Rule           `json:"default,omitempty"` // the rule of the pools not listed, they sign anything if it is nil
+	Pools   map[string]Rule `json:"pools,omitempty"`
+}
+
+// Request is the keysign request the policy is checked against
+type Request struct {
+	PoolPubKey string
+	Messages   [][]byte
+	Metadata   map[string]string
+}
+
+// LoadRules read the rules from the given json file, no rule at all if the file name is empty
+func LoadRules(fileName string) (Rules, error) {
+	if len(fileName) == 0 {
+		return Rules{}, nil
+	}
+	buf, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return Rules{}, fmt.Errorf("fail to read the policy file(%s): %w", fileName, err)
+	}
+	var rules Rules
+	if err := json.Unmarshal(buf, &rules); err != nil {
+		return Rules{}, fmt.Errorf("fail to unmarshal the policy file(%s): %w", fileName, err)
+	}
+	return rules, nil
+}
+
+type window struct {
+	start, end time.Duration
+}
+
+type rule struct {
+	Rule
+	windows  []window
+	prefixes [][]byte
+}
+
+// Engine decides whether the local node joins the keysign, it keeps the time of the signatures of the last day
+// to enforce the rate limits
+type Engine struct {
+	pools       map[string]*rule
+	defaultRule *rule
+	lock        *sync.Mutex
+	history     map[string][]time.Time
+	pending     map[string]int // the signatures reserved by the keysign still running
+	now         func() time.Time
+}
+
+// Reservation is the quota held by an allowed keysign till it is done, the keysign either commits the signatures
+// it produces or releases the quota
+type Reservation struct {
+	engine     *Engine
+	poolPubKey string
+	count      int
+	once       *sync.Once
+}
+
+// Commit count the given number of signatures towards the rate limits of the pool, the rest of the reserved
+// quota is given back. Only the first call of Commit or Release takes effect
+func (r *Reservation) Commit(signed int) {
+	if r == nil || r.engine == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.engine.settle(r.poolPubKey, r.count, signed)
+	})
+}
+
+// Release give the reserved quota back, the keysign is failed, refused or cancelled
+func (r *Reservation) Release() {
+	r.Commit(0)
+}
+
+// NewEngine create a new instance of Engine with the given rules, the pool keys are normalized with the given
+// bech32 prefix
+func NewEngine(rules Rules, bech32Prefix string) (*Engine, error) {
+	e := &Engine{
+		pools:   make(map[string]*rule),
+		lock:    &sync.Mutex{},
+		history: make(map[string][]time.Time),
+		pending: make(map[string]int),
+		now:     time.Now,
+	}
+	for pk, el := range rules.Pools {
+		poolPubKey, err := conversion.NormalizePubKey(pk, bech32Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pool pub key(%s): %w", pk, err)
+		}
+		r, err := newRule(el)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rule of pool(%s): %w", pk, err)
+		}
+		e.pools[poolPubKey] = r
+	}
+	if rules.Default != nil {
+		r, err := newRule(*rules.Default)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default rule: %w", err)
+		}
+		e.defaultRule = r
+	}
+	return e, nil
+}
+
+func newRule(r Rule) (*rule, error) {
+	if r.MaxPerMinute < 0 || r.MaxPerDay < 0 {
+		return nil, errors.New("negative rate limit")
+	}
+	result := &rule{Rule: r}
+	for _, el := range r.Windows {
+		start, err := parseTimeOfDay(el.Start)
+		if err != nil {
+			return nil, err
+		}
+		end, err := parseTimeOfDay(el.End)
+		if err != nil {
+			return nil, err
+		}
+		result.windows = append(result.windows, window{start: start, end: end})
+	}
+	for _, el := range r.MessagePrefixes {
+		prefix, err := hex.DecodeString(el)
+		if err != nil {
+			return nil, fmt.Errorf("invalid message prefix(%s): %w", el, err)
+		}
+		result.prefixes = append(result.prefixes, prefix)
+	}
+	return result, nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day(%s): %w", s, err)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Check whether the local node can sign the given request, the signatures of the allowed request are reserved
+// against the rate limits of the pool, the keysign has to commit or release the returned reservation once it is
+// done. The pool key of the request should be normalized already
+func (e *Engine) Check(req Request) (*Reservation, error) {
+	r, ok := e.pools[req.PoolPubKey]
+	if !ok {
+		r = e.defaultRule
+	}
+	if r == nil {
+		return &Reservation{}, nil
+	}
+	now := e.now().UTC()
+	if !r.inWindows(now) {
+		return nil, fmt.Errorf("%w: out of the time windows", ErrRefused)
+	}
+	for _, el := range r.RequiredMetadata {
+		if len(req.Metadata[el]) == 0 {
+			return nil, fmt.Errorf("%w: missing metadata(%s)", ErrRefused, el)
+		}
+	}
+	for _, msg := range req.Messages {
+		if !r.allowMessage(msg) {
+			return nil, fmt.Errorf("%w: message(%s) is not allowed", ErrRefused, hex.EncodeToString(msg))
+		}
+	}
+	if r.MaxPerMinute == 0 && r.MaxPerDay == 0 {
+		return &Reservation{}, nil
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	var lastDay []time.Time
+	lastMinute := 0
+	for _, el := range e.history[req.PoolPubKey] {
+		if now.Sub(el) < time.Hour*24 {
+			lastDay = append(lastDay, el)
+			if now.Sub(el) < time.Minute {
+				lastMinute++
+			}
+		}
+	}
+	e.history[req.PoolPubKey] = lastDay
+	// the signatures of the keysign still running count against both limits, they may all be produced
+	pending := e.pending[req.PoolPubKey]
+	if r.MaxPerMinute > 0 && lastMinute+pending+len(req.Messages) > r.MaxPerMinute {
+		return nil, fmt.Errorf("%w: more than %d signatures per minute", ErrRefused, r.MaxPerMinute)
+	}
+	if r.MaxPerDay > 0 && len(lastDay)+pending+len(req.Messages) > r.MaxPerDay {
+		return nil, fmt.Errorf("%w: more than %d signatures per day", ErrRefused, r.MaxPerDay)
+	}
+	e.pending[req.PoolPubKey] = pending + len(req.Messages)
+	return &Reservation{
+		engine:     e,
+		poolPubKey: req.PoolPubKey,
+		count:      len(req.Messages),
+		once:       &sync.Once{},
+	}, nil
+}
+
+// settle take the reserved signatures off the pending ones, and record the signed ones at the current time
+func (e *Engine) settle(poolPubKey string, reserved, signed int) {
+	if signed > reserved {
+		signed = reserved
+	}
+	now := e.now().UTC()
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.pending[poolPubKey] -= reserved
+	if e.pending[poolPubKey] <= 0 {
+		delete(e.pending, poolPubKey)
+	}
+	for i := 0; i < signed; i++ {
+		e.history[poolPubKey] = append(e.history[poolPubKey], now)
+	}
+}
+
+func (r *rule) inWindows(now time.Time) bool {
+	if len(r.windows) == 0 {
+		return true
+	}
+	h, m, s := now.Clock()
+	timeOfDay := time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second
+	for _, el := range r.windows {
+		if el.start <= el.end {
+			if timeOfDay >= el.start && timeOfDay < el.end {
+				return true
+			}
+			continue
+		}
+		if timeOfDay >= el.start || timeOfDay < el.end {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *rule) allowMessage(msg []byte) bool {
+	if len(r.prefixes) == 0 {
+		return true
+	}
+	for _, el := range r.prefixes {
+		if bytes.HasPrefix(msg, el) {
+			return true
+		}
+	}
+	return false
+}
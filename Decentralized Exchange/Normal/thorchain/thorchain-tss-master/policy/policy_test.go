@@ -0,0 +1,189 @@
+package policy
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+const testPoolPubKey = "thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3"
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type PolicyTestSuite struct{}
+
+var _ = Suite(&PolicyTestSuite{})
+
+func (s *PolicyTestSuite) TestLoadRules(c *C) {
+	rules, err := LoadRules("")
+	c.Assert(err, IsNil)
+	c.Assert(rules.Pools, HasLen, 0)
+	c.Assert(rules.Default, IsNil)
+
+	fileName := filepath.Join(os.TempDir(), "test_keysign_policy.json")
+	defer func() {
+		c.Assert(os.Remove(fileName), IsNil)
+	}()
+	content := `{
+  "default": {"max_per_day": 100},
+  "pools": {
+    "` + testPoolPubKey + `": {
+      "max_per_minute": 2,
+      "windows": [{"start": "08:00", "end": "20:00"}],
+      "required_metadata": ["tx_id"],
+      "message_prefixes": ["0a0b"]
+    }
+  }
+}`
+	c.Assert(ioutil.WriteFile(fileName, []byte(content), 0600), IsNil)
+	rules, err = LoadRules(fileName)
+	c.Assert(err, IsNil)
+	c.Assert(rules.Default.MaxPerDay, Equals, 100)
+	rule := rules.Pools[testPoolPubKey]
+	c.Assert(rule.MaxPerMinute, Equals, 2)
+	c.Assert(rule.Windows, DeepEquals, []TimeWindow{{Start: "08:00", End: "20:00"}})
+	c.Assert(rule.RequiredMetadata, DeepEquals, []string{"tx_id"})
+	c.Assert(rule.MessagePrefixes, DeepEquals, []string{"0a0b"})
+	_, err = NewEngine(rules, "")
+	c.Assert(err, IsNil)
+
+	c.Assert(ioutil.WriteFile(fileName, []byte("whatever"), 0600), IsNil)
+	_, err = LoadRules(fileName)
+	c.Assert(err, NotNil)
+	_, err = LoadRules(fileName + ".missing")
+	c.Assert(err, NotNil)
+}
+
+func (s *PolicyTestSuite) TestNewEngine(c *C) {
+	for _, rules := range []Rules{
+		{Pools: map[string]Rule{"whatever": {}}},
+		{Pools: map[string]Rule{testPoolPubKey: {MaxPerMinute: -1}}},
+		{Pools: map[string]Rule{testPoolPubKey: {Windows: []TimeWindow{{Start: "8am", End: "20:00"}}}}},
+		{Pools: map[string]Rule{testPoolPubKey: {MessagePrefixes: []string{"whatever"}}}},
+		{Default: &Rule{Windows: []TimeWindow{{Start: "08:00", End: "25:00"}}}},
+	} {
+		_, err := NewEngine(rules, "")
+		c.Assert(err, NotNil)
+	}
+}
+
+func (s *PolicyTestSuite) TestCheck(c *C) {
+	e, err := NewEngine(Rules{
+		Pools: map[string]Rule{
+			testPoolPubKey: {
+				MaxPerMinute:     1,
+				MaxPerDay:        3,
+				Windows:          []TimeWindow{{Start: "08:00", End: "12:00"}, {Start: "22:00", End: "02:00"}},
+				RequiredMetadata: []string{"tx_id"},
+				MessagePrefixes:  []string{"0a0b", "ff"},
+			},
+		},
+	}, "")
+	c.Assert(err, IsNil)
+	now := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	req := Request{
+		PoolPubKey: testPoolPubKey,
+		Messages:   [][]byte{{0x0a, 0x0b, 0x01}},
+		Metadata:   map[string]string{"tx_id": "whatever"},
+	}
+	assertRefused := func(req Request) {
+		_, err := e.Check(req)
+		c.Assert(errors.Is(err, ErrRefused), Equals, true)
+	}
+	assertSigned := func(req Request) {
+		reservation, err := e.Check(req)
+		c.Assert(err, IsNil)
+		reservation.Commit(len(req.Messages))
+	}
+
+	assertSigned(req)
+	// the pool without a rule signs anything
+	assertSigned(Request{PoolPubKey: "whatever"})
+	assertRefused(Request{PoolPubKey: testPoolPubKey, Messages: req.Messages})
+	assertRefused(Request{PoolPubKey: testPoolPubKey, Messages: [][]byte{{0x0a}}, Metadata: req.Metadata})
+	// one of the batch is not allowed
+	assertRefused(Request{PoolPubKey: testPoolPubKey, Messages: [][]byte{{0xff}, {0x01}}, Metadata: req.Metadata})
+
+	// out of the time windows, the second one goes past the midnight
+	now = time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	assertRefused(req)
+	now = time.Date(2020, 1, 2, 1, 0, 0, 0, time.UTC)
+	assertSigned(req)
+	// the refused requests are not counted, one signature per minute
+	now = now.Add(time.Second * 30)
+	assertRefused(req)
+	now = now.Add(time.Second * 30)
+	assertSigned(req)
+	// three signatures per day
+	now = now.Add(time.Minute * 30)
+	assertRefused(req)
+	now = time.Date(2020, 1, 2, 9, 0, 1, 0, time.UTC)
+	assertSigned(req)
+}
+
+func (s *PolicyTestSuite) TestReservation(c *C) {
+	e, err := NewEngine(Rules{
+		Pools: map[string]Rule{
+			testPoolPubKey: {MaxPerMinute: 2, MaxPerDay: 3},
+		},
+	}, "")
+	c.Assert(err, IsNil)
+	now := time.Date(2020, 1, 1, 9, 0, 0, 0, time.UTC)
+	e.now = func() time.Time { return now }
+	req := Request{PoolPubKey: testPoolPubKey, Messages: [][]byte{{0x01}, {0x02}}}
+
+	// the keysign still running holds its quota
+	reservation, err := e.Check(req)
+	c.Assert(err, IsNil)
+	_, err = e.Check(Request{PoolPubKey: testPoolPubKey, Messages: [][]byte{{0x03}}})
+	c.Assert(errors.Is(err, ErrRefused), Equals, true)
+	// the failed keysign gives it back, releasing twice doesn't count
+	reservation.Release()
+	reservation.Release()
+	reservation, err = e.Check(req)
+	c.Assert(err, IsNil)
+	// only one of the batch is signed
+	reservation.Commit(1)
+	reservation.Release()
+	now = now.Add(time.Minute)
+	reservation, err = e.Check(req)
+	c.Assert(err, IsNil)
+	reservation.Commit(2)
+	_, err = e.Check(Request{PoolPubKey: testPoolPubKey, Messages: [][]byte{{0x03}}})
+	c.Assert(errors.Is(err, ErrRefused), Equals, true)
+	c.Assert(e.pending, HasLen, 0)
+
+	// the pool without a limit hands out a reservation that does nothing
+	reservation, err = e.Check(Request{PoolPubKey: "whatever"})
+	c.Assert(err, IsNil)
+	reservation.Commit(1)
+	c.Assert(e.history["whatever"], HasLen, 0)
+}
+
+func (s *PolicyTestSuite) TestDefaultRule(c *C) {
+	e, err := NewEngine(Rules{
+		Default: &Rule{MaxPerMinute: 2},
+		Pools: map[string]Rule{
+			testPoolPubKey: {},
+		},
+	}, "")
+	c.Assert(err, IsNil)
+	otherPool := Request{PoolPubKey: "whatever", Messages: [][]byte{{0x01}, {0x02}}}
+	reservation, err := e.Check(otherPool)
+	c.Assert(err, IsNil)
+	reservation.Commit(2)
+	_, err = e.Check(otherPool)
+	c.Assert(errors.Is(err, ErrRefused), Equals, true)
+	// the pool with its own rule is not limited by the default one
+	for i := 0; i < 3; i++ {
+		reservation, err := e.Check(Request{PoolPubKey: testPoolPubKey, Messages: otherPool.Messages})
+		c.Assert(err, IsNil)
+		reservation.Commit(2)
+	}
+}
@@ -0,0 +1,24 @@
+package reshare
+
+// Request request to move the key of a pool to a new committee, the pool public key stays the same
+type Request struct {
+	PoolPubKey   string   `json:"pool_pub_key"`
+	OldPartyKeys []string `json:"old_party_keys"`
+	NewPartyKeys []string `json:"new_party_keys"`
+	// NewThreshold is optional, the keysign of the new committee needs NewThreshold+1 signers, when it is not
+	// given, the default ceil(2n/3)-1 is used
+	NewThreshold int `json:"new_threshold,omitempty"`
+	// BlockHeight is optional, it tells the sessions of the same request apart, like the retry of a failed reshare, all
+	// the parties have to use the same one
+	BlockHeight int64 `json:"block_height,omitempty"`
+}
+
+// NewRequest create a new instance of reshare.Request
+func NewRequest(poolPubKey string, oldPartyKeys, newPartyKeys []string, newThreshold int) Request {
+	return Request{
+		PoolPubKey:   poolPubKey,
+		OldPartyKeys: oldPartyKeys,
+		NewPartyKeys: newPartyKeys,
+		NewThreshold: newThreshold,
+	}
+}
@@ -0,0 +1,372 @@
+package reshare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	bcrypto "github.com/binance-chain/tss-lib/crypto"
+	bkg "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/binance-chain/tss-lib/ecdsa/resharing"
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	tcrypto "github.com/tendermint/tendermint/crypto"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+const (
+	oldPartyIDPrefix = "old-"
+	newPartyIDPrefix = "new-"
+)
+
+type TssReshare struct {
+	logger          zerolog.Logger
+	localNodePubKey string
+	preParams       *bkg.LocalPreParams
+	tssCommonStruct *common.TssCommon
+	stopChan        chan struct{} // channel to indicate whether we should stop
+	stateManager    storage.LocalStateManager
+	commStopChan    chan struct{}
+	p2pComm         *p2p.Communication
+	msgID           string
+}
+
+// NewTssReshare create a new instance of TssReshare, the pre-parameters are only needed when the local node is in
+// the new committee
+func NewTssReshare(localP2PID string,
+	conf common.TssConfig,
+	localNodePubKey string,
+	broadcastChan chan *messages.BroadcastMsgChan,
+	stopChan chan struct{},
+	preParam *bkg.LocalPreParams,
+	msgID string,
+	stateManager storage.LocalStateManager,
+	privateKey tcrypto.PrivKey,
+	p2pComm *p2p.Communication) *TssReshare {
+	return &TssReshare{
+		logger: log.With().
+			Str("module", "reshare").
+			Str("msgID", msgID).Logger(),
+		localNodePubKey: localNodePubKey,
+		preParams:       preParam,
+		tssCommonStruct: common.NewTssCommon(localP2PID, broadcastChan, conf, msgID, privateKey),
+		stopChan:        stopChan,
+		stateManager:    stateManager,
+		commStopChan:    make(chan struct{}),
+		p2pComm:         p2pComm,
+		msgID:           msgID,
+	}
+}
+
+func (tReshare *TssReshare) GetTssReshareChannels() chan *p2p.Message {
+	return tReshare.tssCommonStruct.TssMsg
+}
+
+func (tReshare *TssReshare) GetTssCommonStruct() *common.TssCommon {
+	return tReshare.tssCommonStruct
+}
+
+// Reshare move the key of the pool to the new committee, the given local state is the key share of the local node,
+// it is only needed when the local node is in the old committee
+func (tReshare *TssReshare) Reshare(ctx context.Context, req Request, localState *storage.KeygenLocalState) (*bcrypto.ECPoint, error) {
+	newThreshold := req.NewThreshold
+	if newThreshold == 0 {
+		var err error
+		newThreshold, err = common.GetThreshold(len(req.NewPartyKeys))
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := common.ValidateThreshold(newThreshold, len(req.NewPartyKeys)); err != nil {
+		return nil, err
+	}
+
+	// the old committee has to use the party keys their shares were created for, the nodes that are only in
+	// the new committee never use the keys of the old committee, so they just go with the untagged ones
+	var oldTag int64
+	oldThreshold := len(req.OldPartyKeys) - 1
+	if localState != nil {
+		if localState.LocalData.ShareID == nil {
+			return nil, errors.New("invalid local state")
+		}
+		oldTag = conversion.GetPartyKeyTag(localState.LocalData.ShareID)
+		threshold, err := localState.GetThreshold()
+		if err != nil {
+			return nil, err
+		}
+		if len(req.OldPartyKeys) <= threshold {
+			return nil, fmt.Errorf("not enough parties in the old committee, threshold=%d and parties=%d", threshold, len(req.OldPartyKeys))
+		}
+		if !isSubset(req.OldPartyKeys, localState.ParticipantKeys) {
+			return nil, errors.New("the old committee is not part of the pool")
+		}
+		oldThreshold = threshold
+	}
+	newTag := getNewCommitteeTag(tReshare.msgID)
+	if newTag == oldTag {
+		return nil, errors.New("the pool has been reshared with the same request")
+	}
+
+	oldPartiesID, localOldPartyID, err := conversion.GetCommitteeParties(req.OldPartyKeys, tReshare.localNodePubKey, oldPartyIDPrefix, oldTag)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the old committee: %w", err)
+	}
+	newPartiesID, localNewPartyID, err := conversion.GetCommitteeParties(req.NewPartyKeys, tReshare.localNodePubKey, newPartyIDPrefix, newTag)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get the new committee: %w", err)
+	}
+	if localOldPartyID != nil && localState == nil {
+		return nil, errors.New("the local state is needed by the old committee")
+	}
+	if localOldPartyID == nil && localNewPartyID == nil {
+		return nil, errors.New("local party is not in the committees")
+	}
+	if localNewPartyID != nil && tReshare.preParams == nil {
+		tReshare.logger.Error().Msg("error, empty pre-parameters")
+		return nil, errors.New("error, empty pre-parameters")
+	}
+
+	_, releaseCurve, err := common.AcquireCurve(ctx, common.ECDSA)
+	if err != nil {
+		return nil, fmt.Errorf("fail to set the curve of %s: %w", common.ECDSA, err)
+	}
+	defer releaseCurve()
+	oldPeerCtx := btss.NewPeerContext(oldPartiesID)
+	newPeerCtx := btss.NewPeerContext(newPartiesID)
+	partyCount := len(oldPartiesID) + len(newPartiesID)
+	outCh := make(chan btss.Message, partyCount*2)
+	// the channel of the party we do not have is left nil, so it is never selected
+	var oldEndCh, newEndCh chan bkg.LocalPartySaveData
+	var localParties []btss.Party
+	if localOldPartyID != nil {
+		params := btss.NewReSharingParameters(oldPeerCtx, newPeerCtx, localOldPartyID, len(oldPartiesID), oldThreshold, len(newPartiesID), newThreshold)
+		oldEndCh = make(chan bkg.LocalPartySaveData, 1)
+		localParties = append(localParties, resharing.NewLocalParty(params, localState.LocalData, outCh, oldEndCh))
+	}
+	if localNewPartyID != nil {
+		params := btss.NewReSharingParameters(oldPeerCtx, newPeerCtx, localNewPartyID, len(oldPartiesID), oldThreshold, len(newPartiesID), newThreshold)
+		newEndCh = make(chan bkg.LocalPartySaveData, 1)
+		save := bkg.NewLocalPartySaveData(len(newPartiesID))
+		save.LocalPreParams = *tReshare.preParams
+		localParties = append(localParties, resharing.NewLocalParty(params, save, outCh, newEndCh))
+	}
+
+	blameMgr := tReshare.tssCommonStruct.GetBlameMgr()
+	partyIDMap := conversion.SetupPartyIDMap(oldPartiesID)
+	for _, el := range newPartiesID {
+		partyIDMap[el.Id] = el
+	}
+	err1 := conversion.SetupIDMaps(partyIDMap, tReshare.tssCommonStruct.PartyIDtoP2PID)
+	err2 := conversion.SetupIDMaps(partyIDMap, blameMgr.PartyIDtoP2PID)
+	if err1 != nil || err2 != nil {
+		tReshare.logger.Error().Msgf("error in creating mapping between partyID and P2P ID")
+		return nil, errors.New("fail to create mapping between partyID and P2P ID")
+	}
+	partyInfo := &common.PartyInfo{
+		Party:      localParties[0],
+		PartyIDMap: partyIDMap,
+	}
+	if len(localParties) > 1 {
+		partyInfo.NewCommitteeParty = localParties[1]
+	}
+	tReshare.tssCommonStruct.SetPartyInfo(partyInfo)
+	blameMgr.SetPartyInfo(localParties[0], partyIDMap)
+	tReshare.tssCommonStruct.P2PPeers = getPeers(tReshare.tssCommonStruct.PartyIDtoP2PID, tReshare.tssCommonStruct.GetLocalPeerID())
+
+	// start the parties of the local node before we process any message, a party of the new committee does not
+	// look at the messages it receives before it starts until the next message comes in
+	for _, party := range localParties {
+		if err := party.Start(); nil != err {
+			tReshare.logger.Error().Err(err).Msg("fail to start reshare party")
+			return nil, fmt.Errorf("fail to start reshare party: %w", err)
+		}
+		tReshare.logger.Info().Msgf("reshare party %s started", party.PartyID().Id)
+	}
+	var reshareWg sync.WaitGroup
+	reshareWg.Add(1)
+	go tReshare.tssCommonStruct.ProcessInboundMessages(tReshare.commStopChan, &reshareWg)
+
+	r, err := tReshare.processReshare(ctx, req, localState, outCh, oldEndCh, newEndCh, localParties, newThreshold)
+	if err != nil {
+		close(tReshare.commStopChan)
+		return nil, fmt.Errorf("fail to process reshare: %w", err)
+	}
+	select {
+	case <-time.After(time.Second * 5):
+		close(tReshare.commStopChan)
+
+	case <-tReshare.tssCommonStruct.GetTaskDone():
+		close(tReshare.commStopChan)
+	}
+
+	reshareWg.Wait()
+	return r, err
+}
+
+func (tReshare *TssReshare) processReshare(ctx context.Context,
+	req Request,
+	localState *storage.KeygenLocalState,
+	outCh <-chan btss.Message,
+	oldEndCh, newEndCh <-chan bkg.LocalPartySaveData,
+	localParties []btss.Party,
+	newThreshold int) (_ *bcrypto.ECPoint, err error) {
+	timer := monitor.NewSessionTimer(monitor.OpReshare)
+	defer func() {
+		timer.Done(monitor.ResultOf(err))
+	}()
+	defer tReshare.logger.Info().Msg("finished reshare process")
+	tReshare.logger.Info().Msg("start to read messages from local party")
+	tssConf := tReshare.tssCommonStruct.GetConf()
+	blameMgr := tReshare.tssCommonStruct.GetBlameMgr()
+	var pubKey *bcrypto.ECPoint
+	if localState != nil {
+		pubKey = localState.LocalData.ECDSAPub
+	}
+	// the node in both committees overwrites its old share with the new one, the node leaving the pool retires it
+	leaving := oldEndCh != nil && newEndCh == nil
+	for {
+		if oldEndCh == nil && newEndCh == nil {
+			if err := tReshare.tssCommonStruct.NotifyTaskDone(); err != nil {
+				tReshare.logger.Error().Err(err).Msg("fail to broadcast the reshare done")
+			}
+			return pubKey, nil
+		}
+		select {
+		case <-tReshare.stopChan: // when TSS processor receive signal to quit
+			return nil, errors.New("received exit signal")
+
+		case <-ctx.Done(): // when the caller cancel this reshare
+			tReshare.logger.Info().Msg("reshare is cancelled")
+			return nil, ctx.Err()
+
+		case <-time.After(tssConf.KeyGenTimeout):
+			// we bail out after KeyGenTimeoutSeconds
+			tReshare.logger.Error().Msgf("fail to reshare with %s", tssConf.KeyGenTimeout.String())
+			// the messages of a resharing round only come from one of the committees, so we blame the parties
+			// the local parties are still waiting for
+			var waitingFor []*btss.PartyID
+			for _, party := range localParties {
+				waitingFor = append(waitingFor, party.WaitingFor()...)
+			}
+			blameNodes, err := blameMgr.GetWaitingForBlame(waitingFor)
+			if err != nil {
+				tReshare.logger.Error().Err(err).Msg("error in get the blame nodes")
+			}
+			lastMsg := blameMgr.GetLastMsg()
+			isUnicast := lastMsg != nil && lastMsg.Type() == messages.RESHARE3aUnicast
+			blameMgr.GetBlame().SetBlame(blame.TssTimeout, blameNodes, isUnicast)
+			return nil, blame.ErrTssTimeOut
+
+		case msg := <-outCh:
+			tReshare.logger.Debug().Msgf(">>>>>>>>>>msg: %s", msg.String())
+			timer.Round(msg.Type())
+			blameMgr.SetLastMsg(msg)
+			err := tReshare.tssCommonStruct.ProcessOutCh(msg, messages.TSSReshareMsg)
+			if err != nil {
+				tReshare.logger.Error().Err(err).Msg("fail to process the message")
+				return nil, err
+			}
+
+		case <-oldEndCh:
+			// the old committee party only ends once every party of the new committee acknowledges its new share
+			tReshare.logger.Debug().Msg("old committee party finished")
+			oldEndCh = nil
+			if leaving {
+				if err := tReshare.retireLocalState(*localState); err != nil {
+					return nil, err
+				}
+			}
+
+		case msg := <-newEndCh:
+			tReshare.logger.Debug().Msgf("reshare finished successfully: %s", msg.ECDSAPub.Y().String())
+			poolPubKey, _, err := conversion.GetTssPubKey(msg.ECDSAPub, tReshare.tssCommonStruct.GetConf().Bech32Prefix)
+			if err != nil {
+				return nil, fmt.Errorf("fail to get thorchain pubkey: %w", err)
+			}
+			if poolPubKey != req.PoolPubKey {
+				return nil, fmt.Errorf("the reshared pool pubkey(%s) does not match the request", poolPubKey)
+			}
+			keygenLocalStateItem := storage.KeygenLocalState{
+				PubKey:          poolPubKey,
+				LocalData:       msg,
+				ParticipantKeys: req.NewPartyKeys,
+				LocalPartyKey:   tReshare.localNodePubKey,
+			}
+			if req.NewThreshold != 0 {
+				keygenLocalStateItem.Threshold = newThreshold
+			}
+			if err := tReshare.stateManager.SaveLocalState(keygenLocalStateItem); err != nil {
+				return nil, fmt.Errorf("fail to save reshare result to storage: %w", err)
+			}
+			address := tReshare.p2pComm.ExportPeerAddress()
+			if err := tReshare.stateManager.SaveAddressBook(address); err != nil {
+				tReshare.logger.Error().Err(err).Msg("fail to save the peer addresses")
+			}
+			pubKey = msg.ECDSAPub
+			newEndCh = nil
+		}
+	}
+}
+
+// retireLocalState wipe the secret share and the pre-parameters of the given local state and save it as retired, so
+// the nodes removed from the pool can't sign with the pool key any more, the public part is kept for the operator
+func (tReshare *TssReshare) retireLocalState(state storage.KeygenLocalState) error {
+	state.State = storage.KeyRetired
+	state.LocalData.Xi = nil
+	state.LocalData.LocalPreParams = bkg.LocalPreParams{}
+	if err := tReshare.stateManager.SaveLocalState(state); err != nil {
+		return fmt.Errorf("fail to retire the old key share: %w", err)
+	}
+	tReshare.logger.Info().Str("pool pub key", state.PubKey).Msg("the old key share is retired")
+	return nil
+}
+
+// getNewCommitteeTag return the tag of the party keys of the new committee, it is derived from the msgID, so all the
+// parties agree on it, and it is never 0, which is the tag of the keys created in keygen
+func getNewCommitteeTag(msgID string) int64 {
+	hash := sha256.Sum256([]byte(msgID))
+	return int64(binary.BigEndian.Uint32(hash[:4])) + 1
+}
+
+func isSubset(keys, allKeys []string) bool {
+	for _, key := range keys {
+		found := false
+		for _, el := range allKeys {
+			if el == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// getPeers return the peers of the other nodes, a node in both committees has two party IDs but only one peer
+func getPeers(partyIDtoP2PID map[string]peer.ID, localPeerID string) []peer.ID {
+	added := make(map[peer.ID]bool)
+	var peerIDs []peer.ID
+	for _, el := range conversion.GetPeersID(partyIDtoP2PID, localPeerID) {
+		if added[el] {
+			continue
+		}
+		added[el] = true
+		peerIDs = append(peerIDs, el)
+	}
+	return peerIDs
+}
@@ -0,0 +1,75 @@
+package reshare
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+var testPubKeys = []string{
+	"thorpub1addwnpepq2ryyje5zr09lq7gqptjwnxqsy2vcdngvwd6z7yt5yjcnyj8c8cn559xe69",
+	"thorpub1addwnpepqfjcw5l4ay5t00c32mmlky7qrppepxzdlkcwfs2fd5u73qrwna0vzag3y4j",
+	"thorpub1addwnpepqtdklw8tf3anjz7nn5fly3uvq2e67w2apn560s4smmrt9e3x52nt2svmmu3",
+	"thorpub1addwnpepqtspqyy6gk22u37ztra4hq3hdakc0w0k60sfy849mlml2vrpfr0wvm6uz09",
+}
+
+func TestPackage(t *testing.T) { TestingT(t) }
+
+type TssReshareTestSuite struct{}
+
+var _ = Suite(&TssReshareTestSuite{})
+
+func (s *TssReshareTestSuite) SetUpSuite(c *C) {
+	conversion.SetupBech32Prefix()
+}
+
+func (s *TssReshareTestSuite) TestGetNewCommitteeTag(c *C) {
+	tag := getNewCommitteeTag("hello")
+	c.Assert(tag, Not(Equals), int64(0))
+	c.Assert(getNewCommitteeTag("hello"), Equals, tag)
+	c.Assert(getNewCommitteeTag("world"), Not(Equals), tag)
+}
+
+func (s *TssReshareTestSuite) TestIsSubset(c *C) {
+	c.Assert(isSubset(testPubKeys[:2], testPubKeys), Equals, true)
+	c.Assert(isSubset(nil, testPubKeys), Equals, true)
+	c.Assert(isSubset(testPubKeys, testPubKeys[1:]), Equals, false)
+}
+
+func (s *TssReshareTestSuite) TestReshareInvalidRequest(c *C) {
+	newInstance := func(localNodePubKey string) *TssReshare {
+		return NewTssReshare("", common.TssConfig{}, localNodePubKey, nil, make(chan struct{}), nil, "whatever", nil, nil, nil)
+	}
+	// the new threshold is too high
+	req := NewRequest("pool", testPubKeys[:3], testPubKeys[1:], 3)
+	_, err := newInstance(testPubKeys[1]).Reshare(context.Background(), req, nil)
+	c.Assert(err, NotNil)
+
+	// the local node is not in the committees
+	req = NewRequest("pool", testPubKeys[1:3], testPubKeys[2:], 0)
+	_, err = newInstance(testPubKeys[0]).Reshare(context.Background(), req, nil)
+	c.Assert(err, NotNil)
+
+	// the old committee can't reshare without the local state
+	req = NewRequest("pool", testPubKeys[:3], testPubKeys[1:], 0)
+	_, err = newInstance(testPubKeys[0]).Reshare(context.Background(), req, nil)
+	c.Assert(err, NotNil)
+
+	// the new committee can't reshare without the pre-parameters
+	_, err = newInstance(testPubKeys[3]).Reshare(context.Background(), req, nil)
+	c.Assert(err, NotNil)
+
+	// the old committee has to be part of the pool
+	localState := &storage.KeygenLocalState{
+		ParticipantKeys: testPubKeys[1:],
+	}
+	localState.LocalData.ShareID = big.NewInt(1)
+	_, err = newInstance(testPubKeys[0]).Reshare(context.Background(), req, localState)
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1 @@
+protoc --go_out=plugins=grpc:. *.proto
\ No newline at end of file
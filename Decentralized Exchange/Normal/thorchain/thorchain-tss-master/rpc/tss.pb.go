@@ -0,0 +1,1848 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: tss.proto
+
+package rpc
+
+import (
+	context "context"
+	fmt "fmt"
+	math "math"
+
+	proto "github.com/golang/protobuf/proto"
+	wrappers "github.com/golang/protobuf/ptypes/wrappers"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = proto.Marshal
+	_ = fmt.Errorf
+	_ = math.Inf
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Status has the same values as the status of the tss server
+type Status int32
+
+const (
+	Status_NA        Status = 0
+	Status_Success   Status = 1
+	Status_Fail      Status = 2
+	Status_Cancelled Status = 3
+	Status_Rejected  Status = 4
+)
+
+var Status_name = map[int32]string{
+	0: "NA",
+	1: "Success",
+	2: "Fail",
+	3: "Cancelled",
+	4: "Rejected",
+}
+
+var Status_value = map[string]int32{
+	"NA":        0,
+	"Success":   1,
+	"Fail":      2,
+	"Cancelled": 3,
+	"Rejected":  4,
+}
+
+func (x Status) String() string {
+	return proto.EnumName(Status_name, int32(x))
+}
+
+func (Status) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{0}
+}
+
+type ProgressEvent_Type int32
+
+const (
+	ProgressEvent_Unknown        ProgressEvent_Type = 0
+	ProgressEvent_PartyJoined    ProgressEvent_Type = 1
+	ProgressEvent_RoundStarted   ProgressEvent_Type = 2
+	ProgressEvent_ShareRequested ProgressEvent_Type = 3
+	ProgressEvent_Finished       ProgressEvent_Type = 4
+)
+
+var ProgressEvent_Type_name = map[int32]string{
+	0: "Unknown",
+	1: "PartyJoined",
+	2: "RoundStarted",
+	3: "ShareRequested",
+	4: "Finished",
+}
+
+var ProgressEvent_Type_value = map[string]int32{
+	"Unknown":        0,
+	"PartyJoined":    1,
+	"RoundStarted":   2,
+	"ShareRequested": 3,
+	"Finished":       4,
+}
+
+func (x ProgressEvent_Type) String() string {
+	return proto.EnumName(ProgressEvent_Type_name, int32(x))
+}
+
+func (ProgressEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{2, 0}
+}
+
+type BlameNode struct {
+	Pubkey               string   `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Signature            []byte   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlameNode) Reset()         { *m = BlameNode{} }
+func (m *BlameNode) String() string { return proto.CompactTextString(m) }
+func (*BlameNode) ProtoMessage()    {}
+func (*BlameNode) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{0}
+}
+
+func (m *BlameNode) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlameNode.Unmarshal(m, b)
+}
+func (m *BlameNode) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlameNode.Marshal(b, m, deterministic)
+}
+func (m *BlameNode) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlameNode.Merge(m, src)
+}
+func (m *BlameNode) XXX_Size() int {
+	return xxx_messageInfo_BlameNode.Size(m)
+}
+func (m *BlameNode) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlameNode.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlameNode proto.InternalMessageInfo
+
+func (m *BlameNode) GetPubkey() string {
+	if m != nil {
+		return m.Pubkey
+	}
+	return ""
+}
+
+func (m *BlameNode) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *BlameNode) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+type Blame struct {
+	FailReason           string       `protobuf:"bytes,1,opt,name=fail_reason,json=failReason,proto3" json:"fail_reason,omitempty"`
+	IsUnicast            bool         `protobuf:"varint,2,opt,name=is_unicast,json=isUnicast,proto3" json:"is_unicast,omitempty"`
+	BlameNodes           []*BlameNode `protobuf:"bytes,3,rep,name=blame_nodes,json=blameNodes,proto3" json:"blame_nodes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Blame) Reset()         { *m = Blame{} }
+func (m *Blame) String() string { return proto.CompactTextString(m) }
+func (*Blame) ProtoMessage()    {}
+func (*Blame) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{1}
+}
+
+func (m *Blame) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Blame.Unmarshal(m, b)
+}
+func (m *Blame) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Blame.Marshal(b, m, deterministic)
+}
+func (m *Blame) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Blame.Merge(m, src)
+}
+func (m *Blame) XXX_Size() int {
+	return xxx_messageInfo_Blame.Size(m)
+}
+func (m *Blame) XXX_DiscardUnknown() {
+	xxx_messageInfo_Blame.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Blame proto.InternalMessageInfo
+
+func (m *Blame) GetFailReason() string {
+	if m != nil {
+		return m.FailReason
+	}
+	return ""
+}
+
+func (m *Blame) GetIsUnicast() bool {
+	if m != nil {
+		return m.IsUnicast
+	}
+	return false
+}
+
+func (m *Blame) GetBlameNodes() []*BlameNode {
+	if m != nil {
+		return m.BlameNodes
+	}
+	return nil
+}
+
+type ProgressEvent struct {
+	Type                 ProgressEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=rpc.ProgressEvent_Type" json:"type,omitempty"`
+	Round                int32              `protobuf:"varint,2,opt,name=round,proto3" json:"round,omitempty"`
+	RoundName            string             `protobuf:"bytes,3,opt,name=round_name,json=roundName,proto3" json:"round_name,omitempty"`
+	Peer                 string             `protobuf:"bytes,4,opt,name=peer,proto3" json:"peer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *ProgressEvent) Reset()         { *m = ProgressEvent{} }
+func (m *ProgressEvent) String() string { return proto.CompactTextString(m) }
+func (*ProgressEvent) ProtoMessage()    {}
+func (*ProgressEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{2}
+}
+
+func (m *ProgressEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProgressEvent.Unmarshal(m, b)
+}
+func (m *ProgressEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProgressEvent.Marshal(b, m, deterministic)
+}
+func (m *ProgressEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProgressEvent.Merge(m, src)
+}
+func (m *ProgressEvent) XXX_Size() int {
+	return xxx_messageInfo_ProgressEvent.Size(m)
+}
+func (m *ProgressEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProgressEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProgressEvent proto.InternalMessageInfo
+
+func (m *ProgressEvent) GetType() ProgressEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return ProgressEvent_Unknown
+}
+
+func (m *ProgressEvent) GetRound() int32 {
+	if m != nil {
+		return m.Round
+	}
+	return 0
+}
+
+func (m *ProgressEvent) GetRoundName() string {
+	if m != nil {
+		return m.RoundName
+	}
+	return ""
+}
+
+func (m *ProgressEvent) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+// PubKeyInfo is the pool key in the encodings the chains use, the BTC and ETH addresses are only set for the secp256k1 key
+type PubKeyInfo struct {
+	PubKeyCompressed     string   `protobuf:"bytes,1,opt,name=pub_key_compressed,json=pubKeyCompressed,proto3" json:"pub_key_compressed,omitempty"`
+	PubKeyUncompressed   string   `protobuf:"bytes,2,opt,name=pub_key_uncompressed,json=pubKeyUncompressed,proto3" json:"pub_key_uncompressed,omitempty"`
+	BtcP2Wpkh            string   `protobuf:"bytes,3,opt,name=btc_p2wpkh,json=btcP2wpkh,proto3" json:"btc_p2wpkh,omitempty"`
+	BtcP2Pkh             string   `protobuf:"bytes,4,opt,name=btc_p2pkh,json=btcP2pkh,proto3" json:"btc_p2pkh,omitempty"`
+	Eth                  string   `protobuf:"bytes,5,opt,name=eth,proto3" json:"eth,omitempty"`
+	Cosmos               string   `protobuf:"bytes,6,opt,name=cosmos,proto3" json:"cosmos,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PubKeyInfo) Reset()         { *m = PubKeyInfo{} }
+func (m *PubKeyInfo) String() string { return proto.CompactTextString(m) }
+func (*PubKeyInfo) ProtoMessage()    {}
+func (*PubKeyInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{3}
+}
+
+func (m *PubKeyInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PubKeyInfo.Unmarshal(m, b)
+}
+func (m *PubKeyInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PubKeyInfo.Marshal(b, m, deterministic)
+}
+func (m *PubKeyInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PubKeyInfo.Merge(m, src)
+}
+func (m *PubKeyInfo) XXX_Size() int {
+	return xxx_messageInfo_PubKeyInfo.Size(m)
+}
+func (m *PubKeyInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_PubKeyInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PubKeyInfo proto.InternalMessageInfo
+
+func (m *PubKeyInfo) GetPubKeyCompressed() string {
+	if m != nil {
+		return m.PubKeyCompressed
+	}
+	return ""
+}
+
+func (m *PubKeyInfo) GetPubKeyUncompressed() string {
+	if m != nil {
+		return m.PubKeyUncompressed
+	}
+	return ""
+}
+
+func (m *PubKeyInfo) GetBtcP2Wpkh() string {
+	if m != nil {
+		return m.BtcP2Wpkh
+	}
+	return ""
+}
+
+func (m *PubKeyInfo) GetBtcP2Pkh() string {
+	if m != nil {
+		return m.BtcP2Pkh
+	}
+	return ""
+}
+
+func (m *PubKeyInfo) GetEth() string {
+	if m != nil {
+		return m.Eth
+	}
+	return ""
+}
+
+func (m *PubKeyInfo) GetCosmos() string {
+	if m != nil {
+		return m.Cosmos
+	}
+	return ""
+}
+
+// RoundSummary tells how a round of the session goes, the times are the milliseconds since the start of the session
+type RoundSummary struct {
+	Round                string   `protobuf:"bytes,1,opt,name=round,proto3" json:"round,omitempty"`
+	LocalMs              int64    `protobuf:"varint,2,opt,name=local_ms,json=localMs,proto3" json:"local_ms,omitempty"`
+	Received             int32    `protobuf:"varint,3,opt,name=received,proto3" json:"received,omitempty"`
+	Confirmed            int32    `protobuf:"varint,4,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	LastPeerMs           int64    `protobuf:"varint,5,opt,name=last_peer_ms,json=lastPeerMs,proto3" json:"last_peer_ms,omitempty"`
+	LastPeer             string   `protobuf:"bytes,6,opt,name=last_peer,json=lastPeer,proto3" json:"last_peer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoundSummary) Reset()         { *m = RoundSummary{} }
+func (m *RoundSummary) String() string { return proto.CompactTextString(m) }
+func (*RoundSummary) ProtoMessage()    {}
+func (*RoundSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{4}
+}
+
+func (m *RoundSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoundSummary.Unmarshal(m, b)
+}
+func (m *RoundSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoundSummary.Marshal(b, m, deterministic)
+}
+func (m *RoundSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoundSummary.Merge(m, src)
+}
+func (m *RoundSummary) XXX_Size() int {
+	return xxx_messageInfo_RoundSummary.Size(m)
+}
+func (m *RoundSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoundSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoundSummary proto.InternalMessageInfo
+
+func (m *RoundSummary) GetRound() string {
+	if m != nil {
+		return m.Round
+	}
+	return ""
+}
+
+func (m *RoundSummary) GetLocalMs() int64 {
+	if m != nil {
+		return m.LocalMs
+	}
+	return 0
+}
+
+func (m *RoundSummary) GetReceived() int32 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *RoundSummary) GetConfirmed() int32 {
+	if m != nil {
+		return m.Confirmed
+	}
+	return 0
+}
+
+func (m *RoundSummary) GetLastPeerMs() int64 {
+	if m != nil {
+		return m.LastPeerMs
+	}
+	return 0
+}
+
+func (m *RoundSummary) GetLastPeer() string {
+	if m != nil {
+		return m.LastPeer
+	}
+	return ""
+}
+
+// TimelineSummary tells where the time of the session goes, the times are the milliseconds since the start of the session
+type TimelineSummary struct {
+	SessionId            string          `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	PartyFormedMs        int64           `protobuf:"varint,2,opt,name=party_formed_ms,json=partyFormedMs,proto3" json:"party_formed_ms,omitempty"`
+	DurationMs           int64           `protobuf:"varint,3,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	ShareRequests        int32           `protobuf:"varint,4,opt,name=share_requests,json=shareRequests,proto3" json:"share_requests,omitempty"`
+	Rounds               []*RoundSummary `protobuf:"bytes,5,rep,name=rounds,proto3" json:"rounds,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *TimelineSummary) Reset()         { *m = TimelineSummary{} }
+func (m *TimelineSummary) String() string { return proto.CompactTextString(m) }
+func (*TimelineSummary) ProtoMessage()    {}
+func (*TimelineSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{5}
+}
+
+func (m *TimelineSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TimelineSummary.Unmarshal(m, b)
+}
+func (m *TimelineSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TimelineSummary.Marshal(b, m, deterministic)
+}
+func (m *TimelineSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TimelineSummary.Merge(m, src)
+}
+func (m *TimelineSummary) XXX_Size() int {
+	return xxx_messageInfo_TimelineSummary.Size(m)
+}
+func (m *TimelineSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_TimelineSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TimelineSummary proto.InternalMessageInfo
+
+func (m *TimelineSummary) GetSessionId() string {
+	if m != nil {
+		return m.SessionId
+	}
+	return ""
+}
+
+func (m *TimelineSummary) GetPartyFormedMs() int64 {
+	if m != nil {
+		return m.PartyFormedMs
+	}
+	return 0
+}
+
+func (m *TimelineSummary) GetDurationMs() int64 {
+	if m != nil {
+		return m.DurationMs
+	}
+	return 0
+}
+
+func (m *TimelineSummary) GetShareRequests() int32 {
+	if m != nil {
+		return m.ShareRequests
+	}
+	return 0
+}
+
+func (m *TimelineSummary) GetRounds() []*RoundSummary {
+	if m != nil {
+		return m.Rounds
+	}
+	return nil
+}
+
+type KeygenRequest struct {
+	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Threshold            int32    `protobuf:"varint,2,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Algo                 string   `protobuf:"bytes,3,opt,name=algo,proto3" json:"algo,omitempty"`
+	BlockHeight          int64    `protobuf:"varint,4,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	CosmosPrefix         string   `protobuf:"bytes,5,opt,name=cosmos_prefix,json=cosmosPrefix,proto3" json:"cosmos_prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *KeygenRequest) Reset()         { *m = KeygenRequest{} }
+func (m *KeygenRequest) String() string { return proto.CompactTextString(m) }
+func (*KeygenRequest) ProtoMessage()    {}
+func (*KeygenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{6}
+}
+
+func (m *KeygenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeygenRequest.Unmarshal(m, b)
+}
+func (m *KeygenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeygenRequest.Marshal(b, m, deterministic)
+}
+func (m *KeygenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeygenRequest.Merge(m, src)
+}
+func (m *KeygenRequest) XXX_Size() int {
+	return xxx_messageInfo_KeygenRequest.Size(m)
+}
+func (m *KeygenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeygenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeygenRequest proto.InternalMessageInfo
+
+func (m *KeygenRequest) GetKeys() []string {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+func (m *KeygenRequest) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *KeygenRequest) GetAlgo() string {
+	if m != nil {
+		return m.Algo
+	}
+	return ""
+}
+
+func (m *KeygenRequest) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *KeygenRequest) GetCosmosPrefix() string {
+	if m != nil {
+		return m.CosmosPrefix
+	}
+	return ""
+}
+
+type KeygenResponse struct {
+	PubKey               string           `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	PoolAddress          string           `protobuf:"bytes,2,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	Status               Status           `protobuf:"varint,3,opt,name=status,proto3,enum=rpc.Status" json:"status,omitempty"`
+	Blame                *Blame           `protobuf:"bytes,4,opt,name=blame,proto3" json:"blame,omitempty"`
+	PubKeyInfo           *PubKeyInfo      `protobuf:"bytes,5,opt,name=pub_key_info,json=pubKeyInfo,proto3" json:"pub_key_info,omitempty"`
+	Timeline             *TimelineSummary `protobuf:"bytes,6,opt,name=timeline,proto3" json:"timeline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *KeygenResponse) Reset()         { *m = KeygenResponse{} }
+func (m *KeygenResponse) String() string { return proto.CompactTextString(m) }
+func (*KeygenResponse) ProtoMessage()    {}
+func (*KeygenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{7}
+}
+
+func (m *KeygenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeygenResponse.Unmarshal(m, b)
+}
+func (m *KeygenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeygenResponse.Marshal(b, m, deterministic)
+}
+func (m *KeygenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeygenResponse.Merge(m, src)
+}
+func (m *KeygenResponse) XXX_Size() int {
+	return xxx_messageInfo_KeygenResponse.Size(m)
+}
+func (m *KeygenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeygenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeygenResponse proto.InternalMessageInfo
+
+func (m *KeygenResponse) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *KeygenResponse) GetPoolAddress() string {
+	if m != nil {
+		return m.PoolAddress
+	}
+	return ""
+}
+
+func (m *KeygenResponse) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_NA
+}
+
+func (m *KeygenResponse) GetBlame() *Blame {
+	if m != nil {
+		return m.Blame
+	}
+	return nil
+}
+
+func (m *KeygenResponse) GetPubKeyInfo() *PubKeyInfo {
+	if m != nil {
+		return m.PubKeyInfo
+	}
+	return nil
+}
+
+func (m *KeygenResponse) GetTimeline() *TimelineSummary {
+	if m != nil {
+		return m.Timeline
+	}
+	return nil
+}
+
+type KeygenProgress struct {
+	Event                *ProgressEvent  `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Response             *KeygenResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *KeygenProgress) Reset()         { *m = KeygenProgress{} }
+func (m *KeygenProgress) String() string { return proto.CompactTextString(m) }
+func (*KeygenProgress) ProtoMessage()    {}
+func (*KeygenProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{8}
+}
+
+func (m *KeygenProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeygenProgress.Unmarshal(m, b)
+}
+func (m *KeygenProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeygenProgress.Marshal(b, m, deterministic)
+}
+func (m *KeygenProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeygenProgress.Merge(m, src)
+}
+func (m *KeygenProgress) XXX_Size() int {
+	return xxx_messageInfo_KeygenProgress.Size(m)
+}
+func (m *KeygenProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeygenProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeygenProgress proto.InternalMessageInfo
+
+func (m *KeygenProgress) GetEvent() *ProgressEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *KeygenProgress) GetResponse() *KeygenResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+type KeySignRequest struct {
+	PoolPubKey           string            `protobuf:"bytes,1,opt,name=pool_pub_key,json=poolPubKey,proto3" json:"pool_pub_key,omitempty"`
+	Message              string            `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	SignerPubKeys        []string          `protobuf:"bytes,3,rep,name=signer_pub_keys,json=signerPubKeys,proto3" json:"signer_pub_keys,omitempty"`
+	Algo                 string            `protobuf:"bytes,4,opt,name=algo,proto3" json:"algo,omitempty"`
+	DerivationPath       string            `protobuf:"bytes,5,opt,name=derivation_path,json=derivationPath,proto3" json:"derivation_path,omitempty"`
+	BlockHeight          int64             `protobuf:"varint,6,opt,name=block_height,json=blockHeight,proto3" json:"block_height,omitempty"`
+	Migration            bool              `protobuf:"varint,7,opt,name=migration,proto3" json:"migration,omitempty"`
+	Metadata             map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *KeySignRequest) Reset()         { *m = KeySignRequest{} }
+func (m *KeySignRequest) String() string { return proto.CompactTextString(m) }
+func (*KeySignRequest) ProtoMessage()    {}
+func (*KeySignRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{9}
+}
+
+func (m *KeySignRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySignRequest.Unmarshal(m, b)
+}
+func (m *KeySignRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySignRequest.Marshal(b, m, deterministic)
+}
+func (m *KeySignRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySignRequest.Merge(m, src)
+}
+func (m *KeySignRequest) XXX_Size() int {
+	return xxx_messageInfo_KeySignRequest.Size(m)
+}
+func (m *KeySignRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySignRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySignRequest proto.InternalMessageInfo
+
+func (m *KeySignRequest) GetPoolPubKey() string {
+	if m != nil {
+		return m.PoolPubKey
+	}
+	return ""
+}
+
+func (m *KeySignRequest) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+func (m *KeySignRequest) GetSignerPubKeys() []string {
+	if m != nil {
+		return m.SignerPubKeys
+	}
+	return nil
+}
+
+func (m *KeySignRequest) GetAlgo() string {
+	if m != nil {
+		return m.Algo
+	}
+	return ""
+}
+
+func (m *KeySignRequest) GetDerivationPath() string {
+	if m != nil {
+		return m.DerivationPath
+	}
+	return ""
+}
+
+func (m *KeySignRequest) GetBlockHeight() int64 {
+	if m != nil {
+		return m.BlockHeight
+	}
+	return 0
+}
+
+func (m *KeySignRequest) GetMigration() bool {
+	if m != nil {
+		return m.Migration
+	}
+	return false
+}
+
+func (m *KeySignRequest) GetMetadata() map[string]string {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type KeySignResponse struct {
+	R                    string               `protobuf:"bytes,1,opt,name=r,proto3" json:"r,omitempty"`
+	S                    string               `protobuf:"bytes,2,opt,name=s,proto3" json:"s,omitempty"`
+	RecoveryId           *wrappers.Int32Value `protobuf:"bytes,3,opt,name=recovery_id,json=recoveryId,proto3" json:"recovery_id,omitempty"`
+	Status               Status               `protobuf:"varint,4,opt,name=status,proto3,enum=rpc.Status" json:"status,omitempty"`
+	Blame                *Blame               `protobuf:"bytes,5,opt,name=blame,proto3" json:"blame,omitempty"`
+	Timeline             *TimelineSummary     `protobuf:"bytes,6,opt,name=timeline,proto3" json:"timeline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *KeySignResponse) Reset()         { *m = KeySignResponse{} }
+func (m *KeySignResponse) String() string { return proto.CompactTextString(m) }
+func (*KeySignResponse) ProtoMessage()    {}
+func (*KeySignResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{10}
+}
+
+func (m *KeySignResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySignResponse.Unmarshal(m, b)
+}
+func (m *KeySignResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySignResponse.Marshal(b, m, deterministic)
+}
+func (m *KeySignResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySignResponse.Merge(m, src)
+}
+func (m *KeySignResponse) XXX_Size() int {
+	return xxx_messageInfo_KeySignResponse.Size(m)
+}
+func (m *KeySignResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySignResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySignResponse proto.InternalMessageInfo
+
+func (m *KeySignResponse) GetR() string {
+	if m != nil {
+		return m.R
+	}
+	return ""
+}
+
+func (m *KeySignResponse) GetS() string {
+	if m != nil {
+		return m.S
+	}
+	return ""
+}
+
+func (m *KeySignResponse) GetRecoveryId() *wrappers.Int32Value {
+	if m != nil {
+		return m.RecoveryId
+	}
+	return nil
+}
+
+func (m *KeySignResponse) GetStatus() Status {
+	if m != nil {
+		return m.Status
+	}
+	return Status_NA
+}
+
+func (m *KeySignResponse) GetBlame() *Blame {
+	if m != nil {
+		return m.Blame
+	}
+	return nil
+}
+
+func (m *KeySignResponse) GetTimeline() *TimelineSummary {
+	if m != nil {
+		return m.Timeline
+	}
+	return nil
+}
+
+type KeySignProgress struct {
+	Event                *ProgressEvent   `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	Response             *KeySignResponse `protobuf:"bytes,2,opt,name=response,proto3" json:"response,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *KeySignProgress) Reset()         { *m = KeySignProgress{} }
+func (m *KeySignProgress) String() string { return proto.CompactTextString(m) }
+func (*KeySignProgress) ProtoMessage()    {}
+func (*KeySignProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{11}
+}
+
+func (m *KeySignProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_KeySignProgress.Unmarshal(m, b)
+}
+func (m *KeySignProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_KeySignProgress.Marshal(b, m, deterministic)
+}
+func (m *KeySignProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeySignProgress.Merge(m, src)
+}
+func (m *KeySignProgress) XXX_Size() int {
+	return xxx_messageInfo_KeySignProgress.Size(m)
+}
+func (m *KeySignProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeySignProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeySignProgress proto.InternalMessageInfo
+
+func (m *KeySignProgress) GetEvent() *ProgressEvent {
+	if m != nil {
+		return m.Event
+	}
+	return nil
+}
+
+func (m *KeySignProgress) GetResponse() *KeySignResponse {
+	if m != nil {
+		return m.Response
+	}
+	return nil
+}
+
+type GetStatusRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{12}
+}
+
+func (m *GetStatusRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusRequest.Unmarshal(m, b)
+}
+func (m *GetStatusRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusRequest.Marshal(b, m, deterministic)
+}
+func (m *GetStatusRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusRequest.Merge(m, src)
+}
+func (m *GetStatusRequest) XXX_Size() int {
+	return xxx_messageInfo_GetStatusRequest.Size(m)
+}
+func (m *GetStatusRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusRequest proto.InternalMessageInfo
+
+type GetStatusResponse struct {
+	StartTime            int64    `protobuf:"varint,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	SuccessfulKeygen     uint64   `protobuf:"varint,2,opt,name=successful_keygen,json=successfulKeygen,proto3" json:"successful_keygen,omitempty"`
+	FailedKeygen         uint64   `protobuf:"varint,3,opt,name=failed_keygen,json=failedKeygen,proto3" json:"failed_keygen,omitempty"`
+	SuccessfulKeysign    uint64   `protobuf:"varint,4,opt,name=successful_keysign,json=successfulKeysign,proto3" json:"successful_keysign,omitempty"`
+	FailedKeysign        uint64   `protobuf:"varint,5,opt,name=failed_keysign,json=failedKeysign,proto3" json:"failed_keysign,omitempty"`
+	PreParamPoolDepth    int32    `protobuf:"varint,6,opt,name=pre_param_pool_depth,json=preParamPoolDepth,proto3" json:"pre_param_pool_depth,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
+func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusResponse) ProtoMessage()    {}
+func (*GetStatusResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{13}
+}
+
+func (m *GetStatusResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetStatusResponse.Unmarshal(m, b)
+}
+func (m *GetStatusResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetStatusResponse.Marshal(b, m, deterministic)
+}
+func (m *GetStatusResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetStatusResponse.Merge(m, src)
+}
+func (m *GetStatusResponse) XXX_Size() int {
+	return xxx_messageInfo_GetStatusResponse.Size(m)
+}
+func (m *GetStatusResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetStatusResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetStatusResponse proto.InternalMessageInfo
+
+func (m *GetStatusResponse) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetSuccessfulKeygen() uint64 {
+	if m != nil {
+		return m.SuccessfulKeygen
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetFailedKeygen() uint64 {
+	if m != nil {
+		return m.FailedKeygen
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetSuccessfulKeysign() uint64 {
+	if m != nil {
+		return m.SuccessfulKeysign
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetFailedKeysign() uint64 {
+	if m != nil {
+		return m.FailedKeysign
+	}
+	return 0
+}
+
+func (m *GetStatusResponse) GetPreParamPoolDepth() int32 {
+	if m != nil {
+		return m.PreParamPoolDepth
+	}
+	return 0
+}
+
+type GetLocalPeerIDRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLocalPeerIDRequest) Reset()         { *m = GetLocalPeerIDRequest{} }
+func (m *GetLocalPeerIDRequest) String() string { return proto.CompactTextString(m) }
+func (*GetLocalPeerIDRequest) ProtoMessage()    {}
+func (*GetLocalPeerIDRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{14}
+}
+
+func (m *GetLocalPeerIDRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLocalPeerIDRequest.Unmarshal(m, b)
+}
+func (m *GetLocalPeerIDRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLocalPeerIDRequest.Marshal(b, m, deterministic)
+}
+func (m *GetLocalPeerIDRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLocalPeerIDRequest.Merge(m, src)
+}
+func (m *GetLocalPeerIDRequest) XXX_Size() int {
+	return xxx_messageInfo_GetLocalPeerIDRequest.Size(m)
+}
+func (m *GetLocalPeerIDRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLocalPeerIDRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLocalPeerIDRequest proto.InternalMessageInfo
+
+type GetLocalPeerIDResponse struct {
+	PeerId               string   `protobuf:"bytes,1,opt,name=peer_id,json=peerId,proto3" json:"peer_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetLocalPeerIDResponse) Reset()         { *m = GetLocalPeerIDResponse{} }
+func (m *GetLocalPeerIDResponse) String() string { return proto.CompactTextString(m) }
+func (*GetLocalPeerIDResponse) ProtoMessage()    {}
+func (*GetLocalPeerIDResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{15}
+}
+
+func (m *GetLocalPeerIDResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetLocalPeerIDResponse.Unmarshal(m, b)
+}
+func (m *GetLocalPeerIDResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetLocalPeerIDResponse.Marshal(b, m, deterministic)
+}
+func (m *GetLocalPeerIDResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetLocalPeerIDResponse.Merge(m, src)
+}
+func (m *GetLocalPeerIDResponse) XXX_Size() int {
+	return xxx_messageInfo_GetLocalPeerIDResponse.Size(m)
+}
+func (m *GetLocalPeerIDResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetLocalPeerIDResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetLocalPeerIDResponse proto.InternalMessageInfo
+
+func (m *GetLocalPeerIDResponse) GetPeerId() string {
+	if m != nil {
+		return m.PeerId
+	}
+	return ""
+}
+
+type ListKeysRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListKeysRequest) Reset()         { *m = ListKeysRequest{} }
+func (m *ListKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*ListKeysRequest) ProtoMessage()    {}
+func (*ListKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{16}
+}
+
+func (m *ListKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListKeysRequest.Unmarshal(m, b)
+}
+func (m *ListKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *ListKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListKeysRequest.Merge(m, src)
+}
+func (m *ListKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_ListKeysRequest.Size(m)
+}
+func (m *ListKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListKeysRequest proto.InternalMessageInfo
+
+type ListKeysResponse struct {
+	Keys                 []*LocalKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}    `json:"-"`
+	XXX_unrecognized     []byte      `json:"-"`
+	XXX_sizecache        int32       `json:"-"`
+}
+
+func (m *ListKeysResponse) Reset()         { *m = ListKeysResponse{} }
+func (m *ListKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListKeysResponse) ProtoMessage()    {}
+func (*ListKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{17}
+}
+
+func (m *ListKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListKeysResponse.Unmarshal(m, b)
+}
+func (m *ListKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListKeysResponse.Merge(m, src)
+}
+func (m *ListKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListKeysResponse.Size(m)
+}
+func (m *ListKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListKeysResponse proto.InternalMessageInfo
+
+func (m *ListKeysResponse) GetKeys() []*LocalKey {
+	if m != nil {
+		return m.Keys
+	}
+	return nil
+}
+
+type GetKeyRequest struct {
+	PubKey               string   `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetKeyRequest) Reset()         { *m = GetKeyRequest{} }
+func (m *GetKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*GetKeyRequest) ProtoMessage()    {}
+func (*GetKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{18}
+}
+
+func (m *GetKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetKeyRequest.Unmarshal(m, b)
+}
+func (m *GetKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *GetKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetKeyRequest.Merge(m, src)
+}
+func (m *GetKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_GetKeyRequest.Size(m)
+}
+func (m *GetKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetKeyRequest proto.InternalMessageInfo
+
+func (m *GetKeyRequest) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+type LocalKey struct {
+	PubKey               string   `protobuf:"bytes,1,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+	Algo                 string   `protobuf:"bytes,2,opt,name=algo,proto3" json:"algo,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	ParticipantKeys      []string `protobuf:"bytes,4,rep,name=participant_keys,json=participantKeys,proto3" json:"participant_keys,omitempty"`
+	LocalPartyKey        string   `protobuf:"bytes,5,opt,name=local_party_key,json=localPartyKey,proto3" json:"local_party_key,omitempty"`
+	Threshold            int32    `protobuf:"varint,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	ShareId              string   `protobuf:"bytes,7,opt,name=share_id,json=shareId,proto3" json:"share_id,omitempty"`
+	PublicShares         []string `protobuf:"bytes,8,rep,name=public_shares,json=publicShares,proto3" json:"public_shares,omitempty"`
+	ModifiedAt           int64    `protobuf:"varint,9,opt,name=modified_at,json=modifiedAt,proto3" json:"modified_at,omitempty"`
+	Error                string   `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LocalKey) Reset()         { *m = LocalKey{} }
+func (m *LocalKey) String() string { return proto.CompactTextString(m) }
+func (*LocalKey) ProtoMessage()    {}
+func (*LocalKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7eeb10c64af1b9c9, []int{19}
+}
+
+func (m *LocalKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LocalKey.Unmarshal(m, b)
+}
+func (m *LocalKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LocalKey.Marshal(b, m, deterministic)
+}
+func (m *LocalKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LocalKey.Merge(m, src)
+}
+func (m *LocalKey) XXX_Size() int {
+	return xxx_messageInfo_LocalKey.Size(m)
+}
+func (m *LocalKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_LocalKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LocalKey proto.InternalMessageInfo
+
+func (m *LocalKey) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *LocalKey) GetAlgo() string {
+	if m != nil {
+		return m.Algo
+	}
+	return ""
+}
+
+func (m *LocalKey) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+func (m *LocalKey) GetParticipantKeys() []string {
+	if m != nil {
+		return m.ParticipantKeys
+	}
+	return nil
+}
+
+func (m *LocalKey) GetLocalPartyKey() string {
+	if m != nil {
+		return m.LocalPartyKey
+	}
+	return ""
+}
+
+func (m *LocalKey) GetThreshold() int32 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *LocalKey) GetShareId() string {
+	if m != nil {
+		return m.ShareId
+	}
+	return ""
+}
+
+func (m *LocalKey) GetPublicShares() []string {
+	if m != nil {
+		return m.PublicShares
+	}
+	return nil
+}
+
+func (m *LocalKey) GetModifiedAt() int64 {
+	if m != nil {
+		return m.ModifiedAt
+	}
+	return 0
+}
+
+func (m *LocalKey) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterEnum("rpc.Status", Status_name, Status_value)
+	proto.RegisterEnum("rpc.ProgressEvent_Type", ProgressEvent_Type_name, ProgressEvent_Type_value)
+	proto.RegisterType((*BlameNode)(nil), "rpc.BlameNode")
+	proto.RegisterType((*Blame)(nil), "rpc.Blame")
+	proto.RegisterType((*ProgressEvent)(nil), "rpc.ProgressEvent")
+	proto.RegisterType((*PubKeyInfo)(nil), "rpc.PubKeyInfo")
+	proto.RegisterType((*RoundSummary)(nil), "rpc.RoundSummary")
+	proto.RegisterType((*TimelineSummary)(nil), "rpc.TimelineSummary")
+	proto.RegisterType((*KeygenRequest)(nil), "rpc.KeygenRequest")
+	proto.RegisterType((*KeygenResponse)(nil), "rpc.KeygenResponse")
+	proto.RegisterType((*KeygenProgress)(nil), "rpc.KeygenProgress")
+	proto.RegisterType((*KeySignRequest)(nil), "rpc.KeySignRequest")
+	proto.RegisterMapType((map[string]string)(nil), "rpc.KeySignRequest.MetadataEntry")
+	proto.RegisterType((*KeySignResponse)(nil), "rpc.KeySignResponse")
+	proto.RegisterType((*KeySignProgress)(nil), "rpc.KeySignProgress")
+	proto.RegisterType((*GetStatusRequest)(nil), "rpc.GetStatusRequest")
+	proto.RegisterType((*GetStatusResponse)(nil), "rpc.GetStatusResponse")
+	proto.RegisterType((*GetLocalPeerIDRequest)(nil), "rpc.GetLocalPeerIDRequest")
+	proto.RegisterType((*GetLocalPeerIDResponse)(nil), "rpc.GetLocalPeerIDResponse")
+	proto.RegisterType((*ListKeysRequest)(nil), "rpc.ListKeysRequest")
+	proto.RegisterType((*ListKeysResponse)(nil), "rpc.ListKeysResponse")
+	proto.RegisterType((*GetKeyRequest)(nil), "rpc.GetKeyRequest")
+	proto.RegisterType((*LocalKey)(nil), "rpc.LocalKey")
+}
+
+func init() {
+	proto.RegisterFile("tss.proto", fileDescriptor_7eeb10c64af1b9c9)
+}
+
+var fileDescriptor_7eeb10c64af1b9c9 = []byte{
+	// 1618 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x92, 0x1b, 0x49,
+	0x11, 0xde, 0xd6, 0xdf, 0xa8, 0x53, 0xd2, 0x48, 0x53, 0x3b, 0xb6, 0xb5, 0xf2, 0x2e, 0x8c, 0x7b,
+	0x83, 0x65, 0x16, 0x83, 0xc6, 0xd6, 0x42, 0x00, 0xcb, 0x72, 0x30, 0xeb, 0xb5, 0x19, 0xbc, 0xe3,
+	0x50, 0xf4, 0xd8, 0x04, 0xb7, 0x8e, 0x56, 0x77, 0x4a, 0x6a, 0xa6, 0xff, 0xa8, 0xea, 0x1e, 0xaf,
+	0x9e, 0x81, 0x67, 0xe0, 0x31, 0x88, 0xe0, 0x1d, 0xb8, 0x11, 0xdc, 0xb9, 0x70, 0xe6, 0xcc, 0x91,
+	0xc8, 0xac, 0xae, 0x96, 0x34, 0x63, 0xc7, 0x02, 0xb7, 0xaa, 0x2f, 0xb3, 0xb2, 0x32, 0xb3, 0xbe,
+	0xcc, 0x2c, 0xb0, 0x0b, 0xa5, 0xa6, 0xb9, 0xcc, 0x8a, 0x4c, 0x34, 0x65, 0x1e, 0x4c, 0xbe, 0xb3,
+	0xca, 0xb2, 0x55, 0x8c, 0x67, 0x0c, 0x2d, 0xca, 0xe5, 0xd9, 0x1b, 0xe9, 0xe7, 0x39, 0xca, 0x4a,
+	0xc9, 0x79, 0x0d, 0xf6, 0xaf, 0x62, 0x3f, 0xc1, 0x97, 0x59, 0x88, 0xe2, 0x2e, 0x74, 0xf2, 0x72,
+	0x71, 0x85, 0x9b, 0xb1, 0x75, 0x62, 0x9d, 0xda, 0x6e, 0xb5, 0x13, 0x02, 0x5a, 0xa1, 0x5f, 0xf8,
+	0xe3, 0xc6, 0x89, 0x75, 0xda, 0x77, 0x79, 0x2d, 0x3e, 0x04, 0x5b, 0x45, 0xab, 0xd4, 0x2f, 0x4a,
+	0x89, 0xe3, 0x26, 0x0b, 0xb6, 0x80, 0xf3, 0x0d, 0xb4, 0xd9, 0xac, 0xf8, 0x2e, 0xf4, 0x96, 0x7e,
+	0x14, 0x7b, 0x12, 0x7d, 0x95, 0xa5, 0x95, 0x5d, 0x20, 0xc8, 0x65, 0x44, 0x7c, 0x04, 0x10, 0x29,
+	0xaf, 0x4c, 0xa3, 0xc0, 0x57, 0x05, 0xdf, 0xd0, 0x75, 0xed, 0x48, 0xbd, 0xd6, 0x80, 0x38, 0x83,
+	0xde, 0x82, 0x0c, 0x79, 0x69, 0x16, 0xa2, 0x1a, 0x37, 0x4f, 0x9a, 0xa7, 0xbd, 0xd9, 0xe1, 0x54,
+	0xe6, 0xc1, 0xb4, 0xf6, 0xdb, 0x85, 0x85, 0x59, 0x2a, 0xe7, 0x1f, 0x16, 0x0c, 0xe6, 0x32, 0x5b,
+	0x49, 0x54, 0xea, 0xab, 0x6b, 0x4c, 0x0b, 0xf1, 0x10, 0x5a, 0xc5, 0x26, 0x47, 0xbe, 0xfb, 0x70,
+	0x76, 0x8f, 0xcf, 0xee, 0x69, 0x4c, 0x5f, 0x6d, 0x72, 0x74, 0x59, 0x49, 0x1c, 0x43, 0x5b, 0x66,
+	0x65, 0x1a, 0xb2, 0x27, 0x6d, 0x57, 0x6f, 0xc8, 0x49, 0x5e, 0x78, 0xa9, 0x9f, 0xe8, 0x68, 0x6d,
+	0xd7, 0x66, 0xe4, 0x25, 0x05, 0x29, 0xa0, 0x95, 0x23, 0xca, 0x71, 0x8b, 0x05, 0xbc, 0x76, 0x7e,
+	0x07, 0x2d, 0x32, 0x2b, 0x7a, 0x70, 0xf0, 0x3a, 0xbd, 0x4a, 0xb3, 0x37, 0xe9, 0xe8, 0x3d, 0x31,
+	0x84, 0xde, 0xdc, 0x97, 0xc5, 0xe6, 0x37, 0x59, 0x94, 0x62, 0x38, 0xb2, 0xc4, 0x08, 0xfa, 0x2e,
+	0x99, 0xb9, 0x2c, 0x7c, 0x59, 0x60, 0x38, 0x6a, 0x08, 0x01, 0x87, 0x97, 0x6b, 0x5f, 0xa2, 0x8b,
+	0x7f, 0x28, 0x51, 0x11, 0xd6, 0x14, 0x7d, 0xe8, 0x3e, 0x8b, 0xd2, 0x48, 0xad, 0x31, 0x1c, 0xb5,
+	0x9c, 0xbf, 0x59, 0x00, 0xf3, 0x72, 0xf1, 0x02, 0x37, 0xe7, 0xe9, 0x32, 0x13, 0x3f, 0x04, 0x91,
+	0x97, 0x0b, 0xef, 0x0a, 0x37, 0x5e, 0x90, 0x25, 0x39, 0x45, 0x85, 0x61, 0x95, 0xe8, 0x51, 0xce,
+	0x7a, 0x5f, 0xd6, 0xb8, 0x78, 0x04, 0xc7, 0x46, 0xbb, 0x4c, 0x77, 0xf4, 0x1b, 0xac, 0x2f, 0xb4,
+	0xfe, 0xeb, 0x1d, 0x09, 0xc5, 0xbe, 0x28, 0x02, 0x2f, 0x9f, 0xbd, 0xc9, 0xaf, 0xd6, 0x26, 0xf6,
+	0x45, 0x11, 0xcc, 0x19, 0x10, 0xf7, 0xc1, 0xd6, 0x62, 0x92, 0xea, 0x04, 0x74, 0x59, 0x4a, 0xc2,
+	0x11, 0x34, 0xb1, 0x58, 0x8f, 0xdb, 0x0c, 0xd3, 0x92, 0x28, 0x16, 0x64, 0x2a, 0xc9, 0xd4, 0xb8,
+	0xa3, 0x29, 0xa6, 0x77, 0xce, 0x5f, 0x2c, 0x93, 0x89, 0x32, 0x49, 0x7c, 0xb9, 0xd9, 0x3e, 0x84,
+	0x8e, 0x44, 0x6f, 0xc4, 0x07, 0xd0, 0x8d, 0xb3, 0xc0, 0x8f, 0xbd, 0x44, 0xb1, 0xcb, 0x4d, 0xf7,
+	0x80, 0xf7, 0x17, 0x4a, 0x4c, 0xa0, 0x2b, 0x31, 0xc0, 0xe8, 0x1a, 0x43, 0xf6, 0xb2, 0xed, 0xd6,
+	0x7b, 0x22, 0x6b, 0x90, 0xa5, 0xcb, 0x48, 0x26, 0x18, 0xb2, 0x93, 0x6d, 0x77, 0x0b, 0x88, 0x13,
+	0xe8, 0xc7, 0xbe, 0x2a, 0x3c, 0x7a, 0x37, 0x32, 0xdc, 0x66, 0xc3, 0x40, 0xd8, 0x1c, 0x51, 0x5e,
+	0x28, 0x0a, 0xb2, 0xd6, 0xa8, 0x1c, 0xef, 0x1a, 0xb1, 0xf3, 0x57, 0x0b, 0x86, 0xaf, 0xa2, 0x04,
+	0xe3, 0x28, 0x45, 0xe3, 0xfd, 0x47, 0x00, 0x0a, 0x95, 0x8a, 0xb2, 0xd4, 0x8b, 0x4c, 0x08, 0x76,
+	0x85, 0x9c, 0x87, 0xe2, 0x13, 0x18, 0xe6, 0xc4, 0x03, 0x6f, 0x99, 0x91, 0x07, 0xdb, 0x68, 0x06,
+	0x0c, 0x3f, 0x63, 0xf4, 0x42, 0x51, 0xf5, 0x84, 0xa5, 0xf4, 0x0b, 0xb2, 0x93, 0x28, 0x0e, 0xab,
+	0xe9, 0x82, 0x81, 0x2e, 0x94, 0xf8, 0x1e, 0x1c, 0x2a, 0x62, 0x8b, 0x27, 0x35, 0x5d, 0x54, 0x15,
+	0xdd, 0x40, 0xed, 0x70, 0x48, 0x89, 0x4f, 0xa1, 0xc3, 0xf9, 0xa3, 0xd8, 0xa8, 0x80, 0x8e, 0xb8,
+	0x08, 0x76, 0xf3, 0xed, 0x56, 0x0a, 0xce, 0x9f, 0x2c, 0x18, 0xbc, 0xc0, 0xcd, 0x0a, 0xd3, 0xea,
+	0x34, 0xb1, 0xfb, 0x0a, 0x37, 0x6a, 0x6c, 0x9d, 0x34, 0x89, 0xdd, 0xb4, 0xa6, 0x84, 0x16, 0x6b,
+	0x89, 0x6a, 0x9d, 0xc5, 0xa6, 0x54, 0xb6, 0x00, 0x9d, 0xf0, 0xe3, 0x55, 0x56, 0x91, 0x85, 0xd7,
+	0xe2, 0x01, 0xf4, 0x17, 0x71, 0x16, 0x5c, 0x79, 0x6b, 0x8c, 0x56, 0xeb, 0x82, 0xfd, 0x6c, 0xba,
+	0x3d, 0xc6, 0x7e, 0xcd, 0x90, 0xf8, 0x18, 0x06, 0x9a, 0x0d, 0x5e, 0x2e, 0x71, 0x19, 0x7d, 0x53,
+	0xf1, 0xa6, 0xaf, 0xc1, 0x39, 0x63, 0xce, 0xbf, 0x2d, 0x38, 0x34, 0xfe, 0xa9, 0x3c, 0x4b, 0x15,
+	0x8a, 0x7b, 0x70, 0x50, 0x71, 0x7a, 0xa7, 0x6f, 0xbd, 0xc0, 0x0d, 0xdd, 0x99, 0x67, 0x59, 0xec,
+	0xf9, 0x61, 0x48, 0x64, 0xae, 0x48, 0xde, 0x23, 0xec, 0x89, 0x86, 0xc4, 0xc7, 0xd0, 0x51, 0x85,
+	0x5f, 0x94, 0x3a, 0xb9, 0x87, 0xb3, 0x1e, 0x67, 0xe6, 0x92, 0x21, 0xb7, 0x12, 0x89, 0x13, 0x68,
+	0x73, 0x87, 0x61, 0xa7, 0x7b, 0x33, 0xd8, 0xb6, 0x1f, 0x57, 0x0b, 0xc4, 0x63, 0xe8, 0x9b, 0xb2,
+	0x8a, 0xd2, 0x65, 0xc6, 0x9e, 0xf7, 0x66, 0x43, 0xdd, 0x6b, 0xea, 0x5a, 0x75, 0x21, 0xaf, 0xd7,
+	0xe2, 0x11, 0x74, 0x8b, 0x8a, 0x35, 0x4c, 0xa9, 0xde, 0xec, 0x98, 0xd5, 0x6f, 0x50, 0xc9, 0xad,
+	0xb5, 0x9c, 0x2b, 0x13, 0xb9, 0xe9, 0x5e, 0xe2, 0x14, 0xda, 0x48, 0x1d, 0x8c, 0xe3, 0xee, 0xcd,
+	0xc4, 0xed, 0xde, 0xe6, 0x6a, 0x05, 0x71, 0x46, 0xd5, 0xa1, 0xf3, 0xc5, 0x69, 0xe8, 0xcd, 0xde,
+	0x67, 0xe5, 0xfd, 0x54, 0xba, 0xb5, 0x92, 0xf3, 0xaf, 0x06, 0xdf, 0x76, 0x19, 0xad, 0x6a, 0x22,
+	0x9c, 0x54, 0xe9, 0xdc, 0x4f, 0x36, 0x10, 0xa6, 0x63, 0x14, 0x63, 0x38, 0x48, 0x50, 0x29, 0x7f,
+	0x85, 0x55, 0xae, 0xcd, 0x96, 0x18, 0x4f, 0xd3, 0x01, 0xa5, 0x39, 0xad, 0x7b, 0xb9, 0xed, 0x0e,
+	0x34, 0xac, 0x0d, 0xa8, 0x9a, 0x3a, 0xad, 0x1d, 0xea, 0x7c, 0x1f, 0x86, 0x21, 0xca, 0xe8, 0x5a,
+	0xd7, 0x41, 0xee, 0xd7, 0x1d, 0xe5, 0x70, 0x0b, 0xcf, 0xfd, 0x62, 0x7d, 0x8b, 0x63, 0x9d, 0xdb,
+	0x1c, 0xfb, 0x10, 0xec, 0x24, 0x5a, 0xe9, 0xfa, 0x19, 0x1f, 0xe8, 0x69, 0x53, 0x03, 0xe2, 0x97,
+	0xd0, 0x4d, 0xb0, 0xf0, 0x79, 0xd8, 0x75, 0xb9, 0x52, 0x1e, 0x98, 0x2c, 0xed, 0x24, 0x62, 0x7a,
+	0x51, 0xe9, 0x7c, 0x95, 0x16, 0xf4, 0x40, 0xe6, 0xc8, 0xe4, 0x17, 0x30, 0xd8, 0x13, 0x51, 0xff,
+	0xdb, 0x26, 0x8a, 0x96, 0xd4, 0xd6, 0xae, 0xfd, 0xb8, 0x34, 0xf9, 0xd1, 0x9b, 0xcf, 0x1b, 0x3f,
+	0xb3, 0x9c, 0x7f, 0x5a, 0x30, 0xac, 0xef, 0xa9, 0x98, 0xdd, 0x07, 0x4b, 0x56, 0xa7, 0x2d, 0x49,
+	0x3b, 0xc3, 0x61, 0x4b, 0x89, 0x2f, 0xa0, 0x27, 0x31, 0xc8, 0xae, 0x51, 0x6e, 0xa8, 0xc7, 0x34,
+	0xf9, 0x51, 0xef, 0x4f, 0xf5, 0xbc, 0x9f, 0x9a, 0x79, 0x3f, 0x3d, 0x4f, 0x8b, 0xcf, 0x66, 0xbf,
+	0xa5, 0x5b, 0x5c, 0x30, 0xfa, 0xe7, 0xe1, 0x0e, 0xef, 0x5b, 0xff, 0x05, 0xef, 0xdb, 0xef, 0xe2,
+	0xfd, 0xff, 0x4e, 0xe2, 0xa4, 0x8e, 0xf2, 0xff, 0x60, 0xf1, 0xa3, 0x5b, 0x2c, 0x3e, 0xde, 0x7f,
+	0x9f, 0x5b, 0x34, 0x16, 0x30, 0x7a, 0x8e, 0x45, 0x15, 0x97, 0x7e, 0x3e, 0xe7, 0x8f, 0x0d, 0x38,
+	0xda, 0x01, 0xab, 0x5c, 0x53, 0xcb, 0xa6, 0x29, 0xec, 0x91, 0xab, 0xec, 0x4a, 0xd3, 0xb5, 0x19,
+	0xa1, 0x60, 0xc4, 0x43, 0x38, 0x52, 0x65, 0x10, 0xa0, 0x52, 0xcb, 0x32, 0x26, 0x02, 0xaf, 0x30,
+	0x65, 0x1f, 0x5a, 0xee, 0x68, 0x2b, 0xd0, 0xe5, 0x44, 0x9d, 0x8c, 0xbe, 0x38, 0x18, 0x1a, 0xc5,
+	0x26, 0x2b, 0xf6, 0x35, 0x58, 0x29, 0xfd, 0x08, 0xc4, 0xbe, 0x45, 0xaa, 0x04, 0x7e, 0x8e, 0x96,
+	0x7b, 0xb4, 0x67, 0x92, 0x04, 0xd4, 0xea, 0xb7, 0x36, 0x59, 0xb5, 0xcd, 0xaa, 0x83, 0xda, 0x28,
+	0xab, 0x9d, 0xc1, 0x71, 0x2e, 0xd1, 0xcb, 0x7d, 0xe9, 0x27, 0x1e, 0x97, 0x6b, 0x88, 0x79, 0xb1,
+	0xe6, 0xd7, 0x69, 0xbb, 0x47, 0xb9, 0xc4, 0x39, 0x89, 0xe6, 0x59, 0x16, 0x3f, 0x25, 0x81, 0x73,
+	0x0f, 0xee, 0x3c, 0xc7, 0xe2, 0x6b, 0x9a, 0xa2, 0x34, 0xce, 0xce, 0x9f, 0x9a, 0x34, 0x3d, 0x86,
+	0xbb, 0x37, 0x05, 0x3b, 0x0d, 0x97, 0x66, 0x65, 0x3d, 0xda, 0x3a, 0xb4, 0x3d, 0x0f, 0x9d, 0x23,
+	0x18, 0x7e, 0x1d, 0xa9, 0x82, 0x7c, 0x31, 0x56, 0x7e, 0x02, 0xa3, 0x2d, 0x54, 0x9d, 0x7f, 0xb0,
+	0x33, 0x51, 0x7a, 0xb3, 0x01, 0x3f, 0x21, 0xdf, 0xf3, 0x02, 0x37, 0x7a, 0xc0, 0x38, 0xa7, 0x30,
+	0x78, 0x8e, 0x74, 0xca, 0x34, 0x9f, 0x77, 0x35, 0x79, 0xe7, 0xcf, 0x0d, 0xe8, 0x9a, 0xc3, 0xef,
+	0xd4, 0xaa, 0xfb, 0x4a, 0x63, 0xa7, 0xaf, 0x1c, 0x43, 0x9b, 0x88, 0x6e, 0x3e, 0x74, 0x7a, 0x23,
+	0x3e, 0x85, 0x11, 0x0d, 0xe1, 0x28, 0x88, 0x72, 0x3f, 0x2d, 0x74, 0xab, 0x6a, 0x71, 0xab, 0x1a,
+	0xee, 0xe0, 0xdc, 0xac, 0x3e, 0x81, 0xa1, 0xfe, 0x8d, 0xe8, 0x61, 0x4e, 0xb7, 0xea, 0xc6, 0x34,
+	0x60, 0x98, 0xbf, 0x7a, 0x74, 0xf9, 0xde, 0xb4, 0xec, 0xdc, 0x9c, 0x96, 0x1f, 0x40, 0x57, 0xcf,
+	0xf0, 0x28, 0xe4, 0x8e, 0x64, 0xbb, 0x07, 0xbc, 0xe7, 0x2a, 0x1d, 0xe4, 0xe5, 0x22, 0x8e, 0x02,
+	0x8f, 0x11, 0xc5, 0x4d, 0xc9, 0x76, 0xfb, 0x1a, 0xe4, 0x7f, 0x22, 0x7f, 0x12, 0x92, 0x2c, 0x8c,
+	0x96, 0x11, 0x86, 0x9e, 0x5f, 0x8c, 0x6d, 0xfd, 0x49, 0x30, 0xd0, 0x93, 0x82, 0xe2, 0x44, 0x29,
+	0x33, 0x39, 0x06, 0x1d, 0x27, 0x6f, 0x7e, 0xf0, 0x14, 0x3a, 0xba, 0x02, 0x44, 0x07, 0x1a, 0x2f,
+	0x9f, 0x8c, 0xde, 0xa3, 0xaf, 0xea, 0xa5, 0xa6, 0xdd, 0xc8, 0x12, 0x5d, 0x68, 0x3d, 0xf3, 0xa3,
+	0x78, 0xd4, 0x10, 0x03, 0xb0, 0xbf, 0xf4, 0xd3, 0x00, 0xe3, 0xd8, 0x7c, 0x46, 0x5d, 0xfc, 0x3d,
+	0x06, 0xf4, 0x35, 0x6d, 0xcd, 0xfe, 0xde, 0x84, 0xe6, 0x2b, 0xa5, 0xc4, 0x63, 0xe8, 0x54, 0xb4,
+	0x16, 0x7b, 0x73, 0x85, 0x1f, 0x6f, 0xf2, 0xb6, 0x59, 0x23, 0x7e, 0x0e, 0x7d, 0x8d, 0x5c, 0x16,
+	0x12, 0xfd, 0xe4, 0x5b, 0x0f, 0x9a, 0x8e, 0xf0, 0xc8, 0x12, 0x3f, 0x86, 0x83, 0xaa, 0xe4, 0xc5,
+	0xfb, 0x6f, 0x69, 0xd0, 0x93, 0xb7, 0x76, 0x05, 0xf1, 0x05, 0x0c, 0x2a, 0xa8, 0xba, 0xf1, 0xdb,
+	0xcf, 0xee, 0xdc, 0xf9, 0x39, 0xd8, 0x75, 0xd3, 0x10, 0x77, 0x58, 0xe9, 0x66, 0x67, 0x99, 0xdc,
+	0xbd, 0x09, 0x57, 0x37, 0x9f, 0xc3, 0xe1, 0x7e, 0x29, 0x89, 0x89, 0xd1, 0xbc, 0x5d, 0x78, 0x93,
+	0xfb, 0x6f, 0x95, 0x55, 0xa6, 0x7e, 0x0a, 0x5d, 0x53, 0x4f, 0x42, 0xbb, 0x7a, 0xa3, 0xe2, 0x26,
+	0x77, 0x6e, 0xa0, 0xd5, 0xc1, 0x87, 0xd0, 0xd1, 0x15, 0x55, 0x25, 0x7a, 0xaf, 0xbc, 0x26, 0xfb,
+	0x45, 0xb8, 0xe8, 0xf0, 0xfc, 0xf8, 0xec, 0x3f, 0x03, 0x00, 0x6c, 0xc1, 0x61, 0xa2, 0x4f, 0x0e,
+	0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// TssClient is the client API for Tss service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type TssClient interface {
+	Keygen(ctx context.Context, in *KeygenRequest, opts ...grpc.CallOption) (*KeygenResponse, error)
+	// KeygenStream sends the progress of the keygen, the last one carries the response
+	KeygenStream(ctx context.Context, in *KeygenRequest, opts ...grpc.CallOption) (Tss_KeygenStreamClient, error)
+	KeySign(ctx context.Context, in *KeySignRequest, opts ...grpc.CallOption) (*KeySignResponse, error)
+	// KeySignStream sends the progress of the keysign, the last one carries the response
+	KeySignStream(ctx context.Context, in *KeySignRequest, opts ...grpc.CallOption) (Tss_KeySignStreamClient, error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	GetLocalPeerID(ctx context.Context, in *GetLocalPeerIDRequest, opts ...grpc.CallOption) (*GetLocalPeerIDResponse, error)
+	ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error)
+	GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*LocalKey, error)
+}
+
+type tssClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTssClient(cc grpc.ClientConnInterface) TssClient {
+	return &tssClient{cc}
+}
+
+func (c *tssClient) Keygen(ctx context.Context, in *KeygenRequest, opts ...grpc.CallOption) (*KeygenResponse, error) {
+	out := new(KeygenResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/Keygen", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tssClient) KeygenStream(ctx context.Context, in *KeygenRequest, opts ...grpc.CallOption) (Tss_KeygenStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Tss_serviceDesc.Streams[0], "/rpc.Tss/KeygenStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tssKeygenStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tss_KeygenStreamClient interface {
+	Recv() (*KeygenProgress, error)
+	grpc.ClientStream
+}
+
+type tssKeygenStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *tssKeygenStreamClient) Recv() (*KeygenProgress, error) {
+	m := new(KeygenProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tssClient) KeySign(ctx context.Context, in *KeySignRequest, opts ...grpc.CallOption) (*KeySignResponse, error) {
+	out := new(KeySignResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/KeySign", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tssClient) KeySignStream(ctx context.Context, in *KeySignRequest, opts ...grpc.CallOption) (Tss_KeySignStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Tss_serviceDesc.Streams[1], "/rpc.Tss/KeySignStream", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &tssKeySignStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Tss_KeySignStreamClient interface {
+	Recv() (*KeySignProgress, error)
+	grpc.ClientStream
+}
+
+type tssKeySignStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *tssKeySignStreamClient) Recv() (*KeySignProgress, error) {
+	m := new(KeySignProgress)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *tssClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/GetStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tssClient) GetLocalPeerID(ctx context.Context, in *GetLocalPeerIDRequest, opts ...grpc.CallOption) (*GetLocalPeerIDResponse, error) {
+	out := new(GetLocalPeerIDResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/GetLocalPeerID", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tssClient) ListKeys(ctx context.Context, in *ListKeysRequest, opts ...grpc.CallOption) (*ListKeysResponse, error) {
+	out := new(ListKeysResponse)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/ListKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tssClient) GetKey(ctx context.Context, in *GetKeyRequest, opts ...grpc.CallOption) (*LocalKey, error) {
+	out := new(LocalKey)
+	err := c.cc.Invoke(ctx, "/rpc.Tss/GetKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TssServer is the server API for Tss service.
+type TssServer interface {
+	Keygen(context.Context, *KeygenRequest) (*KeygenResponse, error)
+	// KeygenStream sends the progress of the keygen, the last one carries the response
+	KeygenStream(*KeygenRequest, Tss_KeygenStreamServer) error
+	KeySign(context.Context, *KeySignRequest) (*KeySignResponse, error)
+	// KeySignStream sends the progress of the keysign, the last one carries the response
+	KeySignStream(*KeySignRequest, Tss_KeySignStreamServer) error
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	GetLocalPeerID(context.Context, *GetLocalPeerIDRequest) (*GetLocalPeerIDResponse, error)
+	ListKeys(context.Context, *ListKeysRequest) (*ListKeysResponse, error)
+	GetKey(context.Context, *GetKeyRequest) (*LocalKey, error)
+}
+
+// UnimplementedTssServer can be embedded to have forward compatible implementations.
+type UnimplementedTssServer struct {
+}
+
+func (*UnimplementedTssServer) Keygen(ctx context.Context, req *KeygenRequest) (*KeygenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Keygen not implemented")
+}
+func (*UnimplementedTssServer) KeygenStream(req *KeygenRequest, srv Tss_KeygenStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method KeygenStream not implemented")
+}
+func (*UnimplementedTssServer) KeySign(ctx context.Context, req *KeySignRequest) (*KeySignResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KeySign not implemented")
+}
+func (*UnimplementedTssServer) KeySignStream(req *KeySignRequest, srv Tss_KeySignStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method KeySignStream not implemented")
+}
+func (*UnimplementedTssServer) GetStatus(ctx context.Context, req *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (*UnimplementedTssServer) GetLocalPeerID(ctx context.Context, req *GetLocalPeerIDRequest) (*GetLocalPeerIDResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLocalPeerID not implemented")
+}
+func (*UnimplementedTssServer) ListKeys(ctx context.Context, req *ListKeysRequest) (*ListKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListKeys not implemented")
+}
+func (*UnimplementedTssServer) GetKey(ctx context.Context, req *GetKeyRequest) (*LocalKey, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetKey not implemented")
+}
+
+func RegisterTssServer(s *grpc.Server, srv TssServer) {
+	s.RegisterService(&_Tss_serviceDesc, srv)
+}
+
+func _Tss_Keygen_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeygenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).Keygen(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/Keygen",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).Keygen(ctx, req.(*KeygenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tss_KeygenStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(KeygenRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TssServer).KeygenStream(m, &tssKeygenStreamServer{stream})
+}
+
+type Tss_KeygenStreamServer interface {
+	Send(*KeygenProgress) error
+	grpc.ServerStream
+}
+
+type tssKeygenStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *tssKeygenStreamServer) Send(m *KeygenProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Tss_KeySign_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KeySignRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).KeySign(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/KeySign",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).KeySign(ctx, req.(*KeySignRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tss_KeySignStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(KeySignRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TssServer).KeySignStream(m, &tssKeySignStreamServer{stream})
+}
+
+type Tss_KeySignStreamServer interface {
+	Send(*KeySignProgress) error
+	grpc.ServerStream
+}
+
+type tssKeySignStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *tssKeySignStreamServer) Send(m *KeySignProgress) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Tss_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/GetStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tss_GetLocalPeerID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLocalPeerIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).GetLocalPeerID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/GetLocalPeerID",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).GetLocalPeerID(ctx, req.(*GetLocalPeerIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tss_ListKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).ListKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/ListKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).ListKeys(ctx, req.(*ListKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Tss_GetKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TssServer).GetKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpc.Tss/GetKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TssServer).GetKey(ctx, req.(*GetKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Tss_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "rpc.Tss",
+	HandlerType: (*TssServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Keygen",
+			Handler:    _Tss_Keygen_Handler,
+		},
+		{
+			MethodName: "KeySign",
+			Handler:    _Tss_KeySign_Handler,
+		},
+		{
+			MethodName: "GetStatus",
+			Handler:    _Tss_GetStatus_Handler,
+		},
+		{
+			MethodName: "GetLocalPeerID",
+			Handler:    _Tss_GetLocalPeerID_Handler,
+		},
+		{
+			MethodName: "ListKeys",
+			Handler:    _Tss_ListKeys_Handler,
+		},
+		{
+			MethodName: "GetKey",
+			Handler:    _Tss_GetKey_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "KeygenStream",
+			Handler:       _Tss_KeygenStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "KeySignStream",
+			Handler:       _Tss_KeySignStream_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "tss.proto",
+}
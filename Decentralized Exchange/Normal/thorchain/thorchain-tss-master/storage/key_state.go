@@ -0,0 +1,47 @@
+package storage
+
+import "fmt"
+
+// KeyState is the lifecycle state of a pool key held by the local node
+type KeyState string
+
+const (
+	// KeyActive is the key that signs any request, the key saved without the state is active
+	KeyActive KeyState = "active"
+	// KeyRetiring is the key that only signs the requests to migrate the funds to the new pool
+	KeyRetiring KeyState = "retiring"
+	// KeyRetired is the key that doesn't sign any more, the share is still kept on the node
+	KeyRetired KeyState = "retired"
+	// KeyArchived is the retired key whose share has been moved to the encrypted archive
+	KeyArchived KeyState = "archived"
+)
+
+// keyStateTransitions lists the states each state can move to, a retiring key can be put back to active in case
+// the migration is cancelled, an archived key is gone from the live storage and can't move any more
+var keyStateTransitions = map[KeyState][]KeyState{
+	KeyActive:   {KeyRetiring, KeyRetired},
+	KeyRetiring: {KeyActive, KeyRetired},
+	KeyRetired:  {KeyArchived},
+}
+
+// GetKeyState return the key state of the given name, the empty name is active
+func GetKeyState(name string) (KeyState, error) {
+	switch KeyState(name) {
+	case "", KeyActive:
+		return KeyActive, nil
+	case KeyRetiring, KeyRetired, KeyArchived:
+		return KeyState(name), nil
+	default:
+		return "", fmt.Errorf("unknown key state(%s)", name)
+	}
+}
+
+// CanTransitionTo tell whether the key in this state can move to the given state
+func (s KeyState) CanTransitionTo(to KeyState) bool {
+	for _, el := range keyStateTransitions[s] {
+		if el == to {
+			return true
+		}
+	}
+	return false
+}
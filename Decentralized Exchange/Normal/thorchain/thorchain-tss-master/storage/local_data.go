@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/binance-chain/tss-lib/crypto/paillier"
+	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsakeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+)
+
+// ecPoint is the json form of crypto.ECPoint, tss-lib checks the point against the curve it is using at the moment
+// when it unmarshal the point, which is the wrong one while a session of the other algorithm is running, so we decode
+// the points of the key share with the curve of the key instead
+type ecPoint struct {
+	Coords [2]*big.Int
+}
+
+func (p *ecPoint) toECPoint(curve elliptic.Curve) (*crypto.ECPoint, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if p.Coords[0] == nil || p.Coords[1] == nil {
+		return nil, errors.New("invalid point")
+	}
+	return crypto.NewECPoint(curve, p.Coords[0], p.Coords[1])
+}
+
+func toECPoints(curve elliptic.Curve, points []*ecPoint) ([]*crypto.ECPoint, error) {
+	if points == nil {
+		return nil, nil
+	}
+	result := make([]*crypto.ECPoint, len(points))
+	for i, el := range points {
+		p, err := el.toECPoint(curve)
+		if err != nil {
+			return nil, fmt.Errorf("fail to decode the point(%d): %w", i, err)
+		}
+		result[i] = p
+	}
+	return result, nil
+}
+
+// UnmarshalJSON decode the local state with the key shares on their own curves
+func (s *KeygenLocalState) UnmarshalJSON(buf []byte) error {
+	type localState KeygenLocalState
+	aux := struct {
+		*localState
+		LocalData      json.RawMessage `json:"local_data"`
+		EdDSALocalData json.RawMessage `json:"eddsa_local_data,omitempty"`
+	}{
+		localState: (*localState)(s),
+	}
+	if err := json.Unmarshal(buf, &aux); err != nil {
+		return err
+	}
+	var err error
+	s.LocalData, err = decodeECDSALocalData(aux.LocalData)
+	if err != nil {
+		return fmt.Errorf("fail to decode the ECDSA local data: %w", err)
+	}
+	s.EdDSALocalData, err = decodeEdDSALocalData(aux.EdDSALocalData)
+	if err != nil {
+		return fmt.Errorf("fail to decode the EdDSA local data: %w", err)
+	}
+	return nil
+}
+
+func decodeECDSALocalData(buf json.RawMessage) (keygen.LocalPartySaveData, error) {
+	var data keygen.LocalPartySaveData
+	if isEmptyJSON(buf) {
+		return data, nil
+	}
+	aux := struct {
+		keygen.LocalPreParams
+		keygen.LocalSecrets
+		Ks                []*big.Int
+		NTildej, H1j, H2j []*big.Int
+		BigXj             []*ecPoint
+		PaillierPKs       []*paillier.PublicKey
+		ECDSAPub          *ecPoint
+	}{}
+	if err := json.Unmarshal(buf, &aux); err != nil {
+		return data, err
+	}
+	bigXj, err := toECPoints(btcec.S256(), aux.BigXj)
+	if err != nil {
+		return data, err
+	}
+	pub, err := aux.ECDSAPub.toECPoint(btcec.S256())
+	if err != nil {
+		return data, fmt.Errorf("fail to decode the pub key: %w", err)
+	}
+	data.LocalPreParams = aux.LocalPreParams
+	data.LocalSecrets = aux.LocalSecrets
+	data.Ks = aux.Ks
+	data.NTildej, data.H1j, data.H2j = aux.NTildej, aux.H1j, aux.H2j
+	data.BigXj = bigXj
+	data.PaillierPKs = aux.PaillierPKs
+	data.ECDSAPub = pub
+	return data, nil
+}
+
+func decodeEdDSALocalData(buf json.RawMessage) (*eddsakeygen.LocalPartySaveData, error) {
+	if isEmptyJSON(buf) {
+		return nil, nil
+	}
+	aux := struct {
+		eddsakeygen.LocalSecrets
+		Ks       []*big.Int
+		BigXj    []*ecPoint
+		EDDSAPub *ecPoint
+	}{}
+	if err := json.Unmarshal(buf, &aux); err != nil {
+		return nil, err
+	}
+	bigXj, err := toECPoints(edwards.Edwards(), aux.BigXj)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := aux.EDDSAPub.toECPoint(edwards.Edwards())
+	if err != nil {
+		return nil, fmt.Errorf("fail to decode the pub key: %w", err)
+	}
+	return &eddsakeygen.LocalPartySaveData{
+		LocalSecrets: aux.LocalSecrets,
+		Ks:           aux.Ks,
+		BigXj:        bigXj,
+		EDDSAPub:     pub,
+	}, nil
+}
+
+func isEmptyJSON(buf json.RawMessage) bool {
+	return len(buf) == 0 || string(buf) == "null"
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/binance-chain/tss-lib/crypto"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+// LocalStateInfo is the public part of a local state, it never carries the secret share Xi or the pre-parameters,
+// so it is safe to be handed to the operator
+type LocalStateInfo struct {
+	PubKey          string      `json:"pub_key"`
+	Algo            common.Algo `json:"algo"`
+	State           KeyState    `json:"state"`
+	ParticipantKeys []string    `json:"participant_keys"`
+	LocalPartyKey   string      `json:"local_party_key"`
+	Threshold       int         `json:"threshold"`
+	ShareID         string      `json:"share_id"`      // decimal
+	PublicShares    []string    `json:"public_shares"` // hex of the compressed BigXj, in the order of the parties
+	ModifiedAt      time.Time   `json:"modified_at"`
+	Error           string      `json:"error,omitempty"` // set when the local state cannot be read
+}
+
+// NewLocalStateInfo return the public part of the given local state, the modification time is the one of the file
+// the state is saved to
+func NewLocalStateInfo(state KeygenLocalState, modifiedAt time.Time) (LocalStateInfo, error) {
+	info := LocalStateInfo{
+		PubKey:          state.PubKey,
+		Algo:            state.GetAlgo(),
+		State:           state.GetState(),
+		ParticipantKeys: state.ParticipantKeys,
+		LocalPartyKey:   state.LocalPartyKey,
+		ModifiedAt:      modifiedAt,
+	}
+	threshold, err := state.GetThreshold()
+	if err != nil {
+		return info, err
+	}
+	info.Threshold = threshold
+	if shareID := state.GetShareID(); shareID != nil {
+		info.ShareID = shareID.String()
+	}
+	var bigXj []*crypto.ECPoint
+	if info.Algo == common.EdDSA {
+		if state.EdDSALocalData != nil {
+			bigXj = state.EdDSALocalData.BigXj
+		}
+	} else {
+		bigXj = state.LocalData.BigXj
+	}
+	info.PublicShares = make([]string, len(bigXj))
+	for i, el := range bigXj {
+		if el == nil {
+			return info, errors.New("invalid local state, the public share is missing")
+		}
+		info.PublicShares[i] = hex.EncodeToString(serializePoint(info.Algo, el))
+	}
+	return info, nil
+}
+
+func serializePoint(algo common.Algo, p *crypto.ECPoint) []byte {
+	if algo == common.EdDSA {
+		return edwards.NewPublicKey(p.X(), p.Y()).Serialize()
+	}
+	pk := btcec.PublicKey{
+		Curve: btcec.S256(),
+		X:     p.X(),
+		Y:     p.Y(),
+	}
+	return pk.SerializeCompressed()
+}
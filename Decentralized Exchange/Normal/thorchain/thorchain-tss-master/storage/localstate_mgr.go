@@ -6,16 +6,19 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"strings"
 	"sync"
 
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsakeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 	ma "github.com/multiformats/go-multiaddr"
 
+	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 )
 
@@ -25,6 +28,47 @@ type KeygenLocalState struct {
 	LocalData       keygen.LocalPartySaveData `json:"local_data"`
 	ParticipantKeys []string                  `json:"participant_keys"` // the paticipant of last key gen
 	LocalPartyKey   string                    `json:"local_party_key"`
+	Threshold       int                       `json:"threshold,omitempty"` // zero for the key generated with the default threshold
+	Algo            common.Algo               `json:"algo,omitempty"`      // empty for the ECDSA key
+	// EdDSALocalData is the key share of the EdDSA key, LocalData is left empty in this case
+	EdDSALocalData *eddsakeygen.LocalPartySaveData `json:"eddsa_local_data,omitempty"`
+	State          KeyState                        `json:"state,omitempty"` // empty for the active key
+}
+
+// GetState return the lifecycle state of the key, the key saved without the state is active
+func (s KeygenLocalState) GetState() KeyState {
+	if s.State == "" {
+		return KeyActive
+	}
+	return s.State
+}
+
+// GetAlgo return the algorithm of the key, the key saved without the algorithm is ECDSA
+func (s KeygenLocalState) GetAlgo() common.Algo {
+	if s.Algo == "" {
+		return common.ECDSA
+	}
+	return s.Algo
+}
+
+// GetShareID return the x-coordinate of the local key share, which carries the tag of the party key once the key
+// is reshared
+func (s KeygenLocalState) GetShareID() *big.Int {
+	if s.GetAlgo() == common.EdDSA {
+		if s.EdDSALocalData == nil {
+			return nil
+		}
+		return s.EdDSALocalData.ShareID
+	}
+	return s.LocalData.ShareID
+}
+
+// GetThreshold return the threshold of the key, the key generated without the threshold stored uses the default one
+func (s KeygenLocalState) GetThreshold() (int, error) {
+	if s.Threshold > 0 {
+		return s.Threshold, nil
+	}
+	return common.GetThreshold(len(s.ParticipantKeys))
 }
 
 // LocalStateManager provide necessary methods to manage the local state, save it , and read it back
@@ -32,10 +76,22 @@ type KeygenLocalState struct {
 type LocalStateManager interface {
 	SaveLocalState(state KeygenLocalState) error
 	GetLocalState(pubKey string) (KeygenLocalState, error)
+	ListLocalStates() ([]LocalStateInfo, error)
+	GetLocalStateInfo(pubKey string) (LocalStateInfo, error)
+	ArchiveLocalState(pubKey string, buf []byte) error
 	SaveAddressBook(addressBook map[peer.ID]addr.AddrList) error
 	RetrieveP2PAddresses() (addr.AddrList, error)
+	SavePreParamPool(buf []byte) error
+	RetrievePreParamPool() ([]byte, error)
 }
 
+const (
+	localStateFilePrefix = "localstate-"
+	localStateFileSuffix = ".json"
+	archiveFolder        = "archive"
+	archiveFileSuffix    = ".dat"
+)
+
 // FileStateMgr save the local state to file
 type FileStateMgr struct {
 	folder    string
@@ -67,7 +123,7 @@ func (fsm *FileStateMgr) getFilePathName(pubKey string) (string, error) {
 		return "", errors.New("invalid pubkey for file name")
 	}
 
-	localFileName := fmt.Sprintf("localstate-%s.json", pubKey)
+	localFileName := localStateFilePrefix + pubKey + localStateFileSuffix
 	if len(fsm.folder) > 0 {
 		return filepath.Join(fsm.folder, localFileName), nil
 	}
@@ -99,7 +155,10 @@ func (fsm *FileStateMgr) GetLocalState(pubKey string) (KeygenLocalState, error)
 	if _, err := os.Stat(filePathName); os.IsNotExist(err) {
 		return KeygenLocalState{}, err
 	}
+	return readLocalState(filePathName)
+}
 
+func readLocalState(filePathName string) (KeygenLocalState, error) {
 	buf, err := ioutil.ReadFile(filePathName)
 	if err != nil {
 		return KeygenLocalState{}, fmt.Errorf("file to read from file(%s): %w", filePathName, err)
@@ -111,6 +170,100 @@ func (fsm *FileStateMgr) GetLocalState(pubKey string) (KeygenLocalState, error)
 	return localState, nil
 }
 
+// ListLocalStates return the public part of all the local states in the folder, ordered by the pub key, the state
+// that cannot be read is listed with the error rather than failing the whole list
+func (fsm *FileStateMgr) ListLocalStates() ([]LocalStateInfo, error) {
+	files, err := filepath.Glob(filepath.Join(fsm.folder, localStateFilePrefix+"*"+localStateFileSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("fail to list the local state files: %w", err)
+	}
+	result := make([]LocalStateInfo, 0, len(files))
+	for _, el := range files {
+		pubKey := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(el), localStateFilePrefix), localStateFileSuffix)
+		fileInfo, err := os.Stat(el)
+		if err != nil {
+			result = append(result, LocalStateInfo{PubKey: pubKey, Error: err.Error()})
+			continue
+		}
+		result = append(result, readLocalStateInfo(pubKey, el, fileInfo))
+	}
+	return result, nil
+}
+
+// GetLocalStateInfo return the public part of the local state of the given pub key, only the file of the key is read,
+// os.ErrNotExist is returned when the local node doesn't hold the key, the state that cannot be read is returned
+// with the error, the same as ListLocalStates
+func (fsm *FileStateMgr) GetLocalStateInfo(pubKey string) (LocalStateInfo, error) {
+	if len(pubKey) == 0 {
+		return LocalStateInfo{}, errors.New("pub key is empty")
+	}
+	filePathName, err := fsm.getFilePathName(pubKey)
+	if err != nil {
+		return LocalStateInfo{}, err
+	}
+	fileInfo, err := os.Stat(filePathName)
+	if err != nil {
+		return LocalStateInfo{}, err
+	}
+	return readLocalStateInfo(pubKey, filePathName, fileInfo), nil
+}
+
+func readLocalStateInfo(pubKey, filePathName string, fileInfo os.FileInfo) LocalStateInfo {
+	state, err := readLocalState(filePathName)
+	if err != nil {
+		return LocalStateInfo{PubKey: pubKey, ModifiedAt: fileInfo.ModTime(), Error: err.Error()}
+	}
+	info, err := NewLocalStateInfo(state, fileInfo.ModTime())
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
+
+// ArchiveLocalState save the given (encrypted) local state to the archive folder, and then wipe the live local
+// state file of the key, so the share can't be used for signing any more
+func (fsm *FileStateMgr) ArchiveLocalState(pubKey string, buf []byte) error {
+	filePathName, err := fsm.getFilePathName(pubKey)
+	if err != nil {
+		return err
+	}
+	fileInfo, err := os.Stat(filePathName)
+	if err != nil {
+		return err
+	}
+	archivePath := filepath.Join(fsm.folder, archiveFolder)
+	if err := os.MkdirAll(archivePath, 0700); err != nil {
+		return fmt.Errorf("fail to create the archive folder: %w", err)
+	}
+	archiveFilePathName := filepath.Join(archivePath, localStateFilePrefix+pubKey+archiveFileSuffix)
+	fsm.writeLock.Lock()
+	defer fsm.writeLock.Unlock()
+	if err := ioutil.WriteFile(archiveFilePathName, buf, 0600); err != nil {
+		return fmt.Errorf("fail to write the archive file: %w", err)
+	}
+	return wipeFile(filePathName, fileInfo.Size())
+}
+
+// wipeFile overwrite the file with zeros before removing it, so the share doesn't stay on the disk
+func wipeFile(filePathName string, size int64) error {
+	f, err := os.OpenFile(filePathName, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("fail to open file(%s): %w", filePathName, err)
+	}
+	if _, err := f.Write(make([]byte, size)); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fail to wipe file(%s): %w", filePathName, err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("fail to sync file(%s): %w", filePathName, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("fail to close file(%s): %w", filePathName, err)
+	}
+	return os.Remove(filePathName)
+}
+
 func (fsm *FileStateMgr) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	if len(fsm.folder) < 1 {
 		return errors.New("base file path is invalid")
@@ -168,3 +321,25 @@ func (fsm *FileStateMgr) RetrieveP2PAddresses() (addr.AddrList, error) {
 	}
 	return peerAddresses, nil
 }
+
+// SavePreParamPool save the (encrypted) pre-parameter pool to file
+func (fsm *FileStateMgr) SavePreParamPool(buf []byte) error {
+	if len(fsm.folder) < 1 {
+		return errors.New("base file path is invalid")
+	}
+	filePathName := filepath.Join(fsm.folder, "preparam_pool.dat")
+	fsm.writeLock.Lock()
+	defer fsm.writeLock.Unlock()
+	return ioutil.WriteFile(filePathName, buf, 0600)
+}
+
+// RetrievePreParamPool read the (encrypted) pre-parameter pool from file
+func (fsm *FileStateMgr) RetrievePreParamPool() ([]byte, error) {
+	if len(fsm.folder) < 1 {
+		return nil, errors.New("base file path is invalid")
+	}
+	filePathName := filepath.Join(fsm.folder, "preparam_pool.dat")
+	fsm.writeLock.RLock()
+	defer fsm.writeLock.RUnlock()
+	return ioutil.ReadFile(filePathName)
+}
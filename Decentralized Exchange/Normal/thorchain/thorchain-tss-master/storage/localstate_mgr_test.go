@@ -1,18 +1,30 @@
 package storage
 
 import (
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/binance-chain/tss-lib/crypto"
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
+	eddsakeygen "github.com/binance-chain/tss-lib/eddsa/keygen"
+	btss "github.com/binance-chain/tss-lib/tss"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/decred/dcrd/dcrec/edwards/v2"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 	tnet "github.com/libp2p/go-libp2p-testing/net"
 	maddr "github.com/multiformats/go-multiaddr"
 	. "gopkg.in/check.v1"
 
+	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 )
 
@@ -103,3 +115,257 @@ func (s *FileStateMgrTestSuite) TestSaveAddressBook(c *C) {
 	c.Assert(err, IsNil)
 	c.Assert(item, HasLen, 3)
 }
+
+func (s *FileStateMgrTestSuite) TestSavePreParamPool(c *C) {
+	folder := os.TempDir()
+	f := filepath.Join(folder, "test_preparam")
+	defer func() {
+		err := os.RemoveAll(f)
+		c.Assert(err, IsNil)
+	}()
+	fsm, err := NewFileStateMgr(f)
+	c.Assert(err, IsNil)
+	_, err = fsm.RetrievePreParamPool()
+	c.Assert(errors.Is(err, os.ErrNotExist), Equals, true)
+	c.Assert(fsm.SavePreParamPool([]byte("whatever")), IsNil)
+	buf, err := fsm.RetrievePreParamPool()
+	c.Assert(err, IsNil)
+	c.Assert(string(buf), Equals, "whatever")
+
+	fsm1, err := NewFileStateMgr("")
+	c.Assert(err, IsNil)
+	c.Assert(fsm1.SavePreParamPool([]byte("whatever")), NotNil)
+	_, err = fsm1.RetrievePreParamPool()
+	c.Assert(err, NotNil)
+}
+
+func (s *FileStateMgrTestSuite) TestGetThreshold(c *C) {
+	stateItem := KeygenLocalState{
+		ParticipantKeys: []string{
+			"A", "B", "C", "D", "E",
+		},
+	}
+	threshold, err := stateItem.GetThreshold()
+	c.Assert(err, IsNil)
+	c.Assert(threshold, Equals, 3)
+	stateItem.Threshold = 2
+	threshold, err = stateItem.GetThreshold()
+	c.Assert(err, IsNil)
+	c.Assert(threshold, Equals, 2)
+}
+
+func (s *FileStateMgrTestSuite) TestGetAlgo(c *C) {
+	stateItem := KeygenLocalState{}
+	c.Assert(stateItem.GetAlgo(), Equals, common.ECDSA)
+	stateItem.LocalData.ShareID = big.NewInt(1)
+	c.Assert(stateItem.GetShareID().Int64(), Equals, int64(1))
+	stateItem.Algo = common.EdDSA
+	c.Assert(stateItem.GetAlgo(), Equals, common.EdDSA)
+	c.Assert(stateItem.GetShareID(), IsNil)
+	stateItem.EdDSALocalData = &eddsakeygen.LocalPartySaveData{}
+	stateItem.EdDSALocalData.ShareID = big.NewInt(2)
+	c.Assert(stateItem.GetShareID().Int64(), Equals, int64(2))
+}
+
+func (s *FileStateMgrTestSuite) TestUnmarshalLocalState(c *C) {
+	ecdsaState := KeygenLocalState{
+		LocalData: keygen.NewLocalPartySaveData(2),
+	}
+	ecdsaState.LocalData.BigXj[0] = crypto.ScalarBaseMult(btcec.S256(), big.NewInt(1))
+	ecdsaState.LocalData.ECDSAPub = crypto.ScalarBaseMult(btcec.S256(), big.NewInt(2))
+	eddsaLocalData := eddsakeygen.NewLocalPartySaveData(2)
+	eddsaLocalData.BigXj[1] = crypto.ScalarBaseMult(edwards.Edwards(), big.NewInt(1))
+	eddsaLocalData.EDDSAPub = crypto.ScalarBaseMult(edwards.Edwards(), big.NewInt(2))
+	eddsaState := KeygenLocalState{
+		Algo:           common.EdDSA,
+		EdDSALocalData: &eddsaLocalData,
+	}
+
+	// the key shares are decoded with their own curves whatever curve tss-lib is using
+	for _, curve := range []elliptic.Curve{btcec.S256(), edwards.Edwards()} {
+		btss.SetCurve(curve)
+		for _, stateItem := range []KeygenLocalState{ecdsaState, eddsaState} {
+			buf, err := json.Marshal(stateItem)
+			c.Assert(err, IsNil)
+			var item KeygenLocalState
+			c.Assert(json.Unmarshal(buf, &item), IsNil)
+			c.Assert(item.Algo, Equals, stateItem.Algo)
+			if stateItem.EdDSALocalData != nil {
+				c.Assert(item.EdDSALocalData.EDDSAPub.Equals(eddsaLocalData.EDDSAPub), Equals, true)
+				c.Assert(item.EdDSALocalData.BigXj[0], IsNil)
+				c.Assert(item.EdDSALocalData.BigXj[1].Equals(eddsaLocalData.BigXj[1]), Equals, true)
+			} else {
+				c.Assert(item.EdDSALocalData, IsNil)
+				c.Assert(item.LocalData.ECDSAPub.Equals(ecdsaState.LocalData.ECDSAPub), Equals, true)
+				c.Assert(item.LocalData.BigXj[0].Equals(ecdsaState.LocalData.BigXj[0]), Equals, true)
+			}
+		}
+	}
+	btss.SetCurve(btcec.S256())
+
+	// the point has to be on the curve of the key
+	buf := []byte(`{"algo":"eddsa","eddsa_local_data":{"EDDSAPub":{"Coords":[1,2]}}}`)
+	var item KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &item), NotNil)
+}
+
+func (s *FileStateMgrTestSuite) TestListLocalStates(c *C) {
+	f := filepath.Join(os.TempDir(), "test_list_local_states")
+	defer func() {
+		err := os.RemoveAll(f)
+		c.Assert(err, IsNil)
+	}()
+	fsm, err := NewFileStateMgr(f)
+	c.Assert(err, IsNil)
+	states, err := fsm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 0)
+
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var ecdsaState KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &ecdsaState), IsNil)
+	c.Assert(fsm.SaveLocalState(ecdsaState), IsNil)
+	eddsaLocalData := eddsakeygen.NewLocalPartySaveData(2)
+	eddsaLocalData.ShareID = big.NewInt(7)
+	eddsaLocalData.Xi = big.NewInt(12345)
+	eddsaLocalData.BigXj[0] = crypto.ScalarBaseMult(edwards.Edwards(), big.NewInt(1))
+	eddsaLocalData.BigXj[1] = crypto.ScalarBaseMult(edwards.Edwards(), big.NewInt(2))
+	eddsaLocalData.EDDSAPub = crypto.ScalarBaseMult(edwards.Edwards(), big.NewInt(3))
+	eddsaPubKey, _, err := conversion.GetEdDSATssPubKey(eddsaLocalData.EDDSAPub, conversion.DefaultBech32Prefix)
+	c.Assert(err, IsNil)
+	eddsaState := KeygenLocalState{
+		PubKey:          eddsaPubKey,
+		ParticipantKeys: []string{"A", "B"},
+		LocalPartyKey:   "A",
+		Threshold:       1,
+		Algo:            common.EdDSA,
+		EdDSALocalData:  &eddsaLocalData,
+	}
+	c.Assert(fsm.SaveLocalState(eddsaState), IsNil)
+	corrupted := "thorpub1addwnpepqf90u7n3nr2jwsw4t2gzhzqfdlply8dlzv3mdj4dr22uvhe04azq5gac3gq"
+	c.Assert(ioutil.WriteFile(filepath.Join(f, "localstate-"+corrupted+".json"), []byte("whatever"), 0600), IsNil)
+	// the other files in the folder are not local states
+	c.Assert(fsm.SavePreParamPool([]byte("whatever")), IsNil)
+
+	states, err = fsm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 3)
+	c.Assert(states[0].PubKey, Equals, corrupted)
+	c.Assert(states[0].Error, Not(Equals), "")
+
+	c.Assert(states[1].PubKey, Equals, ecdsaState.PubKey)
+	c.Assert(states[1].Error, Equals, "")
+	c.Assert(states[1].Algo, Equals, common.ECDSA)
+	c.Assert(states[1].ParticipantKeys, DeepEquals, ecdsaState.ParticipantKeys)
+	c.Assert(states[1].LocalPartyKey, Equals, ecdsaState.LocalPartyKey)
+	c.Assert(states[1].Threshold, Equals, 2)
+	c.Assert(states[1].ShareID, Equals, ecdsaState.LocalData.ShareID.String())
+	c.Assert(states[1].PublicShares, HasLen, 4)
+	for _, el := range states[1].PublicShares {
+		c.Assert(el, HasLen, 66)
+	}
+	c.Assert(states[1].ModifiedAt.IsZero(), Equals, false)
+
+	c.Assert(states[2].PubKey, Equals, eddsaState.PubKey)
+	c.Assert(states[2].Algo, Equals, common.EdDSA)
+	c.Assert(states[2].Threshold, Equals, 1)
+	c.Assert(states[2].ShareID, Equals, "7")
+	c.Assert(states[2].PublicShares, HasLen, 2)
+	c.Assert(states[2].PublicShares[0], HasLen, 64)
+
+	// the secret shares never leave the node
+	buf, err = json.Marshal(states)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(buf), "Xi"), Equals, false)
+	c.Assert(strings.Contains(string(buf), ecdsaState.LocalData.Xi.String()), Equals, false)
+	c.Assert(strings.Contains(string(buf), "12345"), Equals, false)
+}
+
+func (s *FileStateMgrTestSuite) TestGetLocalStateInfo(c *C) {
+	f := filepath.Join(os.TempDir(), "test_get_local_state_info")
+	defer func() {
+		err := os.RemoveAll(f)
+		c.Assert(err, IsNil)
+	}()
+	fsm, err := NewFileStateMgr(f)
+	c.Assert(err, IsNil)
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var state KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &state), IsNil)
+
+	_, err = fsm.GetLocalStateInfo("")
+	c.Assert(err, NotNil)
+	_, err = fsm.GetLocalStateInfo(state.PubKey)
+	c.Assert(os.IsNotExist(err), Equals, true)
+
+	c.Assert(fsm.SaveLocalState(state), IsNil)
+	// the other local states are never read
+	corrupted := "thorpub1addwnpepqf90u7n3nr2jwsw4t2gzhzqfdlply8dlzv3mdj4dr22uvhe04azq5gac3gq"
+	c.Assert(ioutil.WriteFile(filepath.Join(f, "localstate-"+corrupted+".json"), []byte("whatever"), 0600), IsNil)
+	info, err := fsm.GetLocalStateInfo(state.PubKey)
+	c.Assert(err, IsNil)
+	c.Assert(info.PubKey, Equals, state.PubKey)
+	c.Assert(info.Error, Equals, "")
+	c.Assert(info.Threshold, Equals, 2)
+	c.Assert(info.PublicShares, HasLen, 4)
+	c.Assert(info.ModifiedAt.IsZero(), Equals, false)
+
+	info, err = fsm.GetLocalStateInfo(corrupted)
+	c.Assert(err, IsNil)
+	c.Assert(info.PubKey, Equals, corrupted)
+	c.Assert(info.Error, Not(Equals), "")
+}
+
+func (s *FileStateMgrTestSuite) TestKeyState(c *C) {
+	state, err := GetKeyState("")
+	c.Assert(err, IsNil)
+	c.Assert(state, Equals, KeyActive)
+	state, err = GetKeyState("retiring")
+	c.Assert(err, IsNil)
+	c.Assert(state, Equals, KeyRetiring)
+	_, err = GetKeyState("whatever")
+	c.Assert(err, NotNil)
+	c.Assert(KeygenLocalState{}.GetState(), Equals, KeyActive)
+
+	c.Assert(KeyActive.CanTransitionTo(KeyRetiring), Equals, true)
+	c.Assert(KeyActive.CanTransitionTo(KeyRetired), Equals, true)
+	c.Assert(KeyActive.CanTransitionTo(KeyArchived), Equals, false)
+	c.Assert(KeyRetiring.CanTransitionTo(KeyActive), Equals, true)
+	c.Assert(KeyRetiring.CanTransitionTo(KeyRetired), Equals, true)
+	c.Assert(KeyRetired.CanTransitionTo(KeyActive), Equals, false)
+	c.Assert(KeyRetired.CanTransitionTo(KeyArchived), Equals, true)
+	c.Assert(KeyArchived.CanTransitionTo(KeyActive), Equals, false)
+	c.Assert(KeyArchived.CanTransitionTo(KeyArchived), Equals, false)
+}
+
+func (s *FileStateMgrTestSuite) TestArchiveLocalState(c *C) {
+	f := filepath.Join(os.TempDir(), "test_archive_local_state")
+	defer func() {
+		err := os.RemoveAll(f)
+		c.Assert(err, IsNil)
+	}()
+	fsm, err := NewFileStateMgr(f)
+	c.Assert(err, IsNil)
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var state KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &state), IsNil)
+	c.Assert(fsm.ArchiveLocalState(state.PubKey, []byte("encrypted")), NotNil)
+	state.State = KeyRetired
+	c.Assert(fsm.SaveLocalState(state), IsNil)
+	item, err := fsm.GetLocalState(state.PubKey)
+	c.Assert(err, IsNil)
+	c.Assert(item.GetState(), Equals, KeyRetired)
+
+	c.Assert(fsm.ArchiveLocalState(state.PubKey, []byte("encrypted")), IsNil)
+	_, err = fsm.GetLocalState(state.PubKey)
+	c.Assert(errors.Is(err, os.ErrNotExist), Equals, true)
+	archived, err := ioutil.ReadFile(filepath.Join(f, "archive", "localstate-"+state.PubKey+".dat"))
+	c.Assert(err, IsNil)
+	c.Assert(string(archived), Equals, "encrypted")
+	states, err := fsm.ListLocalStates()
+	c.Assert(err, IsNil)
+	c.Assert(states, HasLen, 0)
+}
@@ -1,6 +1,8 @@
 package storage
 
 import (
+	"os"
+
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 )
@@ -17,6 +19,18 @@ func (s *MockLocalStateManager) GetLocalState(pubKey string) (KeygenLocalState,
 	return KeygenLocalState{}, nil
 }
 
+func (s *MockLocalStateManager) ListLocalStates() ([]LocalStateInfo, error) {
+	return nil, nil
+}
+
+func (s *MockLocalStateManager) GetLocalStateInfo(pubKey string) (LocalStateInfo, error) {
+	return LocalStateInfo{}, os.ErrNotExist
+}
+
+func (s *MockLocalStateManager) ArchiveLocalState(pubKey string, buf []byte) error {
+	return nil
+}
+
 func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrList) error {
 	return nil
 }
@@ -24,3 +38,11 @@ func (s *MockLocalStateManager) SaveAddressBook(address map[peer.ID]addr.AddrLis
 func (s *MockLocalStateManager) RetrieveP2PAddresses() (addr.AddrList, error) {
 	return nil, nil
 }
+
+func (s *MockLocalStateManager) SavePreParamPool(buf []byte) error {
+	return nil
+}
+
+func (s *MockLocalStateManager) RetrievePreParamPool() ([]byte, error) {
+	return nil, os.ErrNotExist
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
+)
+
+func main() {
+	fileName := flag.String("file", audit.FileName, "path to the audit log")
+	pubKey := flag.String("pubkey", "", "pub key of the node that writes the log, in bech32 or hex, required")
+	flag.Parse()
+	if len(*pubKey) == 0 {
+		fmt.Println("-pubkey is required, the log can only be trusted against the key of the node known beforehand")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	f, err := os.Open(*fileName)
+	if err != nil {
+		fmt.Printf("fail to open the audit log: %v\n", err)
+		os.Exit(1)
+	}
+	defer f.Close()
+	verified, err := audit.Verify(f, *pubKey)
+	if err != nil {
+		fmt.Printf("%d entries verified, then: %v\n", verified, err)
+		os.Exit(1)
+	}
+	fmt.Printf("all %d entries verified\n", verified)
+}
@@ -1,9 +1,6 @@
 package main
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -13,8 +10,8 @@ import (
 
 	"github.com/binance-chain/tss-lib/ecdsa/keygen"
 	"github.com/btcsuite/btcd/btcec"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
+	"github.com/tendermint/tendermint/libs/bech32"
 )
 
 type (
@@ -42,17 +39,11 @@ func getTssSecretFile(file string) (KeygenLocalState, error) {
 	return localState, nil
 }
 
-func setupBech32Prefix() {
-	config := sdk.GetConfig()
-	// thorchain will import go-tss as a library , thus this is not needed, we copy the prefix here to avoid go-tss to import thorchain
-	config.SetBech32PrefixForAccount("thor", "thorpub")
-	config.SetBech32PrefixForValidator("thorv", "thorvpub")
-	config.SetBech32PrefixForConsensusNode("thorc", "thorcpub")
-}
-
-func getTssPubKey(x, y *big.Int) (string, sdk.AccAddress, error) {
+// getTssPubKey return the bech32 pub key and address of the given point, the pub key prefix is the given account
+// prefix plus pub
+func getTssPubKey(x, y *big.Int, prefix string) (string, string, error) {
 	if x == nil || y == nil {
-		return "", sdk.AccAddress{}, errors.New("invalid points")
+		return "", "", errors.New("invalid points")
 	}
 	tssPubKey := btcec.PublicKey{
 		Curve: btcec.S256(),
@@ -61,29 +52,10 @@ func getTssPubKey(x, y *big.Int) (string, sdk.AccAddress, error) {
 	}
 	var pubKeyCompressed secp256k1.PubKeySecp256k1
 	copy(pubKeyCompressed[:], tssPubKey.SerializeCompressed())
-	pubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, pubKeyCompressed)
-	addr := sdk.AccAddress(pubKeyCompressed.Address().Bytes())
-	return pubKey, addr, err
-}
-
-func aesCTRXOR(key, inText, iv []byte) ([]byte, error) {
-	// AES-128 is selected due to size of encryptKey.
-	aesBlock, err := aes.NewCipher(key)
+	pubKey, err := bech32.ConvertAndEncode(prefix+"pub", pubKeyCompressed.Bytes())
 	if err != nil {
-		return nil, err
+		return "", "", err
 	}
-	stream := cipher.NewCTR(aesBlock, iv)
-	outText := make([]byte, len(inText))
-	stream.XORKeyStream(outText, inText)
-	return outText, err
-}
-
-func generateRandomBytes(n int) ([]byte, error) {
-	b := make([]byte, n)
-	_, err := rand.Read(b)
-	// Note that err == nil only if we read len(b) bytes.
-	if err != nil {
-		return nil, err
-	}
-	return b, nil
+	addr, err := bech32.ConvertAndEncode(prefix, pubKeyCompressed.Address().Bytes())
+	return pubKey, addr, err
 }
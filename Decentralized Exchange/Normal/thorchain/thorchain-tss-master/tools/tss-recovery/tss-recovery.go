@@ -9,6 +9,8 @@ import (
 
 	"github.com/binance-chain/tss-lib/crypto/vss"
 	. "github.com/decred/dcrd/dcrec/secp256k1"
+
+	"gitlab.com/thorchain/tss/go-tss/keystore"
 )
 
 func main() {
@@ -17,10 +19,10 @@ func main() {
 	threshold := n - 1
 	export := flag.String("export", "", "path to export keyfile")
 	password := flag.String("password", "", "encryption password for keyfile")
+	prefix := flag.String("prefix", "thor", "account prefix of the bech32 pub key and address")
 	flag.Parse()
 	files := flag.Args()
 
-	setupBech32Prefix()
 	allSecret := make([]KeygenLocalState, len(files))
 	for i, f := range files {
 		tssSecret, err := getTssSecretFile(f)
@@ -49,7 +51,7 @@ func main() {
 	privKey := NewPrivateKey(tssPrivateKey)
 
 	pk := privKey.PubKey()
-	thorchainpk, address, err := getTssPubKey(pk.X, pk.Y)
+	thorchainpk, address, err := getTssPubKey(pk.X, pk.Y, *prefix)
 	if err != nil {
 		fmt.Printf("--->%v", err)
 	}
@@ -58,7 +60,7 @@ func main() {
 	fmt.Printf("-------%v\n", address)
 
 	if len(*export) > 0 && len(*password) >= 8 {
-		keyfile, err := exportKeyStore(privKey.Serialize(), *password)
+		keyfile, err := keystore.Encrypt(privKey.Serialize(), *password)
 		if err != nil {
 			fmt.Printf("--->%v", err)
 		}
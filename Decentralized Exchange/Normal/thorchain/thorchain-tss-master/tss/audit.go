@@ -0,0 +1,50 @@
+package tss
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
+)
+
+// GetAuditEntries return the latest entries of the audit log, the newest first
+func (t *TssServer) GetAuditEntries(limit int) ([]audit.Entry, error) {
+	if t.auditLog == nil {
+		return nil, errors.New("audit log is not enabled")
+	}
+	return t.auditLog.Recent(limit)
+}
+
+// auditSession append the record of the finished session to the audit log, the session never fails because of the
+// audit log, the failure is only logged
+func (t *TssServer) auditSession(entry audit.Entry, err error) {
+	if t.auditLog == nil {
+		return
+	}
+	entry.EndTime = time.Now()
+	if err != nil {
+		entry.Outcome = "error"
+		entry.Error = err.Error()
+	}
+	if err := t.auditLog.Append(entry); err != nil {
+		t.logger.Error().Err(err).Str("session", entry.SessionID).Msg("fail to write the audit log")
+	}
+}
+
+// hashMessage return the hex of the sha256 of the given base64 message, the hash of the raw message if it is not
+// valid base64
+func hashMessage(msg string) string {
+	buf, err := base64.StdEncoding.DecodeString(msg)
+	if err != nil {
+		buf = []byte(msg)
+	}
+	return hashBytes(buf)
+}
+
+func hashBytes(buf []byte) string {
+	h := sha256.Sum256(buf)
+	return hex.EncodeToString(h[:])
+}
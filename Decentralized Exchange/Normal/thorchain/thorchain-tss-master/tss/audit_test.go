@@ -0,0 +1,74 @@
+package tss
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type AuditTestSuite struct{}
+
+var _ = Suite(&AuditTestSuite{})
+
+func (s *AuditTestSuite) TestAuditKeySign(c *C) {
+	folder := filepath.Join(os.TempDir(), "test_audit")
+	defer func() {
+		c.Assert(os.RemoveAll(folder), IsNil)
+	}()
+	stateManager, err := storage.NewFileStateMgr(folder)
+	c.Assert(err, IsNil)
+	t := &TssServer{
+		logger:       log.Logger,
+		stateManager: stateManager,
+		keyStateLock: &sync.RWMutex{},
+	}
+	_, err = t.GetAuditEntries(0)
+	c.Assert(err, NotNil)
+
+	priKey := secp256k1.GenPrivKey()
+	t.auditLog, err = audit.NewLog(filepath.Join(folder, audit.FileName), priKey, "thor")
+	c.Assert(err, IsNil)
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var localState storage.KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &localState), IsNil)
+	localState.State = storage.KeyRetired
+	c.Assert(stateManager.SaveLocalState(localState), IsNil)
+
+	msg := base64.StdEncoding.EncodeToString([]byte("helloworld"))
+	_, err = t.KeySign(keysign.NewRequest(localState.PubKey, msg, localState.ParticipantKeys))
+	c.Assert(errors.Is(err, ErrKeyRetired), Equals, true)
+	_, err = t.KeySignBatch(keysign.BatchRequest{
+		PoolPubKey:    localState.PubKey,
+		Messages:      []string{msg, msg},
+		SignerPubKeys: localState.ParticipantKeys,
+	})
+	c.Assert(errors.Is(err, ErrKeyRetired), Equals, true)
+
+	entries, err := t.GetAuditEntries(0)
+	c.Assert(err, IsNil)
+	c.Assert(entries, HasLen, 3)
+	for _, el := range entries {
+		c.Assert(el.Operation, Equals, audit.OpKeySign)
+		c.Assert(el.PoolPubKey, Equals, localState.PubKey)
+		c.Assert(el.MessageHash, Equals, hashBytes([]byte("helloworld")))
+		c.Assert(el.Outcome, Equals, "error")
+		c.Assert(el.Error, Matches, ErrKeyRetired.Error()+".*")
+	}
+	// every message of the batch is recorded on its own, the newest first
+	c.Assert(entries[0].SessionID, Matches, ".*-1")
+	c.Assert(entries[1].SessionID, Matches, ".*-0")
+	c.Assert(entries[2].Seq, Equals, uint64(1))
+}
@@ -0,0 +1,125 @@
+package tss
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrDraining is returned when a new session is requested while the server is draining before it stops
+var ErrDraining = errors.New("tss server is draining")
+
+// drainLogInterval is how often the progress of the drain is logged
+const drainLogInterval = 5 * time.Second
+
+// sessionTracker counts the sessions in flight, so the server can wait for them before it stops. All the methods are
+// safe to be called on a nil sessionTracker, which never drains
+type sessionTracker struct {
+	lock     *sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{} // closed once the server is draining and no session is in flight
+}
+
+func newSessionTracker() *sessionTracker {
+	return &sessionTracker{
+		lock: &sync.Mutex{},
+		idle: make(chan struct{}),
+	}
+}
+
+// begin count a new session in flight, the returned function has to be called once the session is finished
+func (s *sessionTracker) begin() (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.draining {
+		return nil, ErrDraining
+	}
+	s.inFlight++
+	once := &sync.Once{}
+	return func() {
+		once.Do(s.end)
+	}, nil
+}
+
+func (s *sessionTracker) end() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.inFlight--
+	if s.draining && s.inFlight == 0 {
+		close(s.idle)
+	}
+}
+
+// drain refuse the new sessions, the returned channel is closed once no session is in flight
+func (s *sessionTracker) drain() <-chan struct{} {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.draining {
+		s.draining = true
+		if s.inFlight == 0 {
+			close(s.idle)
+		}
+	}
+	return s.idle
+}
+
+func (s *sessionTracker) isDraining() bool {
+	if s == nil {
+		return false
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.draining
+}
+
+func (s *sessionTracker) count() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.inFlight
+}
+
+// Drain get the server ready to stop without leaving the peers behind. The new sessions are refused with ErrDraining,
+// the sessions in flight are given till the drain timeout to finish, and then the signatures still queued are sent to
+// the peers and the address book is saved. The sessions not finished in time are aborted once the server is stopped,
+// Stop should be called afterwards
+func (t *TssServer) Drain() {
+	ctx, cancel := context.WithTimeout(context.Background(), t.conf.DrainTimeout)
+	defer cancel()
+	idle := t.sessions.drain()
+	t.logger.Info().Int("sessions", t.sessions.count()).Str("timeout", t.conf.DrainTimeout.String()).Msg("start draining")
+	t.waitForSessions(ctx, idle)
+	if err := t.signatureNotifier.Flush(ctx); err != nil {
+		t.logger.Error().Err(err).Msg("fail to send the queued signatures to the peers")
+	}
+	if err := t.stateManager.SaveAddressBook(t.p2pCommunication.ExportPeerAddress()); err != nil {
+		t.logger.Error().Err(err).Msg("fail to save the peer addresses")
+	}
+	t.logger.Info().Msg("drain finished")
+}
+
+// waitForSessions wait till the given channel is closed or the context is done, how many sessions are still in
+// flight is logged along the way
+func (t *TssServer) waitForSessions(ctx context.Context, idle <-chan struct{}) {
+	deadline, _ := ctx.Deadline()
+	ticker := time.NewTicker(drainLogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-idle:
+			t.logger.Info().Msg("all the sessions in flight are finished")
+			return
+		case <-ticker.C:
+			t.logger.Info().Int("sessions", t.sessions.count()).
+				Str("left", time.Until(deadline).Round(time.Second).String()).
+				Msg("waiting for the sessions in flight")
+		case <-ctx.Done():
+			t.logger.Warn().Int("sessions", t.sessions.count()).Msg("drain timeout, the sessions in flight will be aborted")
+			return
+		}
+	}
+}
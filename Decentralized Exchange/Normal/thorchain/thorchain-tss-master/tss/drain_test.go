@@ -0,0 +1,62 @@
+package tss
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+)
+
+type DrainTestSuite struct{}
+
+var _ = Suite(&DrainTestSuite{})
+
+func (s *DrainTestSuite) TestSessionTracker(c *C) {
+	tracker := newSessionTracker()
+	done1, err := tracker.begin()
+	c.Assert(err, IsNil)
+	done2, err := tracker.begin()
+	c.Assert(err, IsNil)
+	c.Assert(tracker.count(), Equals, 2)
+
+	idle := tracker.drain()
+	c.Assert(tracker.isDraining(), Equals, true)
+	_, err = tracker.begin()
+	c.Assert(errors.Is(err, ErrDraining), Equals, true)
+
+	done1()
+	// the same session is only counted once
+	done1()
+	select {
+	case <-idle:
+		c.Fatal("a session is still in flight")
+	default:
+	}
+	done2()
+	<-idle
+	c.Assert(tracker.count(), Equals, 0)
+	// draining again is fine
+	<-tracker.drain()
+
+	var nilTracker *sessionTracker
+	done, err := nilTracker.begin()
+	c.Assert(err, IsNil)
+	done()
+	c.Assert(nilTracker.isDraining(), Equals, false)
+}
+
+func (s *DrainTestSuite) TestRefuseSessions(c *C) {
+	t := &TssServer{
+		logger:   log.Logger,
+		sessions: newSessionTracker(),
+	}
+	<-t.sessions.drain()
+	_, err := t.KeySign(keysign.Request{})
+	c.Assert(errors.Is(err, ErrDraining), Equals, true)
+	_, err = t.KeySignBatch(keysign.BatchRequest{})
+	c.Assert(errors.Is(err, ErrDraining), Equals, true)
+	_, err = t.KeySignAsync(keysign.Request{})
+	c.Assert(errors.Is(err, ErrDraining), Equals, true)
+}
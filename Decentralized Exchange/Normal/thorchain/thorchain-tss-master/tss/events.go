@@ -0,0 +1,39 @@
+package tss
+
+import (
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/events"
+)
+
+// SubscribeEvents return a new subscription of the events of this server that pass the given filter
+func (t *TssServer) SubscribeEvents(filter events.Filter) *events.Subscription {
+	return t.eventBus.Subscribe(filter)
+}
+
+// publishSessionCreated publish the session of the given operation is created
+func (t *TssServer) publishSessionCreated(sessionID, operation string) {
+	t.eventBus.Publish(events.Event{
+		SessionID: sessionID,
+		Type:      events.SessionCreated,
+		Operation: operation,
+	})
+}
+
+// publishSessionFinished publish the session of the given operation is finished, with the blame if there is any
+func (t *TssServer) publishSessionFinished(sessionID, operation string, status common.Status, b blame.Blame, err error) {
+	e := events.Event{
+		SessionID: sessionID,
+		Type:      events.SessionFinished,
+		Operation: operation,
+		Status:    status.String(),
+	}
+	if len(b.FailReason) != 0 {
+		e.Blame = &b
+	}
+	if err != nil {
+		e.Status = "error"
+		e.Error = err.Error()
+	}
+	t.eventBus.Publish(e)
+}
@@ -0,0 +1,318 @@
+package tss
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+)
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+	ErrJobFinished = errors.New("job already finished")
+)
+
+// JobType indicates what kind of ceremony the job runs
+type JobType string
+
+const (
+	JobKeygen  JobType = "keygen"
+	JobKeySign JobType = "keysign"
+)
+
+// JobState represent the state of an asynchronous job
+type JobState string
+
+const (
+	JobQueued       JobState = "queued"
+	JobJoiningParty JobState = "joining_party"
+	JobRunning      JobState = "running"
+	JobDone         JobState = "done"
+	JobFailed       JobState = "failed"
+	JobCancelled    JobState = "cancelled"
+)
+
+// IsFinished return true when the job will not make any progress anymore
+func (s JobState) IsFinished() bool {
+	return s == JobDone || s == JobFailed || s == JobCancelled
+}
+
+// Job is a snapshot of an asynchronous keygen/keysign job
+type Job struct {
+	ID         string      `json:"id"`
+	Type       JobType     `json:"type"`
+	State      JobState    `json:"state"`
+	Round      string      `json:"round,omitempty"` // the latest round the local party produced a message for
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedAt  time.Time   `json:"created_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// ProgressEventType is what happens in the keygen/keysign session
+type ProgressEventType string
+
+const (
+	ProgressPartyJoined    ProgressEventType = "party_joined"
+	ProgressRoundStarted   ProgressEventType = "round_started"
+	ProgressShareRequested ProgressEventType = "share_requested"
+)
+
+// ProgressEvent tell how far the keygen/keysign session gets
+type ProgressEvent struct {
+	Type      ProgressEventType `json:"type"`
+	Round     int               `json:"round,omitempty"`      // the number of the round the local party starts, from 1
+	RoundName string            `json:"round_name,omitempty"` // the type of the messages of the round
+	Peer      string            `json:"peer,omitempty"`       // the peer whose share is requested
+}
+
+// ProgressListener is told about the progress of the keygen/keysign session, it should not block as it is called by
+// the goroutines processing the messages
+type ProgressListener func(ProgressEvent)
+
+// JobProgress is used by a running keygen/keysign to report how far it gets, all the methods are safe to be
+// called on a nil JobProgress, which is the case when keygen/keysign is called synchronously
+type JobProgress struct {
+	lock      *sync.Mutex
+	state     JobState
+	tssCommon *common.TssCommon
+	listener  ProgressListener
+	rounds    []string
+}
+
+func newJobProgress() *JobProgress {
+	return &JobProgress{
+		lock:  &sync.Mutex{},
+		state: JobQueued,
+	}
+}
+
+// newListenedProgress create a JobProgress that tells the given listener about the progress of the session
+func newListenedProgress(listener ProgressListener) *JobProgress {
+	p := newJobProgress()
+	p.listener = listener
+	return p
+}
+
+func (p *JobProgress) joiningParty() {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.state = JobJoiningParty
+}
+
+// running indicates the party has been formed, the current round is read from the given TssCommon
+func (p *JobProgress) running(tssCommon *common.TssCommon) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	p.state = JobRunning
+	p.tssCommon = tssCommon
+	p.lock.Unlock()
+	if p.listener == nil || tssCommon == nil {
+		return
+	}
+	p.listener(ProgressEvent{Type: ProgressPartyJoined})
+	tssCommon.GetBlameMgr().GetTimeline().SetListener(p.onTimelineEvent)
+}
+
+// onTimelineEvent tell the listener the local party starts a new round, or requests the share of a peer
+func (p *JobProgress) onTimelineEvent(el blame.TimelineEvent) {
+	switch el.Event {
+	case blame.EventLocalMessage:
+		p.lock.Lock()
+		for _, round := range p.rounds {
+			if round == el.Round {
+				p.lock.Unlock()
+				return
+			}
+		}
+		p.rounds = append(p.rounds, el.Round)
+		round := len(p.rounds)
+		p.lock.Unlock()
+		p.listener(ProgressEvent{Type: ProgressRoundStarted, Round: round, RoundName: el.Round})
+	case blame.EventShareRequested:
+		p.listener(ProgressEvent{Type: ProgressShareRequested, RoundName: el.Round, Peer: el.Peer})
+	}
+}
+
+func (p *JobProgress) getState() (JobState, string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.tssCommon == nil {
+		return p.state, ""
+	}
+	lastMsg := p.tssCommon.GetBlameMgr().GetLastMsg()
+	if lastMsg == nil {
+		return p.state, ""
+	}
+	return p.state, lastMsg.Type()
+}
+
+// JobRunner runs the job and return its result, the given context is cancelled when the job is cancelled
+type JobRunner func(ctx context.Context, progress *JobProgress) (interface{}, error)
+
+type jobItem struct {
+	job      Job
+	progress *JobProgress
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+// JobManager keeps track of the asynchronous jobs, the finished jobs are kept for the retention period
+type JobManager struct {
+	logger    zerolog.Logger
+	lock      *sync.Mutex
+	jobs      map[string]*jobItem
+	retention time.Duration
+}
+
+// NewJobManager create a new instance of JobManager
+func NewJobManager(retention time.Duration) *JobManager {
+	// if no retention is given, default to one hour
+	if retention.Nanoseconds() == 0 {
+		retention = time.Hour
+	}
+	return &JobManager{
+		logger:    log.With().Str("module", "job_manager").Logger(),
+		lock:      &sync.Mutex{},
+		jobs:      make(map[string]*jobItem),
+		retention: retention,
+	}
+}
+
+// Submit start the given runner in the background and return the job id straight away
+func (jm *JobManager) Submit(jobType JobType, runner JobRunner) (string, error) {
+	id, err := newJobID()
+	if err != nil {
+		return "", fmt.Errorf("fail to generate job id: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	item := &jobItem{
+		job: Job{
+			ID:        id,
+			Type:      jobType,
+			State:     JobQueued,
+			CreatedAt: time.Now(),
+		},
+		progress: newJobProgress(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	jm.lock.Lock()
+	jm.purgeExpired()
+	jm.jobs[id] = item
+	jm.lock.Unlock()
+	jm.logger.Info().Str("job", id).Msgf("%s job submitted", jobType)
+
+	go func() {
+		defer item.cancel()
+		result, err := runner(item.ctx, item.progress)
+		jm.finish(item, result, err)
+	}()
+	return id, nil
+}
+
+func (jm *JobManager) finish(item *jobItem, result interface{}, err error) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	now := time.Now()
+	item.job.Result = result
+	if item.job.State == JobCancelled {
+		return
+	}
+	item.job.State, item.job.Round = item.progress.getState()
+	item.job.FinishedAt = &now
+	if err != nil {
+		jm.logger.Error().Err(err).Str("job", item.job.ID).Msg("job failed")
+		item.job.State = JobFailed
+		item.job.Error = err.Error()
+		return
+	}
+	jm.logger.Info().Str("job", item.job.ID).Msg("job finished")
+	item.job.State = JobDone
+}
+
+// Get return the snapshot of the given job
+func (jm *JobManager) Get(id string) (Job, error) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	jm.purgeExpired()
+	item, ok := jm.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return jm.snapshot(item), nil
+}
+
+// Cancel abort the given job, the context of the keygen/keysign session the job runs is cancelled
+func (jm *JobManager) Cancel(id string) (Job, error) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	jm.purgeExpired()
+	item, ok := jm.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	if item.job.State.IsFinished() {
+		return jm.snapshot(item), ErrJobFinished
+	}
+	jm.cancel(item)
+	jm.logger.Info().Str("job", id).Msg("job cancelled")
+	return jm.snapshot(item), nil
+}
+
+// Stop cancel all the jobs that are not finished yet
+func (jm *JobManager) Stop() {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	for _, item := range jm.jobs {
+		if !item.job.State.IsFinished() {
+			jm.cancel(item)
+		}
+	}
+}
+
+func (jm *JobManager) cancel(item *jobItem) {
+	now := time.Now()
+	item.job.State = JobCancelled
+	item.job.FinishedAt = &now
+	item.cancel()
+}
+
+func (jm *JobManager) snapshot(item *jobItem) Job {
+	job := item.job
+	if !job.State.IsFinished() {
+		job.State, job.Round = item.progress.getState()
+	}
+	return job
+}
+
+// purgeExpired remove the finished jobs that are older than the retention period, the caller should hold the lock
+func (jm *JobManager) purgeExpired() {
+	for id, item := range jm.jobs {
+		if item.job.FinishedAt != nil && time.Since(*item.job.FinishedAt) > jm.retention {
+			delete(jm.jobs, id)
+		}
+	}
+}
+
+func newJobID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
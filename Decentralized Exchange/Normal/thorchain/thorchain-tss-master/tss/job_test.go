@@ -0,0 +1,121 @@
+package tss
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+type JobManagerTestSuite struct{}
+
+var _ = Suite(&JobManagerTestSuite{})
+
+func waitForJob(c *C, jm *JobManager, id string) Job {
+	for i := 0; i < 100; i++ {
+		job, err := jm.Get(id)
+		c.Assert(err, IsNil)
+		if job.State.IsFinished() {
+			return job
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Fatal("job did not finish in time")
+	return Job{}
+}
+
+func (s *JobManagerTestSuite) TestSubmit(c *C) {
+	jm := NewJobManager(time.Minute)
+	id, err := jm.Submit(JobKeygen, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		progress.joiningParty()
+		progress.running(nil)
+		return "hello", nil
+	})
+	c.Assert(err, IsNil)
+	job := waitForJob(c, jm, id)
+	c.Assert(job.ID, Equals, id)
+	c.Assert(job.Type, Equals, JobKeygen)
+	c.Assert(job.State, Equals, JobDone)
+	c.Assert(job.Result, Equals, "hello")
+	c.Assert(job.FinishedAt, NotNil)
+
+	id, err = jm.Submit(JobKeySign, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		return nil, errors.New("you ask for it")
+	})
+	c.Assert(err, IsNil)
+	job = waitForJob(c, jm, id)
+	c.Assert(job.State, Equals, JobFailed)
+	c.Assert(job.Error, Equals, "you ask for it")
+
+	_, err = jm.Get("whatever")
+	c.Assert(errors.Is(err, ErrJobNotFound), Equals, true)
+}
+
+func (s *JobManagerTestSuite) TestCancel(c *C) {
+	jm := NewJobManager(time.Minute)
+	joined := make(chan struct{})
+	stopped := make(chan struct{})
+	id, err := jm.Submit(JobKeygen, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		progress.joiningParty()
+		close(joined)
+		<-ctx.Done()
+		close(stopped)
+		return nil, ctx.Err()
+	})
+	c.Assert(err, IsNil)
+	<-joined
+	job, err := jm.Get(id)
+	c.Assert(err, IsNil)
+	c.Assert(job.State, Equals, JobJoiningParty)
+
+	job, err = jm.Cancel(id)
+	c.Assert(err, IsNil)
+	c.Assert(job.State, Equals, JobCancelled)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		c.Fatal("the job is not stopped")
+	}
+	// the job stays cancelled even the runner return an error afterwards
+	job = waitForJob(c, jm, id)
+	c.Assert(job.State, Equals, JobCancelled)
+	_, err = jm.Cancel(id)
+	c.Assert(errors.Is(err, ErrJobFinished), Equals, true)
+	_, err = jm.Cancel("whatever")
+	c.Assert(errors.Is(err, ErrJobNotFound), Equals, true)
+}
+
+func (s *JobManagerTestSuite) TestRetention(c *C) {
+	jm := NewJobManager(100 * time.Millisecond)
+	id, err := jm.Submit(JobKeygen, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		return nil, nil
+	})
+	c.Assert(err, IsNil)
+	waitForJob(c, jm, id)
+	time.Sleep(200 * time.Millisecond)
+	_, err = jm.Get(id)
+	c.Assert(errors.Is(err, ErrJobNotFound), Equals, true)
+}
+
+func (s *JobManagerTestSuite) TestProgressListener(c *C) {
+	var events []ProgressEvent
+	progress := newListenedProgress(func(event ProgressEvent) {
+		events = append(events, event)
+	})
+	timeline := blame.NewTimeline()
+	timeline.SetListener(progress.onTimelineEvent)
+	timeline.Add(blame.EventPartyFormed, "", "")
+	timeline.Add(blame.EventLocalMessage, "round1", "")
+	timeline.Add(blame.EventPeerMessage, "round1", "peer1")
+	timeline.Add(blame.EventLocalMessage, "round1", "")
+	timeline.Add(blame.EventShareRequested, "round1", "peer1")
+	timeline.Add(blame.EventLocalMessage, "round2", "")
+	c.Assert(events, DeepEquals, []ProgressEvent{
+		{Type: ProgressRoundStarted, Round: 1, RoundName: "round1"},
+		{Type: ProgressShareRequested, RoundName: "round1", Peer: "peer1"},
+		{Type: ProgressRoundStarted, Round: 2, RoundName: "round2"},
+	})
+}
@@ -0,0 +1,144 @@
+package tss
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	tcrypto "github.com/tendermint/tendermint/crypto"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+var (
+	// ErrInvalidKeyState is returned when the key can't move from its current state to the requested one
+	ErrInvalidKeyState = errors.New("invalid key state transition")
+	// ErrKeyRetired is returned when the key asked to sign is retired or archived
+	ErrKeyRetired = errors.New("the pool key is retired")
+	// ErrKeyRetiring is returned when the key asked to sign is retiring and the request is not a migration
+	ErrKeyRetiring = errors.New("the pool key is retiring, it only signs the migration")
+)
+
+// archiveCipherLabel is the label of the key the archived local states are encrypted with
+const archiveCipherLabel = "tss-key-archive"
+
+// KeyStateRequest is the request to move the pool key to another lifecycle state
+type KeyStateRequest struct {
+	State storage.KeyState `json:"state"`
+}
+
+// checkKeyState make sure the key in the given state can sign the request
+func checkKeyState(state storage.KeygenLocalState, migration bool) error {
+	switch state.GetState() {
+	case storage.KeyActive:
+		return nil
+	case storage.KeyRetiring:
+		if migration {
+			return nil
+		}
+		return ErrKeyRetiring
+	default:
+		return fmt.Errorf("%w, its state is %s", ErrKeyRetired, state.GetState())
+	}
+}
+
+// SetKeyState move the given pool key to the given lifecycle state, the key is in bech32 or hex. It waits for the
+// running keysign and reshare to finish, so the state never changes under them. The share of the archived key is
+// encrypted with the key derived from the node private key and moved out of the live storage, UnsealLocalState opens
+// it again
+func (t *TssServer) SetKeyState(pubKey string, state storage.KeyState) (storage.LocalStateInfo, error) {
+	normalized, err := t.normalizeKey(pubKey)
+	if err != nil {
+		return storage.LocalStateInfo{}, fmt.Errorf("%w: %s", ErrKeyNotFound, err)
+	}
+	to, err := storage.GetKeyState(string(state))
+	if err != nil {
+		return storage.LocalStateInfo{}, fmt.Errorf("%w: %s", ErrInvalidKeyState, err)
+	}
+	t.keyStateLock.Lock()
+	defer t.keyStateLock.Unlock()
+	localState, err := t.stateManager.GetLocalState(normalized)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return storage.LocalStateInfo{}, fmt.Errorf("%w: %s", ErrKeyNotFound, err)
+		}
+		return storage.LocalStateInfo{}, fmt.Errorf("fail to get local keygen state: %w", err)
+	}
+	from := localState.GetState()
+	if !from.CanTransitionTo(to) {
+		return storage.LocalStateInfo{}, fmt.Errorf("%w from %s to %s", ErrInvalidKeyState, from, to)
+	}
+	localState.State = to
+	if to == storage.KeyArchived {
+		buf, err := t.sealLocalState(localState)
+		if err != nil {
+			return storage.LocalStateInfo{}, err
+		}
+		if err := t.stateManager.ArchiveLocalState(normalized, buf); err != nil {
+			return storage.LocalStateInfo{}, fmt.Errorf("fail to archive the local state: %w", err)
+		}
+	} else if err := t.stateManager.SaveLocalState(localState); err != nil {
+		return storage.LocalStateInfo{}, fmt.Errorf("fail to save the local state: %w", err)
+	}
+	t.logger.Info().Str("pool pub key", normalized).
+		Str("from", string(from)).
+		Str("to", string(to)).
+		Msg("key state changed")
+	info, err := storage.NewLocalStateInfo(localState, time.Now())
+	if err != nil {
+		return storage.LocalStateInfo{}, fmt.Errorf("fail to get the local state info: %w", err)
+	}
+	return info, nil
+}
+
+// sealLocalState encrypt the given local state for the archive
+func (t *TssServer) sealLocalState(state storage.KeygenLocalState) ([]byte, error) {
+	priKeyRawBytes, err := conversion.GetPriKeyRawBytes(t.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get private key: %w", err)
+	}
+	aead, err := newNodeKeyCipher(archiveCipherLabel, priKeyRawBytes)
+	if err != nil {
+		return nil, err
+	}
+	plainText, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("fail to marshal the local state: %w", err)
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("fail to generate nonce: %w", err)
+	}
+	return aead.Seal(nonce, nonce, plainText, nil), nil
+}
+
+// UnsealLocalState decrypt the local state of the archived key with the private key of the node that archived it, the
+// sealed data is the content of the file in the archive folder of the node
+func UnsealLocalState(priKey tcrypto.PrivKey, sealed []byte) (storage.KeygenLocalState, error) {
+	priKeyRawBytes, err := conversion.GetPriKeyRawBytes(priKey)
+	if err != nil {
+		return storage.KeygenLocalState{}, fmt.Errorf("fail to get private key: %w", err)
+	}
+	aead, err := newNodeKeyCipher(archiveCipherLabel, priKeyRawBytes)
+	if err != nil {
+		return storage.KeygenLocalState{}, err
+	}
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return storage.KeygenLocalState{}, errors.New("the sealed local state is too short")
+	}
+	plainText, err := aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return storage.KeygenLocalState{}, fmt.Errorf("fail to decrypt the local state: %w", err)
+	}
+	var state storage.KeygenLocalState
+	if err := json.Unmarshal(plainText, &state); err != nil {
+		return storage.KeygenLocalState{}, fmt.Errorf("fail to unmarshal the local state: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,123 @@
+package tss
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type KeyLifecycleTestSuite struct{}
+
+var _ = Suite(&KeyLifecycleTestSuite{})
+
+func (s *KeyLifecycleTestSuite) TestSetKeyState(c *C) {
+	folder := filepath.Join(os.TempDir(), "test_key_lifecycle")
+	defer func() {
+		c.Assert(os.RemoveAll(folder), IsNil)
+	}()
+	stateManager, err := storage.NewFileStateMgr(folder)
+	c.Assert(err, IsNil)
+	priKey := secp256k1.GenPrivKey()
+	t := &TssServer{
+		logger:          log.Logger,
+		stateManager:    stateManager,
+		privateKey:      priKey,
+		keyStateLock:    &sync.RWMutex{},
+		tssKeyGenLocker: &sync.Mutex{},
+	}
+	buf, err := ioutil.ReadFile("../test_data/keysign_data/0.json")
+	c.Assert(err, IsNil)
+	var localState storage.KeygenLocalState
+	c.Assert(json.Unmarshal(buf, &localState), IsNil)
+	c.Assert(stateManager.SaveLocalState(localState), IsNil)
+	t.localNodePubKey = localState.LocalPartyKey
+	poolPubKey := localState.PubKey
+	msg := base64.StdEncoding.EncodeToString([]byte("helloworld"))
+	assertKeySignRefused := func(migration bool, expected error) {
+		req := keysign.NewRequest(poolPubKey, msg, localState.ParticipantKeys)
+		req.Migration = migration
+		_, err := t.KeySign(req)
+		c.Assert(errors.Is(err, expected), Equals, true)
+		_, err = t.KeySignBatch(keysign.BatchRequest{
+			PoolPubKey:    poolPubKey,
+			Messages:      []string{msg},
+			SignerPubKeys: localState.ParticipantKeys,
+			Migration:     migration,
+		})
+		c.Assert(errors.Is(err, expected), Equals, true)
+	}
+
+	_, err = t.SetKeyState("whatever", storage.KeyRetiring)
+	c.Assert(errors.Is(err, ErrKeyNotFound), Equals, true)
+	_, err = t.SetKeyState(poolPubKey, "whatever")
+	c.Assert(errors.Is(err, ErrInvalidKeyState), Equals, true)
+	_, err = t.SetKeyState(poolPubKey, storage.KeyArchived)
+	c.Assert(errors.Is(err, ErrInvalidKeyState), Equals, true)
+
+	// the state doesn't change while a keysign is running
+	t.keyStateLock.RLock()
+	changed := make(chan storage.LocalStateInfo)
+	go func() {
+		info, err := t.SetKeyState(poolPubKey, storage.KeyRetiring)
+		c.Check(err, IsNil)
+		changed <- info
+	}()
+	select {
+	case <-changed:
+		c.Fatal("the key state should not change till the keysign is done")
+	case <-time.After(time.Millisecond * 100):
+	}
+	t.keyStateLock.RUnlock()
+	info := <-changed
+	c.Assert(info.State, Equals, storage.KeyRetiring)
+	assertKeySignRefused(false, ErrKeyRetiring)
+	saved, err := stateManager.GetLocalState(poolPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(saved.GetState(), Equals, storage.KeyRetiring)
+	c.Assert(checkKeyState(saved, true), IsNil)
+
+	_, err = t.SetKeyState(poolPubKey, storage.KeyRetired)
+	c.Assert(err, IsNil)
+	assertKeySignRefused(false, ErrKeyRetired)
+	assertKeySignRefused(true, ErrKeyRetired)
+	// the retired key can't be reshared either
+	_, err = t.Reshare(reshare.NewRequest(poolPubKey, localState.ParticipantKeys, localState.ParticipantKeys, 0))
+	c.Assert(errors.Is(err, ErrKeyRetired), Equals, true)
+	_, err = t.SetKeyState(poolPubKey, storage.KeyActive)
+	c.Assert(errors.Is(err, ErrInvalidKeyState), Equals, true)
+
+	info, err = t.SetKeyState(poolPubKey, storage.KeyArchived)
+	c.Assert(err, IsNil)
+	c.Assert(info.State, Equals, storage.KeyArchived)
+	_, err = stateManager.GetLocalState(poolPubKey)
+	c.Assert(errors.Is(err, os.ErrNotExist), Equals, true)
+	_, err = t.SetKeyState(poolPubKey, storage.KeyActive)
+	c.Assert(errors.Is(err, ErrKeyNotFound), Equals, true)
+
+	// the archive can be opened with the node private key only
+	sealed, err := ioutil.ReadFile(filepath.Join(folder, "archive", "localstate-"+poolPubKey+".dat"))
+	c.Assert(err, IsNil)
+	archived, err := UnsealLocalState(priKey, sealed)
+	c.Assert(err, IsNil)
+	c.Assert(archived.PubKey, Equals, poolPubKey)
+	c.Assert(archived.State, Equals, storage.KeyArchived)
+	c.Assert(archived.LocalData.Xi.Cmp(localState.LocalData.Xi), Equals, 0)
+	c.Assert(archived.ParticipantKeys, DeepEquals, localState.ParticipantKeys)
+	_, err = UnsealLocalState(secp256k1.GenPrivKey(), sealed)
+	c.Assert(err, NotNil)
+	_, err = UnsealLocalState(priKey, sealed[:4])
+	c.Assert(err, NotNil)
+}
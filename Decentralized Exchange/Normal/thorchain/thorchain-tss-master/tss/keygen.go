@@ -1,23 +1,111 @@
 package tss
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"sync/atomic"
+	"time"
 
+	"github.com/binance-chain/go-sdk/common/types"
+	bcrypto "github.com/binance-chain/tss-lib/crypto"
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 )
 
 func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
+	return t.keygen(context.Background(), req, nil)
+}
+
+// KeygenWithContext run the keygen, once the given context is done the keygen is aborted and the response
+// status is set to cancelled
+func (t *TssServer) KeygenWithContext(ctx context.Context, req keygen.Request) (keygen.Response, error) {
+	return t.keygen(ctx, req, nil)
+}
+
+// KeygenWithProgress run the keygen like KeygenWithContext, and tell the given listener how far it gets
+func (t *TssServer) KeygenWithProgress(ctx context.Context, req keygen.Request, listener ProgressListener) (keygen.Response, error) {
+	return t.keygen(ctx, req, newListenedProgress(listener))
+}
+
+// KeygenAsync start the keygen in the background and return the id of the job straight away
+func (t *TssServer) KeygenAsync(req keygen.Request) (string, error) {
+	if t.sessions.isDraining() {
+		return "", ErrDraining
+	}
+	return t.jobManager.Submit(JobKeygen, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		resp, err := t.keygen(ctx, req, progress)
+		if err == nil && resp.Status != common.Success {
+			err = errors.New("keygen failed")
+		}
+		return resp, err
+	})
+}
+
+// keygen run the keygen, the session is aborted once the given context is done, and how far it gets is reported
+// to the given progress
+func (t *TssServer) keygen(ctx context.Context, req keygen.Request, progress *JobProgress) (resp keygen.Response, err error) {
+	done, err := t.sessions.begin()
+	if err != nil {
+		return keygen.Response{}, err
+	}
+	defer done()
 	t.tssKeyGenLocker.Lock()
 	defer t.tssKeyGenLocker.Unlock()
+	if ctx.Err() != nil {
+		return keygen.Response{Status: common.Cancelled}, nil
+	}
 	status := common.Success
+	algo, err := common.GetAlgo(string(req.Algo))
+	if err != nil {
+		return keygen.Response{}, err
+	}
+	req.Keys, err = t.normalizeKeys(req.Keys)
+	if err != nil {
+		return keygen.Response{}, err
+	}
+	if req.Threshold != 0 {
+		if err := common.ValidateThreshold(req.Threshold, len(req.Keys)); err != nil {
+			return keygen.Response{}, err
+		}
+	}
 	msgID, err := t.requestToMsgId(req)
 	if err != nil {
 		return keygen.Response{}, err
 	}
+	startTime := time.Now()
+	t.publishSessionCreated(msgID, monitor.OpKeygen)
+	defer func() {
+		monitor.ObserveBlame(monitor.OpKeygen, resp.Blame)
+		t.publishSessionFinished(msgID, monitor.OpKeygen, resp.Status, resp.Blame, err)
+		t.auditSession(audit.Entry{
+			SessionID:  msgID,
+			Operation:  audit.OpKeygen,
+			PoolPubKey: resp.PubKey,
+			Parties:    req.Keys,
+			StartTime:  startTime,
+			Outcome:    resp.Status.String(),
+			Blame:      audit.BlameSummary(resp.Blame),
+		}, err)
+	}()
+	// every ECDSA keygen uses its own pre-parameters, they are put back only if the keygen never starts
+	var preParams *bkeygen.LocalPreParams
+	if algo == common.ECDSA {
+		preParams, err = t.preParamPool.Take(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return keygen.Response{Status: common.Cancelled}, nil
+			}
+			return keygen.Response{}, fmt.Errorf("fail to get pre-parameters: %w", err)
+		}
+	}
 
 	keygenInstance := keygen.NewTssKeyGen(
 		t.p2pCommunication.GetLocalPeerID(),
@@ -25,24 +113,45 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 		t.localNodePubKey,
 		t.p2pCommunication.BroadcastMsgChan,
 		t.stopChan,
-		t.preParams,
+		preParams,
 		msgID,
 		t.stateManager,
 		t.privateKey,
 		t.p2pCommunication)
+	keygenInstance.GetTssCommonStruct().SetEventBus(t.eventBus)
+
+	timeline := keygenInstance.GetTssCommonStruct().GetBlameMgr().GetTimeline()
+	defer func() {
+		resp.Timeline = t.keepTimeline(msgID, timeline)
+	}()
 
 	keygenMsgChannel := keygenInstance.GetTssKeyGenChannels()
-	t.p2pCommunication.SetSubscribe(messages.TSSKeyGenMsg, msgID, keygenMsgChannel)
-	t.p2pCommunication.SetSubscribe(messages.TSSKeyGenVerMsg, msgID, keygenMsgChannel)
+	keygenMsgType, keygenVerMsgType := keygen.GetMsgTypes(algo)
+	t.p2pCommunication.SetSubscribe(keygenMsgType, msgID, keygenMsgChannel)
+	t.p2pCommunication.SetSubscribe(keygenVerMsgType, msgID, keygenMsgChannel)
 	t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, msgID, keygenMsgChannel)
 	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, keygenMsgChannel)
 
-	defer t.p2pCommunication.CancelSubscribe(messages.TSSKeyGenMsg, msgID)
-	defer t.p2pCommunication.CancelSubscribe(messages.TSSKeyGenVerMsg, msgID)
+	defer t.p2pCommunication.CancelSubscribe(keygenMsgType, msgID)
+	defer t.p2pCommunication.CancelSubscribe(keygenVerMsgType, msgID)
 	defer t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, msgID)
 	defer t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
-	onlinePeers, err := t.joinParty(msgID, req.Keys)
+	var releaseCurve func()
+	ctx, releaseCurve, err = t.acquireCurve(ctx, monitor.OpKeygen, algo, preParams)
+	if err != nil {
+		return keygen.Response{}, err
+	}
+	defer releaseCurve()
+	progress.joiningParty()
+	onlinePeers, err := t.joinParty(ctx, monitor.OpKeygen, msgID, req.Keys)
 	if err != nil {
+		if preParams != nil {
+			t.preParamPool.Return(preParams)
+		}
+		if ctx.Err() != nil {
+			t.logger.Info().Msg("keygen is cancelled before the party is formed")
+			return keygen.Response{Status: common.Cancelled}, nil
+		}
 		if onlinePeers == nil {
 			t.logger.Error().Err(err).Msg("error before we start join party")
 			return keygen.Response{
@@ -65,11 +174,17 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 	}
 
 	t.logger.Info().Msg("keygen party formed")
+	timeline.Add(blame.EventPartyFormed, "", "")
+	progress.running(keygenInstance.GetTssCommonStruct())
 	// the statistic of keygen only care about Tss it self, even if the
 	// following http response aborts, it still counted as a successful keygen
 	// as the Tss model runs successfully.
-	k, err := keygenInstance.GenerateNewKey(req)
+	k, err := keygenInstance.GenerateNewKeyWithContext(ctx, req)
 	blameMgr := keygenInstance.GetTssCommonStruct().GetBlameMgr()
+	if err != nil && ctx.Err() != nil {
+		t.logger.Info().Msg("keygen is cancelled")
+		return keygen.Response{Status: common.Cancelled}, nil
+	}
 	if err != nil {
 		atomic.AddUint64(&t.Status.FailedKeyGen, 1)
 		t.logger.Error().Err(err).Msg("err in keygen")
@@ -79,17 +194,35 @@ func (t *TssServer) Keygen(req keygen.Request) (keygen.Response, error) {
 		atomic.AddUint64(&t.Status.SucKeyGen, 1)
 	}
 
-	newPubKey, addr, err := conversion.GetTssPubKey(k)
+	newPubKey, addr, err := getTssPubKey(algo, k, t.conf.Bech32Prefix)
 	if err != nil {
 		t.logger.Error().Err(err).Msg("fail to generate the new Tss key")
 		status = common.Fail
 	}
 
 	blameNodes := *blameMgr.GetBlame()
-	return keygen.NewResponse(
+	resp = keygen.NewResponse(
 		newPubKey,
 		addr.String(),
 		status,
 		blameNodes,
-	), nil
+	)
+	if status == common.Success {
+		pubKeyInfo, err := conversion.GetPubKeyInfoFromBech32(newPubKey, req.CosmosPrefix)
+		if err != nil {
+			t.logger.Error().Err(err).Msg("fail to get the encodings of the pool key")
+		} else {
+			resp.PubKeyInfo = &pubKeyInfo
+		}
+	}
+	return resp, nil
+}
+
+// getTssPubKey return the pub key in bech32 with the given prefix and the address of the pool key of the given
+// algorithm
+func getTssPubKey(algo common.Algo, pubKeyPoint *bcrypto.ECPoint, prefix string) (string, types.AccAddress, error) {
+	if algo == common.EdDSA {
+		return conversion.GetEdDSATssPubKey(pubKeyPoint, prefix)
+	}
+	return conversion.GetTssPubKey(pubKeyPoint, prefix)
 }
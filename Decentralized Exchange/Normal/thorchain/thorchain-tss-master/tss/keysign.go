@@ -1,31 +1,125 @@
 package tss
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/libp2p/go-libp2p-core/peer"
 
+	"gitlab.com/thorchain/tss/go-tss/audit"
 	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+	"gitlab.com/thorchain/tss/go-tss/policy"
 )
 
 func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
+	return t.keySign(context.Background(), req, nil)
+}
+
+// KeySignWithContext run the keysign, once the given context is done the keysign is aborted and the response
+// status is set to cancelled
+func (t *TssServer) KeySignWithContext(ctx context.Context, req keysign.Request) (keysign.Response, error) {
+	return t.keySign(ctx, req, nil)
+}
+
+// KeySignWithProgress run the keysign like KeySignWithContext, and tell the given listener how far it gets, nothing
+// is told when the local node is not one of the signers
+func (t *TssServer) KeySignWithProgress(ctx context.Context, req keysign.Request, listener ProgressListener) (keysign.Response, error) {
+	return t.keySign(ctx, req, newListenedProgress(listener))
+}
+
+// KeySignAsync start the keysign in the background and return the id of the job straight away
+func (t *TssServer) KeySignAsync(req keysign.Request) (string, error) {
+	if t.sessions.isDraining() {
+		return "", ErrDraining
+	}
+	return t.jobManager.Submit(JobKeySign, func(ctx context.Context, progress *JobProgress) (interface{}, error) {
+		resp, err := t.keySign(ctx, req, progress)
+		if err == nil && resp.Status != common.Success {
+			err = errors.New("keysign failed")
+		}
+		return resp, err
+	})
+}
+
+// keySign run the keysign, the session is aborted once the given context is done, and how far it gets is reported
+// to the given progress
+func (t *TssServer) keySign(ctx context.Context, req keysign.Request, progress *JobProgress) (resp keysign.Response, err error) {
+	done, err := t.sessions.begin()
+	if err != nil {
+		return keysign.Response{}, err
+	}
+	defer done()
 	t.logger.Info().Str("pool pub key", req.PoolPubKey).
 		Str("signer pub keys", strings.Join(req.SignerPubKeys, ",")).
 		Str("msg", req.Message).
 		Msg("received keysign request")
 	emptyResp := keysign.Response{}
+	if req.PoolPubKey, err = t.normalizeKey(req.PoolPubKey); err != nil {
+		return emptyResp, err
+	}
+	if req.SignerPubKeys, err = t.normalizeKeys(req.SignerPubKeys); err != nil {
+		return emptyResp, err
+	}
 	msgID, err := t.requestToMsgId(req)
 	if err != nil {
 		return emptyResp, err
 	}
+	startTime := time.Now()
+	t.publishSessionCreated(msgID, monitor.OpKeySign)
+	defer func() {
+		monitor.ObserveBlame(monitor.OpKeySign, resp.Blame)
+		t.publishSessionFinished(msgID, monitor.OpKeySign, resp.Status, resp.Blame, err)
+		t.auditSession(audit.Entry{
+			SessionID:   msgID,
+			Operation:   audit.OpKeySign,
+			PoolPubKey:  req.PoolPubKey,
+			Parties:     req.SignerPubKeys,
+			MessageHash: hashMessage(req.Message),
+			StartTime:   startTime,
+			Outcome:     resp.Status.String(),
+			Blame:       audit.BlameSummary(resp.Blame),
+			R:           resp.R,
+			S:           resp.S,
+		}, err)
+	}()
+	// the key state is held till the keysign is done, so the key can't be retired or archived in the middle of it
+	t.keyStateLock.RLock()
+	defer t.keyStateLock.RUnlock()
+	localStateItem, err := t.stateManager.GetLocalState(req.PoolPubKey)
+	if err != nil {
+		return emptyResp, fmt.Errorf("fail to get local keygen state: %w", err)
+	}
+	if err := checkKeyState(localStateItem, req.Migration); err != nil {
+		return emptyResp, err
+	}
+	algo := localStateItem.GetAlgo()
+	if req.Algo != "" && req.Algo != algo {
+		return emptyResp, fmt.Errorf("the pool key is %s, not %s", algo, req.Algo)
+	}
+	// the signature of the child key is verified against the child key rather than the pool key
+	verifyPubKey := req.PoolPubKey
+	if len(req.DerivationPath) != 0 {
+		childPubKey, _, tweak, err := conversion.GetDerivedPubKey(req.PoolPubKey, req.DerivationPath, t.conf.Bech32Prefix)
+		if err != nil {
+			return emptyResp, fmt.Errorf("fail to derive the child key: %w", err)
+		}
+		localStateItem, err = keysign.DeriveLocalState(localStateItem, tweak)
+		if err != nil {
+			return emptyResp, fmt.Errorf("fail to derive the local state: %w", err)
+		}
+		verifyPubKey = childPubKey
+	}
 
 	keysignInstance := keysign.NewTssKeySign(
 		t.p2pCommunication.GetLocalPeerID(),
@@ -37,22 +131,20 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		t.p2pCommunication,
 		t.stateManager,
 	)
+	keysignInstance.GetTssCommonStruct().SetEventBus(t.eventBus)
 
 	keySignChannels := keysignInstance.GetTssKeySignChannels()
-	t.p2pCommunication.SetSubscribe(messages.TSSKeySignMsg, msgID, keySignChannels)
-	t.p2pCommunication.SetSubscribe(messages.TSSKeySignVerMsg, msgID, keySignChannels)
+	keySignMsgType, keySignVerMsgType := keysign.GetMsgTypes(algo)
+	t.p2pCommunication.SetSubscribe(keySignMsgType, msgID, keySignChannels)
+	t.p2pCommunication.SetSubscribe(keySignVerMsgType, msgID, keySignChannels)
 	t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, msgID, keySignChannels)
 	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, keySignChannels)
 
-	defer t.p2pCommunication.CancelSubscribe(messages.TSSKeySignMsg, msgID)
-	defer t.p2pCommunication.CancelSubscribe(messages.TSSKeySignVerMsg, msgID)
+	defer t.p2pCommunication.CancelSubscribe(keySignMsgType, msgID)
+	defer t.p2pCommunication.CancelSubscribe(keySignVerMsgType, msgID)
 	defer t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, msgID)
 	defer t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
 
-	localStateItem, err := t.stateManager.GetLocalState(req.PoolPubKey)
-	if err != nil {
-		return emptyResp, fmt.Errorf("fail to get local keygen state: %w", err)
-	}
 	msgToSign, err := base64.StdEncoding.DecodeString(req.Message)
 	if err != nil {
 		return emptyResp, fmt.Errorf("fail to decode message(%s): %w", req.Message, err)
@@ -61,7 +153,7 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		return emptyResp, errors.New("empty signer pub keys")
 	}
 
-	threshold, err := common.GetThreshold(len(localStateItem.ParticipantKeys))
+	threshold, err := localStateItem.GetThreshold()
 	if err != nil {
 		t.logger.Error().Err(err).Msg("fail to get the threshold")
 		return emptyResp, errors.New("fail to get threshold")
@@ -72,9 +164,13 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	}
 
 	if !t.isPartOfKeysignParty(req.SignerPubKeys) {
+		progress.running(nil)
 		// TSS keysign include both form party and keysign itself, thus we wait twice of the timeout
-		data, err := t.signatureNotifier.WaitForSignature(msgID, msgToSign, req.PoolPubKey, t.conf.KeySignTimeout*2)
+		data, err := t.signatureNotifier.WaitForSignature(ctx, msgID, msgToSign, verifyPubKey, t.conf.KeySignTimeout*2)
 		if err != nil {
+			if ctx.Err() != nil {
+				return keysign.Response{Status: common.Cancelled}, nil
+			}
 			return emptyResp, fmt.Errorf("fail to get signature:%w", err)
 		}
 		if data == nil || (len(data.S) == 0 && len(data.R) == 0) {
@@ -83,19 +179,67 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 		return keysign.NewResponse(
 			base64.StdEncoding.EncodeToString(data.R),
 			base64.StdEncoding.EncodeToString(data.S),
+			keysign.GetRecoveryID(data.SignatureRecovery),
 			common.Success,
 			blame.Blame{},
 		), nil
 	}
+	// the other signers are told straight away, so they don't wait for us till the party timeout
+	reservation, err := t.policy.Check(policy.Request{
+		PoolPubKey: req.PoolPubKey,
+		Messages:   [][]byte{msgToSign},
+		Metadata:   req.Metadata,
+	})
+	if err != nil {
+		t.logger.Warn().Err(err).Str("msgID", msgID).Msg("refuse to join the keysign")
+		t.refuseParty(msgID, req.SignerPubKeys)
+		return keysign.Response{
+			Status: common.Rejected,
+			Blame:  blame.NewBlame(err.Error(), []blame.Node{{Pubkey: t.localNodePubKey}}),
+		}, nil
+	}
+	// only the signature actually produced counts towards the rate limits
+	defer func() {
+		if err == nil && resp.Status == common.Success {
+			reservation.Commit(1)
+			return
+		}
+		reservation.Release()
+	}()
 	blameMgr := keysignInstance.GetTssCommonStruct().GetBlameMgr()
+	timeline := blameMgr.GetTimeline()
+	defer func() {
+		resp.Timeline = t.keepTimeline(msgID, timeline)
+	}()
 	// get all the tss nodes that were part of the original key gen
 	signers, err := conversion.GetPeerIDs(localStateItem.ParticipantKeys)
 	if err != nil {
 		return emptyResp, fmt.Errorf("fail to convert pub keys to peer id:%w", err)
 	}
 
-	onlinePeers, err := t.joinParty(msgID, req.SignerPubKeys)
+	var releaseCurve func()
+	ctx, releaseCurve, err = t.acquireCurve(ctx, monitor.OpKeySign, algo, nil)
+	if err != nil {
+		t.broadcastKeysignFailure(msgID, signers)
+		return emptyResp, err
+	}
+	defer releaseCurve()
+	progress.joiningParty()
+	onlinePeers, err := t.joinParty(ctx, monitor.OpKeySign, msgID, req.SignerPubKeys)
 	if err != nil {
+		var refusedErr *p2p.JoinPartyRefusedError
+		if errors.As(err, &refusedErr) {
+			t.broadcastKeysignFailure(msgID, signers)
+			return keysign.Response{
+				Status: common.Rejected,
+				Blame:  t.refusedBlame(refusedErr.Peers),
+			}, nil
+		}
+		if ctx.Err() != nil {
+			t.logger.Info().Msg("keysign is cancelled before the party is formed")
+			t.broadcastKeysignFailure(msgID, signers)
+			return keysign.Response{Status: common.Cancelled}, nil
+		}
 		if onlinePeers == nil {
 			t.logger.Error().Err(err).Msg("error before we start join party")
 			t.broadcastKeysignFailure(msgID, signers)
@@ -119,7 +263,14 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 
 	}
 
-	signatureData, err := keysignInstance.SignMessage(msgToSign, localStateItem, req.SignerPubKeys)
+	timeline.Add(blame.EventPartyFormed, "", "")
+	progress.running(keysignInstance.GetTssCommonStruct())
+	signatureData, err := keysignInstance.SignMessageWithContext(ctx, msgToSign, localStateItem, req.SignerPubKeys)
+	if err != nil && ctx.Err() != nil {
+		t.logger.Info().Msg("keysign is cancelled")
+		t.broadcastKeysignFailure(msgID, signers)
+		return keysign.Response{Status: common.Cancelled}, nil
+	}
 	// the statistic of keygen only care about Tss it self, even if the following http response aborts,
 	// it still counted as a successful keygen as the Tss model runs successfully.
 	if err != nil {
@@ -142,6 +293,7 @@ func (t *TssServer) KeySign(req keysign.Request) (keysign.Response, error) {
 	return keysign.NewResponse(
 		base64.StdEncoding.EncodeToString(signatureData.R),
 		base64.StdEncoding.EncodeToString(signatureData.S),
+		keysign.GetRecoveryID(signatureData.SignatureRecovery),
 		common.Success,
 		blame.Blame{},
 	), nil
@@ -161,3 +313,19 @@ func (t *TssServer) isPartOfKeysignParty(parties []string) bool {
 	}
 	return false
 }
+
+// DerivePubKey return the non-hardened BIP32 child key of the pool along the given path, and its address, the chain
+// code of the pool is the SHA256 of the compressed pool key, see conversion.PoolChainCode
+func (t *TssServer) DerivePubKey(req keysign.DeriveRequest) (keysign.DeriveResponse, error) {
+	if len(req.PoolPubKey) == 0 {
+		return keysign.DeriveResponse{}, errors.New("empty pool pub key")
+	}
+	pubKey, addr, _, err := conversion.GetDerivedPubKey(req.PoolPubKey, req.DerivationPath, t.conf.Bech32Prefix)
+	if err != nil {
+		return keysign.DeriveResponse{}, fmt.Errorf("fail to derive the child key: %w", err)
+	}
+	return keysign.DeriveResponse{
+		PubKey:  pubKey,
+		Address: addr.String(),
+	}, nil
+}
@@ -0,0 +1,351 @@
+package tss
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+	"gitlab.com/thorchain/tss/go-tss/policy"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// KeySignBatch sign all the messages in the given request, the keysign party is formed only once for the whole batch,
+// and then all the messages are signed in parallel. The responses are returned in the same order as the messages.
+func (t *TssServer) KeySignBatch(req keysign.BatchRequest) ([]keysign.Response, error) {
+	return t.KeySignBatchWithContext(context.Background(), req)
+}
+
+// KeySignBatchWithContext is KeySignBatch that gives up once the given context is done, the messages not signed by
+// then are cancelled
+func (t *TssServer) KeySignBatchWithContext(ctx context.Context, req keysign.BatchRequest) (result []keysign.Response, err error) {
+	done, err := t.sessions.begin()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	if ctx.Err() != nil {
+		return cancelledResponses(len(req.Messages)), nil
+	}
+	t.logger.Info().Str("pool pub key", req.PoolPubKey).
+		Str("signer pub keys", strings.Join(req.SignerPubKeys, ",")).
+		Int("messages", len(req.Messages)).
+		Msg("received batch keysign request")
+	if len(req.Messages) == 0 {
+		return nil, errors.New("empty messages")
+	}
+	if req.PoolPubKey, err = t.normalizeKey(req.PoolPubKey); err != nil {
+		return nil, err
+	}
+	if req.SignerPubKeys, err = t.normalizeKeys(req.SignerPubKeys); err != nil {
+		return nil, err
+	}
+	msgID, err := t.requestToMsgId(req)
+	if err != nil {
+		return nil, err
+	}
+	startTime := time.Now()
+	// every message of the batch is a session on its own
+	for i := range req.Messages {
+		t.publishSessionCreated(getBatchMsgID(msgID, i), monitor.OpKeySign)
+	}
+	defer func() {
+		for i, msg := range req.Messages {
+			entry := audit.Entry{
+				SessionID:   getBatchMsgID(msgID, i),
+				Operation:   audit.OpKeySign,
+				PoolPubKey:  req.PoolPubKey,
+				Parties:     req.SignerPubKeys,
+				MessageHash: hashMessage(msg),
+				StartTime:   startTime,
+			}
+			var status common.Status
+			var b blame.Blame
+			if i < len(result) {
+				status, b = result[i].Status, result[i].Blame
+			}
+			t.publishSessionFinished(entry.SessionID, monitor.OpKeySign, status, b, err)
+			if i < len(result) {
+				monitor.ObserveBlame(monitor.OpKeySign, result[i].Blame)
+				entry.Outcome = result[i].Status.String()
+				entry.Blame = audit.BlameSummary(result[i].Blame)
+				entry.R = result[i].R
+				entry.S = result[i].S
+			}
+			t.auditSession(entry, err)
+		}
+	}()
+
+	// the key state is held till the keysign is done, so the key can't be retired or archived in the middle of it
+	t.keyStateLock.RLock()
+	defer t.keyStateLock.RUnlock()
+	localStateItem, err := t.stateManager.GetLocalState(req.PoolPubKey)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get local keygen state: %w", err)
+	}
+	if err := checkKeyState(localStateItem, req.Migration); err != nil {
+		return nil, err
+	}
+	msgsToSign := make([][]byte, len(req.Messages))
+	for i, msg := range req.Messages {
+		msgToSign, err := base64.StdEncoding.DecodeString(msg)
+		if err != nil {
+			return nil, fmt.Errorf("fail to decode message(%s): %w", msg, err)
+		}
+		msgsToSign[i] = msgToSign
+	}
+	if len(req.SignerPubKeys) == 0 {
+		return nil, errors.New("empty signer pub keys")
+	}
+
+	threshold, err := localStateItem.GetThreshold()
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the threshold")
+		return nil, errors.New("fail to get threshold")
+	}
+	if len(req.SignerPubKeys) <= threshold {
+		t.logger.Error().Msgf("not enough signers, threshold=%d and signers=%d", threshold, len(req.SignerPubKeys))
+		return nil, errors.New("not enough signers")
+	}
+
+	if !t.isPartOfKeysignParty(req.SignerPubKeys) {
+		return t.waitForBatchSignatures(ctx, msgID, msgsToSign, req.PoolPubKey), nil
+	}
+
+	reservation, err := t.policy.Check(policy.Request{
+		PoolPubKey: req.PoolPubKey,
+		Messages:   msgsToSign,
+		Metadata:   req.Metadata,
+	})
+	if err != nil {
+		t.logger.Warn().Err(err).Str("msgID", msgID).Msg("refuse to join the batch keysign")
+		t.refuseParty(msgID, req.SignerPubKeys)
+		return rejectedResponses(len(msgsToSign), blame.NewBlame(err.Error(), []blame.Node{{Pubkey: t.localNodePubKey}})), nil
+	}
+	// only the signatures actually produced count towards the rate limits
+	defer func() {
+		signed := 0
+		for _, el := range result {
+			if el.Status == common.Success {
+				signed++
+			}
+		}
+		reservation.Commit(signed)
+	}()
+	// get all the tss nodes that were part of the original key gen
+	signers, err := conversion.GetPeerIDs(localStateItem.ParticipantKeys)
+	if err != nil {
+		return nil, fmt.Errorf("fail to convert pub keys to peer id:%w", err)
+	}
+
+	// every message in the batch is signed in its own session, the session IDs are derived from the batch msgID
+	// so all the parties agree on them without any extra coordination
+	keySignMsgType, keySignVerMsgType := keysign.GetMsgTypes(localStateItem.GetAlgo())
+	keysignInstances := make([]*keysign.TssKeySign, len(msgsToSign))
+	for i := range msgsToSign {
+		subMsgID := getBatchMsgID(msgID, i)
+		keysignInstance := keysign.NewTssKeySign(
+			t.p2pCommunication.GetLocalPeerID(),
+			t.conf,
+			t.p2pCommunication.BroadcastMsgChan,
+			t.stopChan,
+			subMsgID,
+			t.privateKey,
+			t.p2pCommunication,
+			t.stateManager,
+		)
+		keysignInstance.GetTssCommonStruct().SetEventBus(t.eventBus)
+		keySignChannels := keysignInstance.GetTssKeySignChannels()
+		t.p2pCommunication.SetSubscribe(keySignMsgType, subMsgID, keySignChannels)
+		t.p2pCommunication.SetSubscribe(keySignVerMsgType, subMsgID, keySignChannels)
+		t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, subMsgID, keySignChannels)
+		t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, subMsgID, keySignChannels)
+
+		defer t.p2pCommunication.CancelSubscribe(keySignMsgType, subMsgID)
+		defer t.p2pCommunication.CancelSubscribe(keySignVerMsgType, subMsgID)
+		defer t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, subMsgID)
+		defer t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, subMsgID)
+		keysignInstances[i] = keysignInstance
+	}
+	defer func() {
+		for i, el := range keysignInstances {
+			summary := t.keepTimeline(getBatchMsgID(msgID, i), el.GetTssCommonStruct().GetBlameMgr().GetTimeline())
+			if i < len(result) {
+				result[i].Timeline = summary
+			}
+		}
+	}()
+
+	// the sessions of the batch share the curve
+	var releaseCurve func()
+	ctx, releaseCurve, err = t.acquireCurve(ctx, monitor.OpKeySign, localStateItem.GetAlgo(), nil)
+	if err != nil {
+		for i := range msgsToSign {
+			t.broadcastKeysignFailure(getBatchMsgID(msgID, i), signers)
+		}
+		return nil, err
+	}
+	defer releaseCurve()
+	onlinePeers, err := t.joinParty(ctx, monitor.OpKeySign, msgID, req.SignerPubKeys)
+	if err != nil {
+		var refusedErr *p2p.JoinPartyRefusedError
+		if errors.As(err, &refusedErr) {
+			for i := range msgsToSign {
+				t.broadcastKeysignFailure(getBatchMsgID(msgID, i), signers)
+			}
+			return rejectedResponses(len(msgsToSign), t.refusedBlame(refusedErr.Peers)), nil
+		}
+		if ctx.Err() != nil {
+			t.logger.Info().Msg("batch keysign is cancelled before the party is formed")
+			for i := range msgsToSign {
+				t.broadcastKeysignFailure(getBatchMsgID(msgID, i), signers)
+			}
+			return cancelledResponses(len(msgsToSign)), nil
+		}
+		var blameNodes blame.Blame
+		if onlinePeers == nil {
+			t.logger.Error().Err(err).Msg("error before we start join party")
+			blameNodes = blame.NewBlame(blame.InternalError, []blame.Node{})
+		} else {
+			blameMgr := keysignInstances[0].GetTssCommonStruct().GetBlameMgr()
+			blameNodes, err = blameMgr.NodeSyncBlame(req.SignerPubKeys, onlinePeers)
+			if err != nil {
+				t.logger.Err(err).Msg("fail to get peers to blame")
+			}
+			t.logger.Error().Err(err).Msgf("fail to form batch keysign party with online:%v", onlinePeers)
+		}
+		responses := make([]keysign.Response, len(msgsToSign))
+		for i := range msgsToSign {
+			t.broadcastKeysignFailure(getBatchMsgID(msgID, i), signers)
+			responses[i] = keysign.Response{
+				Status: common.Fail,
+				Blame:  blameNodes,
+			}
+		}
+		return responses, nil
+	}
+
+	for _, el := range keysignInstances {
+		el.GetTssCommonStruct().GetBlameMgr().GetTimeline().Add(blame.EventPartyFormed, "", "")
+	}
+	responses := make([]keysign.Response, len(msgsToSign))
+	var wg sync.WaitGroup
+	for i, keysignInstance := range keysignInstances {
+		wg.Add(1)
+		go func(idx int, keysignInstance *keysign.TssKeySign) {
+			defer wg.Done()
+			responses[idx] = t.signOneInBatch(ctx, keysignInstance, getBatchMsgID(msgID, idx), msgsToSign[idx], localStateItem, req.SignerPubKeys, signers)
+		}(i, keysignInstance)
+	}
+	wg.Wait()
+	return responses, nil
+}
+
+// signOneInBatch run the keysign of one message in the batch, the party has already been formed
+func (t *TssServer) signOneInBatch(ctx context.Context, keysignInstance *keysign.TssKeySign, msgID string, msgToSign []byte, localStateItem storage.KeygenLocalState, signerPubKeys []string, signers []peer.ID) keysign.Response {
+	signatureData, err := keysignInstance.SignMessageWithContext(ctx, msgToSign, localStateItem, signerPubKeys)
+	if err != nil && ctx.Err() != nil {
+		t.logger.Info().Str("msgID", msgID).Msg("batch keysign is cancelled")
+		t.broadcastKeysignFailure(msgID, signers)
+		return keysign.Response{Status: common.Cancelled}
+	}
+	// the statistic of keysign only care about Tss it self, each message in the batch is counted separately
+	if err != nil {
+		t.logger.Error().Err(err).Str("msgID", msgID).Msg("err in batch keysign")
+		atomic.AddUint64(&t.Status.FailedKeySign, 1)
+		t.broadcastKeysignFailure(msgID, signers)
+		blameNodes := *keysignInstance.GetTssCommonStruct().GetBlameMgr().GetBlame()
+		return keysign.Response{
+			Status: common.Fail,
+			Blame:  blameNodes,
+		}
+	}
+
+	atomic.AddUint64(&t.Status.SucKeySign, 1)
+
+	// update signature notification
+	if err := t.signatureNotifier.BroadcastSignature(msgID, signatureData, signers); err != nil {
+		t.logger.Error().Err(err).Str("msgID", msgID).Msg("fail to broadcast signature")
+	}
+	return keysign.NewResponse(
+		base64.StdEncoding.EncodeToString(signatureData.R),
+		base64.StdEncoding.EncodeToString(signatureData.S),
+		keysign.GetRecoveryID(signatureData.SignatureRecovery),
+		common.Success,
+		blame.Blame{},
+	)
+}
+
+// waitForBatchSignatures is used by the nodes that are not in the signer list, they wait for the signature
+// of every message in the batch to be sent over by the keysign party
+func (t *TssServer) waitForBatchSignatures(ctx context.Context, msgID string, msgsToSign [][]byte, poolPubKey string) []keysign.Response {
+	responses := make([]keysign.Response, len(msgsToSign))
+	var wg sync.WaitGroup
+	for i, msgToSign := range msgsToSign {
+		wg.Add(1)
+		go func(idx int, msgToSign []byte) {
+			defer wg.Done()
+			subMsgID := getBatchMsgID(msgID, idx)
+			// TSS keysign include both form party and keysign itself, thus we wait twice of the timeout
+			data, err := t.signatureNotifier.WaitForSignature(ctx, subMsgID, msgToSign, poolPubKey, t.conf.KeySignTimeout*2)
+			if err != nil && ctx.Err() != nil {
+				responses[idx] = keysign.Response{Status: common.Cancelled}
+				return
+			}
+			if err != nil || data == nil || (len(data.S) == 0 && len(data.R) == 0) {
+				t.logger.Error().Err(err).Str("msgID", subMsgID).Msg("fail to get signature")
+				responses[idx] = keysign.NewResponse("", "", nil, common.Fail, blame.Blame{})
+				return
+			}
+			responses[idx] = keysign.NewResponse(
+				base64.StdEncoding.EncodeToString(data.R),
+				base64.StdEncoding.EncodeToString(data.S),
+				keysign.GetRecoveryID(data.SignatureRecovery),
+				common.Success,
+				blame.Blame{},
+			)
+		}(i, msgToSign)
+	}
+	wg.Wait()
+	return responses
+}
+
+// rejectedResponses return the responses of the batch refused by the given nodes
+func rejectedResponses(n int, blameNodes blame.Blame) []keysign.Response {
+	responses := make([]keysign.Response, n)
+	for i := range responses {
+		responses[i] = keysign.Response{
+			Status: common.Rejected,
+			Blame:  blameNodes,
+		}
+	}
+	return responses
+}
+
+// cancelledResponses return the responses of the batch that is cancelled
+func cancelledResponses(n int) []keysign.Response {
+	responses := make([]keysign.Response, n)
+	for i := range responses {
+		responses[i] = keysign.Response{Status: common.Cancelled}
+	}
+	return responses
+}
+
+// getBatchMsgID return the msgID of the keysign session of the message at the given index of the batch
+func getBatchMsgID(msgID string, idx int) string {
+	return fmt.Sprintf("%s-%d", msgID, idx)
+}
@@ -0,0 +1,33 @@
+package tss
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// ErrKeyNotFound is returned when the local node holds no share of the given pool key
+var ErrKeyNotFound = errors.New("key not found")
+
+// ListLocalKeys return the pools the local node holds the key shares of, the secret shares are never returned
+func (t *TssServer) ListLocalKeys() ([]storage.LocalStateInfo, error) {
+	return t.stateManager.ListLocalStates()
+}
+
+// GetLocalKey return the given pool the local node holds the key share of, the pool key is in bech32 or hex
+func (t *TssServer) GetLocalKey(pubKey string) (storage.LocalStateInfo, error) {
+	normalized, err := t.normalizeKey(pubKey)
+	if err != nil {
+		return storage.LocalStateInfo{}, fmt.Errorf("%w: %s", ErrKeyNotFound, err)
+	}
+	info, err := t.stateManager.GetLocalStateInfo(normalized)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return storage.LocalStateInfo{}, ErrKeyNotFound
+		}
+		return storage.LocalStateInfo{}, err
+	}
+	return info, nil
+}
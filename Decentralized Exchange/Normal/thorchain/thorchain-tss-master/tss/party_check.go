@@ -0,0 +1,68 @@
+package tss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/p2p"
+)
+
+// PartyCheckRequest is the request to check whether the given nodes can be reached
+type PartyCheckRequest struct {
+	Keys []string `json:"keys"`
+}
+
+// NodeReachability is the reachability of a node of the party
+type NodeReachability struct {
+	PubKey string `json:"pub_key"`
+	p2p.PeerReachability
+}
+
+// PartyCheckResponse is the reachability of each node of the party, in the order of the keys in the request
+type PartyCheckResponse struct {
+	AllReachable bool               `json:"all_reachable"`
+	Nodes        []NodeReachability `json:"nodes"`
+}
+
+// CheckParty connect to and ping each of the given nodes, the invalid key is reported as an unreachable node, so
+// the callers find out all the problems of the party before they submit a keygen or keysign, the node given more
+// than once is only checked once, and the check stops when the ctx is done
+func (t *TssServer) CheckParty(ctx context.Context, keys []string) (PartyCheckResponse, error) {
+	if len(keys) == 0 {
+		return PartyCheckResponse{}, errors.New("empty keys")
+	}
+	nodes := make([]NodeReachability, len(keys))
+	var peers []peer.ID
+	peerIdx := make(map[peer.ID][]int)
+	for i, el := range keys {
+		nodes[i].PubKey = el
+		pID, err := conversion.GetPeerIDFromPubKey(el)
+		if err != nil {
+			nodes[i].Error = fmt.Sprintf("invalid pub key: %s", err)
+			continue
+		}
+		nodes[i].PeerID = pID.String()
+		if _, ok := peerIdx[pID]; !ok {
+			peers = append(peers, pID)
+		}
+		peerIdx[pID] = append(peerIdx[pID], i)
+	}
+	results := p2p.CheckPeers(ctx, t.p2pCommunication.GetHost(), peers, p2p.TimeoutPeerCheck)
+	for i, el := range results {
+		for _, idx := range peerIdx[peers[i]] {
+			nodes[idx].PeerReachability = el
+		}
+	}
+	allReachable := true
+	for _, el := range nodes {
+		allReachable = allReachable && el.Reachable
+	}
+	return PartyCheckResponse{
+		AllReachable: allReachable,
+		Nodes:        nodes,
+	}, nil
+}
@@ -0,0 +1,235 @@
+package tss
+
+import (
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// preParamPoolCipherLabel is the label of the key the persisted pool is encrypted with
+const preParamPoolCipherLabel = "tss-preparam-pool"
+
+// errPreParamPoolDisabled is returned when the pool of size zero runs out of the pre-parameters given to it
+var errPreParamPoolDisabled = errors.New("pre-parameter pool is empty and its size is zero, so nothing is generated for it")
+
+// preParamPool keeps a number of pre-parameters ready for keygen, it is filled in background and every keygen
+// takes one entry out of it, so the Paillier keys and safe primes are never reused across keygen.
+// The unused entries are persisted encrypted with the key derived from the node private key.
+type preParamPool struct {
+	logger       zerolog.Logger
+	lock         *sync.Mutex
+	entries      []*bkeygen.LocalPreParams
+	size         int
+	concurrency  int
+	timeout      time.Duration
+	stateManager storage.LocalStateManager
+	aead         cipher.AEAD
+	added        chan struct{}
+	needMore     chan struct{}
+	stopChan     chan struct{}
+}
+
+func newPreParamPool(size, concurrency int, timeout time.Duration, stateManager storage.LocalStateManager, priKeyRawBytes []byte) (*preParamPool, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	aead, err := newNodeKeyCipher(preParamPoolCipherLabel, priKeyRawBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &preParamPool{
+		logger:       log.With().Str("module", "preparam_pool").Logger(),
+		lock:         &sync.Mutex{},
+		size:         size,
+		concurrency:  concurrency,
+		timeout:      timeout,
+		stateManager: stateManager,
+		aead:         aead,
+		added:        make(chan struct{}, 1),
+		needMore:     make(chan struct{}, 1),
+		stopChan:     make(chan struct{}),
+	}, nil
+}
+
+// load read the persisted entries back, a missing pool file is not an error
+func (p *preParamPool) load() error {
+	buf, err := p.stateManager.RetrievePreParamPool()
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("fail to read the pre-parameter pool: %w", err)
+	}
+	nonceSize := p.aead.NonceSize()
+	if len(buf) < nonceSize {
+		return errors.New("invalid pre-parameter pool file")
+	}
+	plainText, err := p.aead.Open(nil, buf[:nonceSize], buf[nonceSize:], nil)
+	if err != nil {
+		return fmt.Errorf("fail to decrypt the pre-parameter pool: %w", err)
+	}
+	var entries []*bkeygen.LocalPreParams
+	if err := json.Unmarshal(plainText, &entries); err != nil {
+		return fmt.Errorf("fail to unmarshal the pre-parameter pool: %w", err)
+	}
+	for _, item := range entries {
+		p.add(item)
+	}
+	return nil
+}
+
+// persist save the unused entries, the caller should hold the lock
+func (p *preParamPool) persist() {
+	plainText, err := json.Marshal(p.entries)
+	if err != nil {
+		p.logger.Error().Err(err).Msg("fail to marshal the pre-parameter pool")
+		return
+	}
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		p.logger.Error().Err(err).Msg("fail to generate nonce")
+		return
+	}
+	if err := p.stateManager.SavePreParamPool(p.aead.Seal(nonce, nonce, plainText, nil)); err != nil {
+		p.logger.Error().Err(err).Msg("fail to save the pre-parameter pool")
+	}
+}
+
+// add put the given pre-parameters into the pool, invalid and duplicated entries are dropped
+func (p *preParamPool) add(preParams *bkeygen.LocalPreParams) bool {
+	if preParams == nil || !preParams.Validate() {
+		p.logger.Error().Msg("drop the invalid pre-parameters")
+		return false
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, item := range p.entries {
+		if item.PaillierSK.N.Cmp(preParams.PaillierSK.N) == 0 {
+			return false
+		}
+	}
+	p.entries = append(p.entries, preParams)
+	p.persist()
+	select {
+	case p.added <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// Start the background generation, the pool is not refilled if its size is zero
+func (p *preParamPool) Start() {
+	if p.size <= 0 {
+		return
+	}
+	go p.fill()
+	p.requestMore()
+}
+
+// Stop the background generation, the generation in progress is left to finish in background
+func (p *preParamPool) Stop() {
+	close(p.stopChan)
+}
+
+func (p *preParamPool) requestMore() {
+	select {
+	case p.needMore <- struct{}{}:
+	default:
+	}
+}
+
+func (p *preParamPool) fill() {
+	for {
+		select {
+		case <-p.stopChan:
+			return
+		case <-p.needMore:
+		}
+		for p.Depth() < p.size {
+			p.logger.Info().Msgf("generating pre-parameters, pool depth %d/%d", p.Depth(), p.size)
+			preParams, err := bkeygen.GeneratePreParams(p.timeout, p.concurrency)
+			if err != nil {
+				p.logger.Error().Err(err).Msg("fail to generate pre-parameters")
+				// back off a bit before we retry
+				select {
+				case <-p.stopChan:
+					return
+				case <-time.After(10 * time.Second):
+				}
+				continue
+			}
+			p.add(preParams)
+			select {
+			case <-p.stopChan:
+				return
+			default:
+			}
+		}
+	}
+}
+
+// Depth return how many pre-parameters are ready to use
+func (p *preParamPool) Depth() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.entries)
+}
+
+// Take remove one entry from the pool, if the pool is empty, it waits for the background generation till the
+// context is done. The pool of size zero is never refilled, so it fails straight away once it is empty
+func (p *preParamPool) Take(ctx context.Context) (*bkeygen.LocalPreParams, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	timeout := time.After(p.timeout)
+	for {
+		if preParams := p.pop(); preParams != nil {
+			p.requestMore()
+			return preParams, nil
+		}
+		if p.size <= 0 {
+			return nil, errPreParamPoolDisabled
+		}
+		select {
+		case <-p.added:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-p.stopChan:
+			return nil, errors.New("pre-parameter pool is stopped")
+		case <-timeout:
+			return nil, errors.New("timeout waiting for pre-parameters")
+		}
+	}
+}
+
+// Return put back the pre-parameters that were taken but never used, which is the case when the keygen party
+// can't be formed
+func (p *preParamPool) Return(preParams *bkeygen.LocalPreParams) {
+	p.add(preParams)
+}
+
+func (p *preParamPool) pop() *bkeygen.LocalPreParams {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if len(p.entries) == 0 {
+		return nil
+	}
+	preParams := p.entries[0]
+	p.entries = p.entries[1:]
+	// persist straight away, so the entry can't be used again even if we crash during the keygen
+	p.persist()
+	return preParams
+}
@@ -0,0 +1,97 @@
+package tss
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type PreParamPoolTestSuite struct{}
+
+var _ = Suite(&PreParamPoolTestSuite{})
+
+func (s *PreParamPoolTestSuite) TestPersistence(c *C) {
+	folder, err := ioutil.TempDir("", "preparam_pool")
+	c.Assert(err, IsNil)
+	defer func() {
+		c.Assert(os.RemoveAll(folder), IsNil)
+	}()
+	stateMgr, err := storage.NewFileStateMgr(folder)
+	c.Assert(err, IsNil)
+	preParams := getPreparams(c)
+
+	pool, err := newPreParamPool(0, 1, time.Second, stateMgr, []byte("hello"))
+	c.Assert(err, IsNil)
+	c.Assert(pool.load(), IsNil)
+	c.Assert(pool.Depth(), Equals, 0)
+	c.Assert(pool.add(preParams[0]), Equals, true)
+	c.Assert(pool.add(preParams[1]), Equals, true)
+	// the same pre-parameters can't be added twice
+	c.Assert(pool.add(preParams[0]), Equals, false)
+	c.Assert(pool.Depth(), Equals, 2)
+
+	taken, err := pool.Take(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(taken.PaillierSK.N.Cmp(preParams[0].PaillierSK.N), Equals, 0)
+	c.Assert(pool.Depth(), Equals, 1)
+
+	// only the unused entry is loaded back
+	pool1, err := newPreParamPool(0, 1, time.Second, stateMgr, []byte("hello"))
+	c.Assert(err, IsNil)
+	c.Assert(pool1.load(), IsNil)
+	c.Assert(pool1.Depth(), Equals, 1)
+	taken, err = pool1.Take(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(taken.PaillierSK.N.Cmp(preParams[1].PaillierSK.N), Equals, 0)
+
+	// the pool can't be read with a different key
+	pool2, err := newPreParamPool(0, 1, time.Second, stateMgr, []byte("world"))
+	c.Assert(err, IsNil)
+	c.Assert(pool2.load(), NotNil)
+}
+
+func (s *PreParamPoolTestSuite) TestTakeFromDisabledPool(c *C) {
+	pool, err := newPreParamPool(0, 1, time.Minute, &storage.MockLocalStateManager{}, []byte("hello"))
+	c.Assert(err, IsNil)
+	// nothing is generated for the pool of size zero, so it doesn't wait
+	_, err = pool.Take(context.Background())
+	c.Assert(err, Equals, errPreParamPoolDisabled)
+
+	preParams := getPreparams(c)
+	c.Assert(pool.add(preParams[0]), Equals, true)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = pool.Take(ctx)
+	c.Assert(err, Equals, context.Canceled)
+	taken, err := pool.Take(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(taken.PaillierSK.N.Cmp(preParams[0].PaillierSK.N), Equals, 0)
+}
+
+func (s *PreParamPoolTestSuite) TestTakeWaitForGeneration(c *C) {
+	pool, err := newPreParamPool(1, 1, time.Minute, &storage.MockLocalStateManager{}, []byte("hello"))
+	c.Assert(err, IsNil)
+	// the background generation is not started, so we wait until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = pool.Take(ctx)
+	c.Assert(errors.Is(err, context.DeadlineExceeded), Equals, true)
+
+	preParams := getPreparams(c)
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		pool.Return(preParams[0])
+	}()
+	taken, err := pool.Take(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(taken.PaillierSK.N.Cmp(preParams[0].PaillierSK.N), Equals, 0)
+	pool.Stop()
+	_, err = pool.Take(context.Background())
+	c.Assert(err, NotNil)
+}
@@ -0,0 +1,222 @@
+package tss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
+	"gitlab.com/thorchain/tss/go-tss/blame"
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+// Reshare move the key of the given pool from the old committee to the new committee, the pool public key stays the
+// same, the members of the new committee save their new key shares once the resharing is done
+func (t *TssServer) Reshare(req reshare.Request) (reshare.Response, error) {
+	return t.ReshareWithContext(context.Background(), req)
+}
+
+// ReshareWithContext run the resharing, once the given context is done the resharing is aborted and the response
+// status is set to cancelled
+func (t *TssServer) ReshareWithContext(ctx context.Context, req reshare.Request) (resp reshare.Response, err error) {
+	done, err := t.sessions.begin()
+	if err != nil {
+		return reshare.Response{}, err
+	}
+	defer done()
+	t.tssKeyGenLocker.Lock()
+	defer t.tssKeyGenLocker.Unlock()
+	if ctx.Err() != nil {
+		return reshare.Response{Status: common.Cancelled}, nil
+	}
+	if len(req.PoolPubKey) == 0 {
+		return reshare.Response{}, errors.New("empty pool pub key")
+	}
+	if len(req.OldPartyKeys) == 0 || len(req.NewPartyKeys) == 0 {
+		return reshare.Response{}, errors.New("empty party keys")
+	}
+	if req.PoolPubKey, err = t.normalizeKey(req.PoolPubKey); err != nil {
+		return reshare.Response{}, err
+	}
+	if req.OldPartyKeys, err = t.normalizeKeys(req.OldPartyKeys); err != nil {
+		return reshare.Response{}, err
+	}
+	if req.NewPartyKeys, err = t.normalizeKeys(req.NewPartyKeys); err != nil {
+		return reshare.Response{}, err
+	}
+	if req.NewThreshold != 0 {
+		if err := common.ValidateThreshold(req.NewThreshold, len(req.NewPartyKeys)); err != nil {
+			return reshare.Response{}, err
+		}
+	}
+	inOldCommittee := contains(req.OldPartyKeys, t.localNodePubKey)
+	inNewCommittee := contains(req.NewPartyKeys, t.localNodePubKey)
+	if !inOldCommittee && !inNewCommittee {
+		return reshare.Response{}, errors.New("local node is not in the committees")
+	}
+	msgID, err := t.requestToMsgId(req)
+	if err != nil {
+		return reshare.Response{}, err
+	}
+	startTime := time.Now()
+	t.publishSessionCreated(msgID, monitor.OpReshare)
+	defer func() {
+		monitor.ObserveBlame(monitor.OpReshare, resp.Blame)
+		t.publishSessionFinished(msgID, monitor.OpReshare, resp.Status, resp.Blame, err)
+		t.auditSession(audit.Entry{
+			SessionID:  msgID,
+			Operation:  audit.OpReshare,
+			PoolPubKey: req.PoolPubKey,
+			Parties:    unionKeys(req.OldPartyKeys, req.NewPartyKeys),
+			StartTime:  startTime,
+			Outcome:    resp.Status.String(),
+			Blame:      audit.BlameSummary(resp.Blame),
+		}, err)
+	}()
+
+	// the key state is held till the resharing is done, so the key can't be retired or archived in the middle of it
+	t.keyStateLock.RLock()
+	defer t.keyStateLock.RUnlock()
+	var localState *storage.KeygenLocalState
+	if inOldCommittee {
+		localStateItem, err := t.stateManager.GetLocalState(req.PoolPubKey)
+		if err != nil {
+			return reshare.Response{}, fmt.Errorf("fail to get local keygen state: %w", err)
+		}
+		// moving the key to the new committee is a migration, the retiring key can still do it
+		if err := checkKeyState(localStateItem, true); err != nil {
+			return reshare.Response{}, err
+		}
+		if localStateItem.GetAlgo() != common.ECDSA {
+			return reshare.Response{}, errors.New("only the ECDSA key can be reshared")
+		}
+		localState = &localStateItem
+	}
+	// the new committee needs the pre-parameters like keygen does, they are put back only if the resharing never starts
+	var preParams *bkeygen.LocalPreParams
+	if inNewCommittee {
+		preParams, err = t.preParamPool.Take(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return reshare.Response{Status: common.Cancelled}, nil
+			}
+			return reshare.Response{}, fmt.Errorf("fail to get pre-parameters: %w", err)
+		}
+	}
+
+	reshareInstance := reshare.NewTssReshare(
+		t.p2pCommunication.GetLocalPeerID(),
+		t.conf,
+		t.localNodePubKey,
+		t.p2pCommunication.BroadcastMsgChan,
+		t.stopChan,
+		preParams,
+		msgID,
+		t.stateManager,
+		t.privateKey,
+		t.p2pCommunication)
+	reshareInstance.GetTssCommonStruct().SetEventBus(t.eventBus)
+
+	timeline := reshareInstance.GetTssCommonStruct().GetBlameMgr().GetTimeline()
+	defer t.keepTimeline(msgID, timeline)
+
+	reshareMsgChannel := reshareInstance.GetTssReshareChannels()
+	t.p2pCommunication.SetSubscribe(messages.TSSReshareMsg, msgID, reshareMsgChannel)
+	t.p2pCommunication.SetSubscribe(messages.TSSReshareVerMsg, msgID, reshareMsgChannel)
+	t.p2pCommunication.SetSubscribe(messages.TSSControlMsg, msgID, reshareMsgChannel)
+	t.p2pCommunication.SetSubscribe(messages.TSSTaskDone, msgID, reshareMsgChannel)
+
+	defer t.p2pCommunication.CancelSubscribe(messages.TSSReshareMsg, msgID)
+	defer t.p2pCommunication.CancelSubscribe(messages.TSSReshareVerMsg, msgID)
+	defer t.p2pCommunication.CancelSubscribe(messages.TSSControlMsg, msgID)
+	defer t.p2pCommunication.CancelSubscribe(messages.TSSTaskDone, msgID)
+
+	var releaseCurve func()
+	ctx, releaseCurve, err = t.acquireCurve(ctx, monitor.OpReshare, common.ECDSA, preParams)
+	if err != nil {
+		return reshare.Response{}, err
+	}
+	defer releaseCurve()
+	allKeys := unionKeys(req.OldPartyKeys, req.NewPartyKeys)
+	onlinePeers, err := t.joinParty(ctx, monitor.OpReshare, msgID, allKeys)
+	if err != nil {
+		if preParams != nil {
+			t.preParamPool.Return(preParams)
+		}
+		if ctx.Err() != nil {
+			t.logger.Info().Msg("reshare is cancelled before the party is formed")
+			return reshare.Response{Status: common.Cancelled}, nil
+		}
+		if onlinePeers == nil {
+			t.logger.Error().Err(err).Msg("error before we start join party")
+			return reshare.Response{
+				Status: common.Fail,
+				Blame:  blame.NewBlame(blame.InternalError, []blame.Node{}),
+			}, nil
+		}
+		blameMgr := reshareInstance.GetTssCommonStruct().GetBlameMgr()
+		blameNodes, err := blameMgr.NodeSyncBlame(allKeys, onlinePeers)
+		if err != nil {
+			t.logger.Err(err).Msg("fail to get peers to blame")
+		}
+		t.logger.Error().Err(err).Msgf("fail to form reshare party with online:%v", onlinePeers)
+		return reshare.Response{
+			Status: common.Fail,
+			Blame:  blameNodes,
+		}, nil
+	}
+
+	t.logger.Info().Msg("reshare party formed")
+	timeline.Add(blame.EventPartyFormed, "", "")
+	k, err := reshareInstance.Reshare(ctx, req, localState)
+	blameMgr := reshareInstance.GetTssCommonStruct().GetBlameMgr()
+	if err != nil && ctx.Err() != nil {
+		t.logger.Info().Msg("reshare is cancelled")
+		return reshare.Response{Status: common.Cancelled}, nil
+	}
+	if err != nil {
+		// the failure is told by the status and the blame, so the caller still gets the blame
+		t.logger.Error().Err(err).Msg("err in reshare")
+		return reshare.NewResponse("", "", common.Fail, *blameMgr.GetBlame()), nil
+	}
+
+	newPubKey, addr, err := conversion.GetTssPubKey(k, t.conf.Bech32Prefix)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to get the pool pub key")
+		return reshare.NewResponse("", "", common.Fail, *blameMgr.GetBlame()), nil
+	}
+	return reshare.NewResponse(
+		newPubKey,
+		addr.String(),
+		common.Success,
+		*blameMgr.GetBlame(),
+	), nil
+}
+
+// unionKeys return the keys of both the given lists, the duplicated ones only once
+func unionKeys(a, b []string) []string {
+	result := append([]string{}, a...)
+	for _, el := range b {
+		if !contains(result, el) {
+			result = append(result, el)
+		}
+	}
+	return result
+}
+
+func contains(keys []string, key string) bool {
+	for _, el := range keys {
+		if el == key {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,16 @@
 package tss
 
 import (
+	"context"
+
+	"gitlab.com/thorchain/tss/go-tss/audit"
 	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
 )
 
 // Server define the necessary functionality should be provide by a TSS Server implementation
@@ -12,6 +19,27 @@ type Server interface {
 	Stop()
 	GetLocalPeerID() string
 	Keygen(req keygen.Request) (keygen.Response, error)
+	KeygenWithContext(ctx context.Context, req keygen.Request) (keygen.Response, error)
+	KeygenWithProgress(ctx context.Context, req keygen.Request, listener ProgressListener) (keygen.Response, error)
 	KeySign(req keysign.Request) (keysign.Response, error)
+	KeySignWithContext(ctx context.Context, req keysign.Request) (keysign.Response, error)
+	KeySignWithProgress(ctx context.Context, req keysign.Request, listener ProgressListener) (keysign.Response, error)
+	KeySignBatch(req keysign.BatchRequest) ([]keysign.Response, error)
+	KeySignBatchWithContext(ctx context.Context, req keysign.BatchRequest) ([]keysign.Response, error)
+	Reshare(req reshare.Request) (reshare.Response, error)
+	ReshareWithContext(ctx context.Context, req reshare.Request) (reshare.Response, error)
+	DerivePubKey(req keysign.DeriveRequest) (keysign.DeriveResponse, error)
+	GetPubKeyInfo(poolPubKey, cosmosPrefix string) (conversion.PubKeyInfo, error)
+	CheckParty(ctx context.Context, keys []string) (PartyCheckResponse, error)
+	ListLocalKeys() ([]storage.LocalStateInfo, error)
+	GetLocalKey(pubKey string) (storage.LocalStateInfo, error)
+	SetKeyState(pubKey string, state storage.KeyState) (storage.LocalStateInfo, error)
+	KeygenAsync(req keygen.Request) (string, error)
+	KeySignAsync(req keysign.Request) (string, error)
+	GetJob(id string) (Job, error)
+	CancelJob(id string) (Job, error)
 	GetStatus() common.TssStatus
+	GetAuditEntries(limit int) ([]audit.Entry, error)
+	SubscribeEvents(filter events.Filter) *events.Subscription
+	GetTimeline(sessionID string) (SessionTimeline, error)
 }
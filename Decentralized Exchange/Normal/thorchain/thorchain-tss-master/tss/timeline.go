@@ -0,0 +1,84 @@
+package tss
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+// ErrTimelineNotFound is returned when the timeline of the given session is not kept, or the session is unknown
+var ErrTimelineNotFound = errors.New("timeline not found")
+
+// maxTimelines is how many timelines of the latest sessions are kept for debugging
+const maxTimelines = 256
+
+// SessionTimeline is the full timeline of a tss session
+type SessionTimeline struct {
+	Summary blame.TimelineSummary `json:"summary"`
+	Start   time.Time             `json:"start"`
+	Events  []blame.TimelineEvent `json:"events"`
+}
+
+// timelineStore keeps the timelines of the latest sessions, the oldest one is dropped once it is full
+type timelineStore struct {
+	lock      *sync.Mutex
+	size      int
+	timelines map[string]SessionTimeline
+	order     []string
+}
+
+func newTimelineStore(size int) *timelineStore {
+	return &timelineStore{
+		lock:      &sync.Mutex{},
+		size:      size,
+		timelines: make(map[string]SessionTimeline),
+	}
+}
+
+func (s *timelineStore) add(sessionID string, timeline SessionTimeline) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if _, ok := s.timelines[sessionID]; !ok {
+		s.order = append(s.order, sessionID)
+	}
+	s.timelines[sessionID] = timeline
+	for len(s.order) > s.size {
+		delete(s.timelines, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+func (s *timelineStore) get(sessionID string) (SessionTimeline, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	timeline, ok := s.timelines[sessionID]
+	return timeline, ok
+}
+
+// keepTimeline keep the timeline of the finished session for debugging, and return its summary
+func (t *TssServer) keepTimeline(sessionID string, timeline *blame.Timeline) *blame.TimelineSummary {
+	summary := timeline.Summary(sessionID)
+	if t.timelines != nil {
+		t.timelines.add(sessionID, SessionTimeline{
+			Summary: summary,
+			Start:   timeline.GetStart(),
+			Events:  timeline.GetEvents(),
+		})
+	}
+	t.logger.Debug().Str("session", sessionID).Interface("timeline", summary).Msg("session finished")
+	return &summary
+}
+
+// GetTimeline return the timeline of the given session, only the latest sessions are kept
+func (t *TssServer) GetTimeline(sessionID string) (SessionTimeline, error) {
+	if t.timelines == nil {
+		return SessionTimeline{}, ErrTimelineNotFound
+	}
+	timeline, ok := t.timelines.get(sessionID)
+	if !ok {
+		return SessionTimeline{}, ErrTimelineNotFound
+	}
+	return timeline, nil
+}
@@ -0,0 +1,34 @@
+package tss
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog/log"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/blame"
+)
+
+type TimelineTestSuite struct{}
+
+var _ = Suite(&TimelineTestSuite{})
+
+func (s *TimelineTestSuite) TestKeepTimeline(c *C) {
+	t := &TssServer{
+		logger:    log.Logger,
+		timelines: newTimelineStore(2),
+	}
+	for _, el := range []string{"session1", "session2", "session3"} {
+		timeline := blame.NewTimeline()
+		timeline.Add(blame.EventPartyFormed, "", "")
+		summary := t.keepTimeline(el, timeline)
+		c.Assert(summary.SessionID, Equals, el)
+	}
+	// only the latest sessions are kept
+	_, err := t.GetTimeline("session1")
+	c.Assert(errors.Is(err, ErrTimelineNotFound), Equals, true)
+	timeline, err := t.GetTimeline("session3")
+	c.Assert(err, IsNil)
+	c.Assert(timeline.Summary.SessionID, Equals, "session3")
+	c.Assert(timeline.Events, HasLen, 1)
+}
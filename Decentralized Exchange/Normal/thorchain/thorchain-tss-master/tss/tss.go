@@ -1,28 +1,41 @@
 package tss
 
 import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	bkeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
-	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-peerstore/addr"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	tcrypto "github.com/tendermint/tendermint/crypto"
 
+	"gitlab.com/thorchain/tss/go-tss/audit"
+	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/events"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
 	"gitlab.com/thorchain/tss/go-tss/messages"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
 	"gitlab.com/thorchain/tss/go-tss/p2p"
+	"gitlab.com/thorchain/tss/go-tss/policy"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
 	"gitlab.com/thorchain/tss/go-tss/storage"
 )
 
@@ -33,13 +46,20 @@ type TssServer struct {
 	Status            common.TssStatus
 	p2pCommunication  *p2p.Communication
 	localNodePubKey   string
-	preParams         *bkeygen.LocalPreParams
+	preParamPool      *preParamPool
 	tssKeyGenLocker   *sync.Mutex
 	stopChan          chan struct{}
 	partyCoordinator  *p2p.PartyCoordinator
 	stateManager      storage.LocalStateManager
 	signatureNotifier *keysign.SignatureNotifier
 	privateKey        tcrypto.PrivKey
+	jobManager        *JobManager
+	keyStateLock      *sync.RWMutex
+	policy            *policy.Engine
+	auditLog          *audit.Log
+	timelines         *timelineStore
+	sessions          *sessionTracker
+	eventBus          *events.Bus
 }
 
 // NewTss create a new instance of Tss
@@ -53,7 +73,7 @@ func NewTss(
 	preParams *bkeygen.LocalPreParams,
 	externalIP string,
 ) (*TssServer, error) {
-	pubKey, err := sdk.Bech32ifyPubKey(sdk.Bech32PubKeyTypeAccPub, priKey.PubKey())
+	pubKey, err := conversion.Bech32ifyPubKey(conf.Bech32Prefix, priKey.PubKey())
 	if err != nil {
 		return nil, fmt.Errorf("fail to genearte the key: %w", err)
 	}
@@ -62,6 +82,18 @@ func NewTss(
 	if err != nil {
 		return nil, fmt.Errorf("fail to create file state manager")
 	}
+	rules, err := policy.LoadRules(conf.KeySignPolicyFile)
+	if err != nil {
+		return nil, err
+	}
+	policyEngine, err := policy.NewEngine(rules, conf.Bech32Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create keysign policy engine: %w", err)
+	}
+	auditLog, err := audit.NewLog(filepath.Join(baseFolder, audit.FileName), priKey, conf.Bech32Prefix)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open the audit log: %w", err)
+	}
 
 	var bootstrapPeers addr.AddrList
 	savedPeers, err := stateManager.RetrieveP2PAddresses()
@@ -79,30 +111,31 @@ func NewTss(
 	if err != nil {
 		return nil, fmt.Errorf("fail to create communication layer: %w", err)
 	}
+	priKeyRawBytes, err := conversion.GetPriKeyRawBytes(priKey)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get private key")
+	}
 	// When using the keygen party it is recommended that you pre-compute the
 	// "safe primes" and Paillier secret beforehand because this can take some
-	// time.
-	// This code will generate those parameters using a concurrency limit equal
-	// to the number of available CPU cores.
-	if preParams == nil || !preParams.Validate() {
-		preParams, err = bkeygen.GeneratePreParams(conf.PreParamTimeout)
-		if err != nil {
-			return nil, fmt.Errorf("fail to generate pre parameters: %w", err)
-		}
+	// time. The pool generates those parameters in background, the given
+	// pre-parameters (if any) are added to the pool as well.
+	pool, err := newPreParamPool(conf.PreParamPoolSize, conf.PreParamConcurrency, conf.PreParamTimeout, stateManager, priKeyRawBytes)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create pre-parameter pool: %w", err)
 	}
-	if !preParams.Validate() {
-		return nil, errors.New("invalid preparams")
+	if err := pool.load(); err != nil {
+		return nil, err
 	}
-
-	priKeyRawBytes, err := conversion.GetPriKeyRawBytes(priKey)
-	if err != nil {
-		return nil, fmt.Errorf("fail to get private key")
+	if preParams != nil {
+		pool.add(preParams)
 	}
 	if err := comm.Start(priKeyRawBytes); nil != err {
 		return nil, fmt.Errorf("fail to start p2p network: %w", err)
 	}
-	pc := p2p.NewPartyCoordinator(comm.GetHost(), conf.PartyTimeout)
-	sn := keysign.NewSignatureNotifier(comm.GetHost())
+	// every server has its own bus, so the events of the servers in the same process are not mixed up
+	eventBus := events.NewBus()
+	pc := p2p.NewPartyCoordinator(comm.GetHost(), conf.PartyTimeout, eventBus)
+	sn := keysign.NewSignatureNotifier(comm.GetHost(), eventBus)
 	tssServer := TssServer{
 		conf:   conf,
 		logger: log.With().Str("module", "tss").Logger(),
@@ -111,13 +144,20 @@ func NewTss(
 		},
 		p2pCommunication:  comm,
 		localNodePubKey:   pubKey,
-		preParams:         preParams,
+		preParamPool:      pool,
 		tssKeyGenLocker:   &sync.Mutex{},
 		stopChan:          make(chan struct{}),
 		partyCoordinator:  pc,
 		stateManager:      stateManager,
 		signatureNotifier: sn,
 		privateKey:        priKey,
+		jobManager:        NewJobManager(conf.JobRetention),
+		keyStateLock:      &sync.RWMutex{},
+		policy:            policyEngine,
+		auditLog:          auditLog,
+		timelines:         newTimelineStore(maxTimelines),
+		sessions:          newSessionTracker(),
+		eventBus:          eventBus,
 	}
 
 	return &tssServer, nil
@@ -128,11 +168,13 @@ func (t *TssServer) Start() error {
 	log.Info().Msg("Starting the TSS servers")
 	t.Status.Starttime = time.Now()
 	t.signatureNotifier.Start()
+	t.preParamPool.Start()
 	return nil
 }
 
 // Stop Tss server
 func (t *TssServer) Stop() {
+	t.jobManager.Stop()
 	close(t.stopChan)
 	// stop the p2p and finish the p2p wait group
 	err := t.p2pCommunication.Stop()
@@ -141,35 +183,130 @@ func (t *TssServer) Stop() {
 	}
 	t.signatureNotifier.Stop()
 	t.partyCoordinator.Stop()
+	t.preParamPool.Stop()
 	log.Info().Msg("The Tss and p2p server has been stopped successfully")
 }
 
+// requestToMsgId return the session id of the request, which is the hash of the operation, the pool, the parties,
+// the block height and whatever the parties have to agree on, so the sessions of different pools or the retries at
+// different heights never share the id. The messages of the parties are signed along with the id, which can't be
+// replayed into another session
 func (t *TssServer) requestToMsgId(request interface{}) (string, error) {
-	var dat []byte
+	var fields []string
 	switch value := request.(type) {
 	case keygen.Request:
-		keyAccumulation := ""
-		keys := value.Keys
-		sort.Strings(keys)
-		for _, el := range keys {
-			keyAccumulation += el
+		algo, err := common.GetAlgo(string(value.Algo))
+		if err != nil {
+			return "", err
+		}
+		fields = []string{
+			"keygen",
+			sortedKeys(value.Keys),
+			strconv.Itoa(value.Threshold),
+			string(algo),
+			strconv.FormatInt(value.BlockHeight, 10),
 		}
-		dat = []byte(keyAccumulation)
 	case keysign.Request:
 		msgToSign, err := base64.StdEncoding.DecodeString(value.Message)
 		if err != nil {
 			t.logger.Error().Err(err).Msg("error in decode the keysign req")
 			return "", err
 		}
-		dat = msgToSign
+		// the same message signed with different child keys are different sessions
+		fields = []string{
+			"keysign",
+			value.PoolPubKey,
+			sortedKeys(value.SignerPubKeys),
+			strconv.FormatInt(value.BlockHeight, 10),
+			hex.EncodeToString(msgToSign),
+			value.DerivationPath,
+		}
+	case keysign.BatchRequest:
+		fields = []string{
+			"keysign-batch",
+			value.PoolPubKey,
+			sortedKeys(value.SignerPubKeys),
+			strconv.FormatInt(value.BlockHeight, 10),
+		}
+		for _, msg := range value.Messages {
+			msgToSign, err := base64.StdEncoding.DecodeString(msg)
+			if err != nil {
+				t.logger.Error().Err(err).Msg("error in decode the batch keysign req")
+				return "", err
+			}
+			fields = append(fields, hex.EncodeToString(msgToSign))
+		}
+	case reshare.Request:
+		// the same keys split differently between the committees give a different msgID
+		fields = []string{
+			"reshare",
+			value.PoolPubKey,
+			sortedKeys(value.OldPartyKeys),
+			sortedKeys(value.NewPartyKeys),
+			strconv.Itoa(value.NewThreshold),
+			strconv.FormatInt(value.BlockHeight, 10),
+		}
 	default:
 		t.logger.Error().Msg("unknown request type")
 		return "", errors.New("unknown request type")
 	}
-	return common.MsgToHashString(dat)
+	return common.MsgToHashString([]byte(strings.Join(fields, "|")))
+}
+
+// sortedKeys join the sorted copy of the given keys, the order of the keys in the request doesn't matter
+func sortedKeys(keys []string) string {
+	sorted := append([]string{}, keys...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// normalizeKey return the given pub key, which is in bech32 or hex, in bech32 with the prefix of the server
+func (t *TssServer) normalizeKey(pubKey string) (string, error) {
+	normalized, err := conversion.NormalizePubKey(pubKey, t.conf.Bech32Prefix)
+	if err != nil {
+		return "", fmt.Errorf("fail to parse pub key(%s): %w", pubKey, err)
+	}
+	return normalized, nil
+}
+
+// normalizeKeys return the given pub keys in bech32 with the prefix of the server, the session id, the party order
+// and the local state all work on the keys of the same encoding whatever the callers send
+func (t *TssServer) normalizeKeys(pubKeys []string) ([]string, error) {
+	return conversion.NormalizePubKeys(pubKeys, t.conf.Bech32Prefix)
+}
+
+// newNodeKeyCipher create the AES-GCM cipher of the data the node keeps encrypted on its disk, the key is derived from
+// the given label and the node private key, so every kind of data has its own key
+func newNodeKeyCipher(label string, priKeyRawBytes []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(append([]byte(label), priKeyRawBytes...))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("fail to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("fail to create gcm: %w", err)
+	}
+	return aead, nil
+}
+
+// acquireCurve take the tss-lib curve of the given algorithm for the session before the party is formed, the session
+// is refused with common.ErrCurveBusy while the sessions of the other algorithm run on this node. The given pre
+// parameters, if any, are put back to the pool when the session is refused
+func (t *TssServer) acquireCurve(ctx context.Context, operation string, algo common.Algo, preParams *bkeygen.LocalPreParams) (context.Context, func(), error) {
+	curveCtx, release, err := common.AcquireCurve(ctx, algo)
+	if err != nil {
+		if preParams != nil {
+			t.preParamPool.Return(preParams)
+		}
+		t.logger.Warn().Err(err).Str("operation", operation).Msg("the session is refused")
+		return ctx, nil, fmt.Errorf("fail to take the curve: %w", err)
+	}
+	return curveCtx, release, nil
 }
 
-func (t *TssServer) joinParty(msgID string, keys []string) ([]peer.ID, error) {
+// joinParty form the party of the given operation with the given keys, and record how long it takes
+func (t *TssServer) joinParty(ctx context.Context, operation, msgID string, keys []string) ([]peer.ID, error) {
 	peerIDs, err := conversion.GetPeerIDsFromPubKeys(keys)
 	if err != nil {
 		return nil, fmt.Errorf("fail to convert pub key to peer id: %w", err)
@@ -178,16 +315,80 @@ func (t *TssServer) joinParty(msgID string, keys []string) ([]peer.ID, error) {
 	joinPartyReq := &messages.JoinPartyRequest{
 		ID: msgID,
 	}
-	onlinePeers, err := t.partyCoordinator.JoinPartyWithRetry(joinPartyReq, peerIDs)
+	start := time.Now()
+	onlinePeers, err := t.partyCoordinator.JoinPartyWithRetry(ctx, joinPartyReq, peerIDs)
+	monitor.ObserveJoinParty(operation, time.Since(start), joinPartyResult(ctx, onlinePeers, err))
 	return onlinePeers, err
 }
 
+// joinPartyResult return the result label of the join party, the party isn't formed in time if some peers are online
+// but not all
+func joinPartyResult(ctx context.Context, onlinePeers []peer.ID, err error) string {
+	var refusedErr *p2p.JoinPartyRefusedError
+	switch {
+	case err == nil:
+		return monitor.ResultSuccess
+	case ctx.Err() != nil:
+		return monitor.ResultCancelled
+	case errors.As(err, &refusedErr):
+		return monitor.ResultRefused
+	case onlinePeers != nil:
+		return monitor.ResultTimeout
+	default:
+		return monitor.ResultError
+	}
+}
+
+// refuseParty tell the other signers the local node won't join the keysign of the given message
+func (t *TssServer) refuseParty(msgID string, keys []string) {
+	peerIDs, err := conversion.GetPeerIDsFromPubKeys(keys)
+	if err != nil {
+		t.logger.Error().Err(err).Msg("fail to convert pub key to peer id")
+		return
+	}
+	if err := t.partyCoordinator.RefuseParty(msgID, peerIDs); err != nil {
+		t.logger.Error().Err(err).Msg("fail to refuse the party")
+	}
+}
+
+// refusedBlame blame the peers refusing to join the party
+func (t *TssServer) refusedBlame(peers []peer.ID) blame.Blame {
+	var nodes []blame.Node
+	for _, el := range peers {
+		pk, err := conversion.GetPubKeyFromPeerID(el.String(), t.conf.Bech32Prefix)
+		if err != nil {
+			t.logger.Error().Err(err).Msgf("fail to get the pub key of peer(%s)", el)
+			continue
+		}
+		nodes = append(nodes, blame.Node{Pubkey: pk})
+	}
+	return blame.NewBlame(blame.KeysignRefused, nodes)
+}
+
 // GetLocalPeerID return the local peer
 func (t *TssServer) GetLocalPeerID() string {
 	return t.p2pCommunication.GetLocalPeerID()
 }
 
+// GetJob return the state of the given asynchronous job
+func (t *TssServer) GetJob(id string) (Job, error) {
+	return t.jobManager.Get(id)
+}
+
+// CancelJob abort the given asynchronous job
+func (t *TssServer) CancelJob(id string) (Job, error) {
+	return t.jobManager.Cancel(id)
+}
+
 // GetStatus return the TssStatus
 func (t *TssServer) GetStatus() common.TssStatus {
-	return t.Status
+	status := t.Status
+	status.PreParamPoolDepth = t.preParamPool.Depth()
+	return status
+}
+
+// GetPubKeyInfo return the given pool key in the encodings of the other chains, the Cosmos address uses the given
+// prefix
+func (t *TssServer) GetPubKeyInfo(poolPubKey, cosmosPrefix string) (conversion.PubKeyInfo, error) {
+	return conversion.GetPubKeyInfoFromBech32(poolPubKey, cosmosPrefix)
 }
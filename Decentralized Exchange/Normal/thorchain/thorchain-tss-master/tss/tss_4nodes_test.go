@@ -1,11 +1,15 @@
 package tss
 
 import (
+	"context"
+	"crypto/ecdsa"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"io/ioutil"
+	"math/big"
 	"os"
 	"path"
 	"strconv"
@@ -15,13 +19,21 @@ import (
 	"time"
 
 	btsskeygen "github.com/binance-chain/tss-lib/ecdsa/keygen"
+	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	maddr "github.com/multiformats/go-multiaddr"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
 	. "gopkg.in/check.v1"
 
+	"gitlab.com/thorchain/tss/go-tss/blame"
 	"gitlab.com/thorchain/tss/go-tss/common"
 	"gitlab.com/thorchain/tss/go-tss/conversion"
 	"gitlab.com/thorchain/tss/go-tss/keygen"
 	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/policy"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
 )
 
 const (
@@ -122,6 +134,8 @@ func (s *FourNodeTestSuite) TestKeygenAndKeySign(c *C) {
 	wg.Wait()
 	var poolPubKey string
 	for _, item := range keygenResult {
+		c.Assert(item.PubKeyInfo, NotNil)
+		c.Assert(item.PubKeyInfo.ETH, Not(Equals), "")
 		if len(poolPubKey) == 0 {
 			poolPubKey = item.PubKey
 		} else {
@@ -163,7 +177,15 @@ func (s *FourNodeTestSuite) TestKeygenAndKeySign(c *C) {
 		c.Assert(signature, Equals, item.S+item.R)
 	}
 	payload := base64.StdEncoding.EncodeToString(hash([]byte("helloworld+xyz")))
-	keysignReq = keysign.NewRequest(poolPubKey, payload, testPubKeys[:3])
+	// the signers in raw hex are the same nodes as the bech32 ones
+	var hexSigners []string
+	for _, el := range testPubKeys[:3] {
+		pk, err := conversion.ParsePubKey(el)
+		c.Assert(err, IsNil)
+		secpPk := pk.(secp256k1.PubKeySecp256k1)
+		hexSigners = append(hexSigners, hex.EncodeToString(secpPk[:]))
+	}
+	keysignReq = keysign.NewRequest(poolPubKey, payload, hexSigners)
 	keysignResult1 := make(map[int]keysign.Response)
 	for i := 0; i < partyNum; i++ {
 		wg.Add(1)
@@ -177,18 +199,292 @@ func (s *FourNodeTestSuite) TestKeygenAndKeySign(c *C) {
 		}(i)
 	}
 	wg.Wait()
+	// the node out of the party gets the recovery id along with the signature
 	signature = ""
 	for _, item := range keysignResult1 {
+		c.Assert(item.RecoveryID, NotNil)
+		c.Assert(*item.RecoveryID >= 0 && *item.RecoveryID <= 3, Equals, true)
+		if len(signature) == 0 {
+			signature = item.S + item.R + strconv.Itoa(*item.RecoveryID)
+			continue
+		}
+		c.Assert(signature, Equals, item.S+item.R+strconv.Itoa(*item.RecoveryID))
+	}
+
+	batchReq := keysign.NewBatchRequest(poolPubKey, []string{
+		base64.StdEncoding.EncodeToString(hash([]byte("batch1"))),
+		base64.StdEncoding.EncodeToString(hash([]byte("batch2"))),
+		base64.StdEncoding.EncodeToString(hash([]byte("batch3"))),
+	}, testPubKeys[:3])
+	batchResult := make(map[int][]keysign.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].KeySignBatch(batchReq)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			batchResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	for msgIdx := range batchReq.Messages {
+		signature = ""
+		for _, item := range batchResult {
+			c.Assert(item, HasLen, len(batchReq.Messages))
+			c.Assert(item[msgIdx].Status, Equals, common.Success)
+			if len(signature) == 0 {
+				signature = item[msgIdx].S + item[msgIdx].R
+				continue
+			}
+			c.Assert(signature, Equals, item[msgIdx].S+item[msgIdx].R)
+		}
+	}
+
+	// sign with the child key, the node out of the party verifies the signature with the child key
+	deriveResp, err := s.servers[0].DerivePubKey(keysign.DeriveRequest{PoolPubKey: poolPubKey, DerivationPath: "m/0/1"})
+	c.Assert(err, IsNil)
+	childPubKey, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, deriveResp.PubKey)
+	c.Assert(err, IsNil)
+	childSecpKey, ok := childPubKey.(secp256k1.PubKeySecp256k1)
+	c.Assert(ok, Equals, true)
+	childKey, err := btcec.ParsePubKey(childSecpKey[:], btcec.S256())
+	c.Assert(err, IsNil)
+	msgHash := hash([]byte("helloworld+child"))
+	keysignReq = keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(msgHash), testPubKeys[:3])
+	keysignReq.DerivationPath = "m/0/1"
+	keysignResult2 := make(map[int]keysign.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].KeySign(keysignReq)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keysignResult2[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	c.Assert(keysignResult2, HasLen, partyNum)
+	for _, item := range keysignResult2 {
+		c.Assert(item.Status, Equals, common.Success)
+		r, err := base64.StdEncoding.DecodeString(item.R)
+		c.Assert(err, IsNil)
+		s, err := base64.StdEncoding.DecodeString(item.S)
+		c.Assert(err, IsNil)
+		c.Assert(ecdsa.Verify(childKey.ToECDSA(), msgHash, new(big.Int).SetBytes(r), new(big.Int).SetBytes(s)), Equals, true)
+	}
+
+	// the second node asks for the tx id, it refuses the keysign without it and the other signers give up too
+	policyEngine, err := policy.NewEngine(policy.Rules{
+		Pools: map[string]policy.Rule{
+			poolPubKey: {RequiredMetadata: []string{"tx_id"}},
+		},
+	}, "")
+	c.Assert(err, IsNil)
+	s.servers[1].policy = policyEngine
+	keysignReq = keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(hash([]byte("helloworld+policy"))), testPubKeys[:3])
+	keysignResult3 := make(map[int]keysign.Response)
+	for i := 0; i < partyNum-1; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].KeySign(keysignReq)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keysignResult3[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	for _, item := range keysignResult3 {
+		c.Assert(item.Status, Equals, common.Rejected)
+		c.Assert(item.Blame.BlameNodes, HasLen, 1)
+		c.Assert(item.Blame.BlameNodes[0].Pubkey, Equals, s.servers[1].localNodePubKey)
+	}
+	c.Assert(keysignResult3[0].Blame.FailReason, Equals, blame.KeysignRefused)
+	c.Assert(strings.Contains(keysignResult3[1].Blame.FailReason, "tx_id"), Equals, true)
+}
+
+func (s *FourNodeTestSuite) TestEdDSAKeygenAndKeySign(c *C) {
+	req := keygen.NewRequest(testPubKeys)
+	req.Algo = common.EdDSA
+	wg := sync.WaitGroup{}
+	lock := &sync.Mutex{}
+	keygenResult := make(map[int]keygen.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].Keygen(req)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keygenResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	var poolPubKey string
+	for _, item := range keygenResult {
+		c.Assert(item.Status, Equals, common.Success)
+		if len(poolPubKey) == 0 {
+			poolPubKey = item.PubKey
+		} else {
+			c.Assert(poolPubKey, Equals, item.PubKey)
+		}
+	}
+	pk, err := sdk.GetPubKeyFromBech32(sdk.Bech32PubKeyTypeAccPub, poolPubKey)
+	c.Assert(err, IsNil)
+	_, ok := pk.(ed25519.PubKeyEd25519)
+	c.Assert(ok, Equals, true)
+
+	// the algorithm of the request has to match the pool key
+	msg := []byte("helloworld+eddsa")
+	keysignReqWithErr := keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(msg), testPubKeys[:3])
+	keysignReqWithErr.Algo = common.ECDSA
+	_, err = s.servers[0].KeySign(keysignReqWithErr)
+	c.Assert(err, NotNil)
+
+	// the last node is not in the signers, it gets the signature from the others
+	keysignReq := keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(msg), testPubKeys[:3])
+	keysignResult := make(map[int]keysign.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].KeySign(keysignReq)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keysignResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	for _, item := range keysignResult {
+		c.Assert(item.Status, Equals, common.Success)
+		r, err := base64.StdEncoding.DecodeString(item.R)
+		c.Assert(err, IsNil)
+		s, err := base64.StdEncoding.DecodeString(item.S)
+		c.Assert(err, IsNil)
+		c.Assert(pk.VerifyBytes(msg, append(r, s...)), Equals, true)
+	}
+}
+
+// move the key to a new committee, node 0 leaves the pool, node 3 joins, and node 1 and node 2 are in both committees
+func (s *FourNodeTestSuite) TestKeygenAndReshare(c *C) {
+	req := keygen.NewRequest(testPubKeys)
+	wg := sync.WaitGroup{}
+	lock := &sync.Mutex{}
+	keygenResult := make(map[int]keygen.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].Keygen(req)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keygenResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	poolPubKey := keygenResult[0].PubKey
+	c.Assert(poolPubKey, Not(Equals), "")
+
+	// every member of the new committee needs its own pre-parameters, drop whatever is left in the pool from the earlier tests
+	for i := 1; i < partyNum; i++ {
+		for s.servers[i].preParamPool.pop() != nil {
+		}
+		c.Assert(s.servers[i].preParamPool.add(s.preParams[(i+1)%partyNum]), Equals, true)
+	}
+	newReshareRequest := func() reshare.Request {
+		return reshare.NewRequest(poolPubKey,
+			[]string{s.servers[0].localNodePubKey, s.servers[1].localNodePubKey, s.servers[2].localNodePubKey},
+			[]string{s.servers[1].localNodePubKey, s.servers[2].localNodePubKey, s.servers[3].localNodePubKey},
+			0)
+	}
+	reshareResult := make(map[int]reshare.Response)
+	for i := 0; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].Reshare(newReshareRequest())
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			reshareResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	for _, item := range reshareResult {
+		c.Assert(item.Status, Equals, common.Success)
+		c.Assert(item.PubKey, Equals, poolPubKey)
+	}
+
+	// the new committee signs with the same pool key
+	newKeys := newReshareRequest().NewPartyKeys
+	keysignReq := keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(hash([]byte("helloworld+reshare"))), newKeys)
+	keysignResult := make(map[int]keysign.Response)
+	for i := 1; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			res, err := s.servers[idx].KeySign(keysignReq)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keysignResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	var signature string
+	for _, item := range keysignResult {
+		c.Assert(item.Status, Equals, common.Success)
 		if len(signature) == 0 {
 			signature = item.S + item.R
 			continue
 		}
 		c.Assert(signature, Equals, item.S+item.R)
 	}
-	// make sure we sign
+
+	// node 0 left the pool, its share is wiped and retired, so it can't sign with the pool key any more
+	localState, err := s.servers[0].stateManager.GetLocalState(poolPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(localState.GetState(), Equals, storage.KeyRetired)
+	c.Assert(localState.LocalData.Xi, IsNil)
+	c.Assert(localState.LocalData.LocalPreParams.PaillierSK, IsNil)
+	oldKeys := newReshareRequest().OldPartyKeys
+	_, err = s.servers[0].KeySign(keysign.NewRequest(poolPubKey, base64.StdEncoding.EncodeToString(hash([]byte("helloworld+retired"))), oldKeys))
+	c.Assert(errors.Is(err, ErrKeyRetired), Equals, true)
+	for i := 1; i < partyNum; i++ {
+		localState, err := s.servers[i].stateManager.GetLocalState(poolPubKey)
+		c.Assert(err, IsNil)
+		c.Assert(localState.GetState(), Equals, storage.KeyActive)
+	}
 }
 
 func (s *FourNodeTestSuite) TestFailJoinParty(c *C) {
+	// the nodes are all online, the node that never joins can only be told by the party coordination
+	// the node given twice gets the same result
+	keys := append(append([]string{}, testPubKeys...), testPubKeys[0], "whatever")
+	checkResp, err := s.servers[1].CheckParty(context.Background(), keys)
+	c.Assert(err, IsNil)
+	c.Assert(checkResp.AllReachable, Equals, false)
+	c.Assert(checkResp.Nodes, HasLen, partyNum+2)
+	for i := 0; i < partyNum; i++ {
+		c.Assert(checkResp.Nodes[i].PubKey, Equals, testPubKeys[i])
+		c.Assert(checkResp.Nodes[i].Reachable, Equals, true, Commentf("node(%d): %s", i, checkResp.Nodes[i].Error))
+		c.Assert(checkResp.Nodes[i].Addresses, Not(HasLen), 0)
+	}
+	c.Assert(checkResp.Nodes[partyNum].PubKey, Equals, testPubKeys[0])
+	c.Assert(checkResp.Nodes[partyNum].PeerReachability, DeepEquals, checkResp.Nodes[0].PeerReachability)
+	c.Assert(checkResp.Nodes[partyNum+1].Reachable, Equals, false)
+	c.Assert(checkResp.Nodes[partyNum+1].Error, Not(Equals), "")
+	_, err = s.servers[1].CheckParty(context.Background(), nil)
+	c.Assert(err, NotNil)
+
 	// JoinParty should fail if there is a node that suppose to be in the keygen , but we didn't send request in
 	req := keygen.NewRequest(testPubKeys)
 	wg := sync.WaitGroup{}
@@ -221,6 +517,36 @@ func (s *FourNodeTestSuite) TestFailJoinParty(c *C) {
 	}
 }
 
+func (s *FourNodeTestSuite) TestKeygenWithContextCancel(c *C) {
+	// the first node never joins, so the party can't be formed before the deadline
+	req := keygen.NewRequest(testPubKeys)
+	wg := sync.WaitGroup{}
+	lock := &sync.Mutex{}
+	keygenResult := make(map[int]keygen.Response)
+	start := time.Now()
+	for i := 1; i < partyNum; i++ {
+		wg.Add(1)
+		go func(idx int) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			res, err := s.servers[idx].KeygenWithContext(ctx, req)
+			c.Assert(err, IsNil)
+			lock.Lock()
+			defer lock.Unlock()
+			keygenResult[idx] = res
+		}(i)
+	}
+	wg.Wait()
+	// we should not wait for the party timeout
+	c.Assert(time.Since(start) < 8*time.Second, Equals, true)
+	c.Assert(keygenResult, HasLen, partyNum-1)
+	for _, item := range keygenResult {
+		c.Assert(item.PubKey, Equals, "")
+		c.Assert(item.Status, Equals, common.Cancelled)
+	}
+}
+
 func (s *FourNodeTestSuite) TestBlame(c *C) {
 	s.isBlameTest = true
 	expectedFailNode := testPubKeys[0]
@@ -0,0 +1,174 @@
+package tss
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/tendermint/tendermint/crypto/secp256k1"
+	. "gopkg.in/check.v1"
+
+	"gitlab.com/thorchain/tss/go-tss/common"
+	"gitlab.com/thorchain/tss/go-tss/conversion"
+	"gitlab.com/thorchain/tss/go-tss/keygen"
+	"gitlab.com/thorchain/tss/go-tss/keysign"
+	"gitlab.com/thorchain/tss/go-tss/monitor"
+	"gitlab.com/thorchain/tss/go-tss/reshare"
+	"gitlab.com/thorchain/tss/go-tss/storage"
+)
+
+type TssServerTestSuite struct{}
+
+var _ = Suite(&TssServerTestSuite{})
+
+func (s *TssServerTestSuite) TestRequestToMsgId(c *C) {
+	t := &TssServer{logger: log.Logger}
+	msgIDs := make(map[string]bool)
+	assertNewMsgID := func(req interface{}) string {
+		msgID, err := t.requestToMsgId(req)
+		c.Assert(err, IsNil)
+		c.Assert(msgIDs[msgID], Equals, false)
+		msgIDs[msgID] = true
+		return msgID
+	}
+
+	keygenReq := keygen.NewRequest([]string{testPubKeys[1], testPubKeys[0], testPubKeys[2]})
+	keygenMsgID := assertNewMsgID(keygenReq)
+	// the order of the keys doesn't matter, and the caller's keys are left as they are
+	sameKeygenReq := keygen.NewRequest([]string{testPubKeys[2], testPubKeys[1], testPubKeys[0]})
+	msgID, err := t.requestToMsgId(sameKeygenReq)
+	c.Assert(err, IsNil)
+	c.Assert(msgID, Equals, keygenMsgID)
+	c.Assert(sameKeygenReq.Keys[0], Equals, testPubKeys[2])
+	// ECDSA is the default algorithm
+	sameKeygenReq.Algo = common.ECDSA
+	msgID, err = t.requestToMsgId(sameKeygenReq)
+	c.Assert(err, IsNil)
+	c.Assert(msgID, Equals, keygenMsgID)
+	// the retry at another height
+	keygenReq.BlockHeight = 100
+	assertNewMsgID(keygenReq)
+	keygenReq.Threshold = 1
+	assertNewMsgID(keygenReq)
+	keygenReq.Algo = common.EdDSA
+	assertNewMsgID(keygenReq)
+	keygenReq.Algo = "whatever"
+	_, err = t.requestToMsgId(keygenReq)
+	c.Assert(err, NotNil)
+
+	msg := base64.StdEncoding.EncodeToString([]byte("helloworld"))
+	keysignReq := keysign.NewRequest(testPubKeys[0], msg, testPubKeys[:3])
+	assertNewMsgID(keysignReq)
+	// the same message signed by another pool
+	keysignReq.PoolPubKey = testPubKeys[1]
+	assertNewMsgID(keysignReq)
+	keysignReq.SignerPubKeys = testPubKeys[1:]
+	assertNewMsgID(keysignReq)
+	keysignReq.BlockHeight = 100
+	assertNewMsgID(keysignReq)
+	keysignReq.DerivationPath = "m/0"
+	assertNewMsgID(keysignReq)
+	keysignReq.Message = "invalid base64"
+	_, err = t.requestToMsgId(keysignReq)
+	c.Assert(err, NotNil)
+
+	batchReq := keysign.NewBatchRequest(testPubKeys[0], []string{msg}, testPubKeys[:3])
+	assertNewMsgID(batchReq)
+	batchReq.BlockHeight = 100
+	assertNewMsgID(batchReq)
+
+	reshareReq := reshare.NewRequest(testPubKeys[0], testPubKeys[:3], testPubKeys[1:], 0)
+	assertNewMsgID(reshareReq)
+	// the same keys split differently between the committees
+	reshareReq = reshare.NewRequest(testPubKeys[0], testPubKeys[:2], testPubKeys, 0)
+	assertNewMsgID(reshareReq)
+	reshareReq.BlockHeight = 100
+	assertNewMsgID(reshareReq)
+
+	_, err = t.requestToMsgId("whatever")
+	c.Assert(err, NotNil)
+}
+
+func (s *TssServerTestSuite) TestReshareWithContextCancelled(c *C) {
+	t := &TssServer{logger: log.Logger, tssKeyGenLocker: &sync.Mutex{}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp, err := t.ReshareWithContext(ctx, reshare.NewRequest(testPubKeys[0], testPubKeys[:3], testPubKeys[1:], 0))
+	c.Assert(err, IsNil)
+	c.Assert(resp.Status, Equals, common.Cancelled)
+}
+
+func (s *TssServerTestSuite) TestKeySignBatchWithContextCancelled(c *C) {
+	t := &TssServer{logger: log.Logger}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resps, err := t.KeySignBatchWithContext(ctx, keysign.BatchRequest{
+		PoolPubKey:    testPubKeys[0],
+		Messages:      []string{"aGVsbG8=", "d29ybGQ="},
+		SignerPubKeys: testPubKeys[:3],
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resps, HasLen, 2)
+	for _, el := range resps {
+		c.Assert(el.Status, Equals, common.Cancelled)
+	}
+}
+
+func (s *TssServerTestSuite) TestAcquireCurveRefused(c *C) {
+	pool, err := newPreParamPool(0, 1, time.Minute, &storage.MockLocalStateManager{}, []byte("hello"))
+	c.Assert(err, IsNil)
+	t := &TssServer{logger: log.Logger, preParamPool: pool}
+	_, release, err := common.AcquireCurve(context.Background(), common.EdDSA)
+	c.Assert(err, IsNil)
+	// the ECDSA session is refused up front and its pre-parameters are put back
+	preParams := getPreparams(c)
+	_, _, err = t.acquireCurve(context.Background(), monitor.OpKeygen, common.ECDSA, preParams[0])
+	c.Assert(errors.Is(err, common.ErrCurveBusy), Equals, true)
+	c.Assert(pool.Depth(), Equals, 1)
+	release()
+
+	ctx, release, err := t.acquireCurve(context.Background(), monitor.OpKeygen, common.ECDSA, preParams[0])
+	c.Assert(err, IsNil)
+	c.Assert(ctx, NotNil)
+	release()
+}
+
+func (s *TssServerTestSuite) TestNormalizeKeys(c *C) {
+	t := &TssServer{logger: log.Logger}
+	pk, err := conversion.ParsePubKey(testPubKeys[0])
+	c.Assert(err, IsNil)
+	secpPk := pk.(secp256k1.PubKeySecp256k1)
+	hexPubKey := hex.EncodeToString(secpPk[:])
+
+	keys, err := t.normalizeKeys([]string{hexPubKey, testPubKeys[1]})
+	c.Assert(err, IsNil)
+	c.Assert(keys, DeepEquals, []string{testPubKeys[0], testPubKeys[1]})
+	// the same request in either encoding is the same session
+	keysignReq := keysign.NewRequest(testPubKeys[0], base64.StdEncoding.EncodeToString([]byte("helloworld")), keys)
+	msgID, err := t.requestToMsgId(keysignReq)
+	c.Assert(err, IsNil)
+	keysignReq.SignerPubKeys = []string{testPubKeys[0], testPubKeys[1]}
+	sameMsgID, err := t.requestToMsgId(keysignReq)
+	c.Assert(err, IsNil)
+	c.Assert(sameMsgID, Equals, msgID)
+
+	t.conf.Bech32Prefix = "tthor"
+	poolPubKey, err := t.normalizeKey(hexPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(strings.HasPrefix(poolPubKey, "tthorpub1"), Equals, true)
+	samePoolPubKey, err := t.normalizeKey(poolPubKey)
+	c.Assert(err, IsNil)
+	c.Assert(samePoolPubKey, Equals, poolPubKey)
+	// the thorpub key is not a key of the tthor server
+	_, err = t.normalizeKey(testPubKeys[0])
+	c.Assert(err, NotNil)
+	_, err = t.normalizeKey("")
+	c.Assert(err, NotNil)
+	_, err = t.normalizeKeys([]string{testPubKeys[0], "whatever"})
+	c.Assert(err, NotNil)
+}